@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	compressionGZIP = "gzip"
+	compressionZSTD = "zstd"
+)
+
+// compressFunc compresses a single encoded message.
+type compressFunc func(src []byte) ([]byte, error)
+
+var compressFuncs = map[string]compressFunc{
+	"":              noneCompress,
+	compressionGZIP: gzipCompress,
+	compressionZSTD: zstdCompress,
+}
+
+// zstdEncoder is shared since a nil-writer encoder is safe for concurrent EncodeAll calls.
+// It is created on first use so that only exporters compressing with zstd pay for it.
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdErr     error
+)
+
+func noneCompress(src []byte) ([]byte, error) {
+	return src, nil
+}
+
+func gzipCompress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func zstdCompress(src []byte) ([]byte, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+	})
+	if zstdErr != nil {
+		return nil, zstdErr
+	}
+	return zstdEncoder.EncodeAll(src, make([]byte, 0, len(src))), nil
+}
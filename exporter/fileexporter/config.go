@@ -16,16 +16,69 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
 
+const defaultMaxOpenFiles = 100
+
 // Config defines configuration for file exporter.
 type Config struct {
 	config.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	// Path of the file to write to. Path is relative to current directory.
+	// The path can contain {attribute} placeholders, in which case every resource
+	// is written to the file named after the values of its resource attributes.
 	Path string `mapstructure:"path"`
+
+	// MaxOpenFiles is the maximum number of files kept open when Path contains
+	// placeholders. The least recently used file is closed when the limit is
+	// reached. It defaults to 100.
+	MaxOpenFiles int `mapstructure:"max_open_files"`
+
+	// Rotation defines an option about rotation of telemetry files. When nil
+	// the exporter keeps appending to Path forever.
+	Rotation *Rotation `mapstructure:"rotation"`
+
+	// FormatType define the data format of encoded telemetry data.
+	// Options:
+	// - json[default]:  OTLP json bytes, one message per line.
+	// - proto:  OTLP binary protobuf bytes, each message prefixed with its length.
+	FormatType string `mapstructure:"format"`
+
+	// Compression Codec used to compress each message written to the file.
+	// Options: gzip, zstd. The default is no compression.
+	Compression string `mapstructure:"compression"`
+}
+
+// Rotation an option to rolling log files
+type Rotation struct {
+	// MaxMegabytes is the maximum size in megabytes of the file before it gets
+	// rotated. It defaults to 100 megabytes.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// MaxDays is the maximum number of days to retain old log files based on the
+	// timestamp encoded in their filename. Note that a day is defined as 24
+	// hours and may not exactly correspond to calendar days due to daylight
+	// savings, leap seconds, etc. The default is not to remove old log files
+	// based on age.
+	MaxDays int `mapstructure:"max_days"`
+
+	// MaxBackups is the maximum number of old log files to retain. The default
+	// is to retain all old log files (though MaxDays may still cause them to get
+	// deleted.)
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// LocalTime determines if the time used for formatting the timestamps in
+	// backup files is the computer's local time. The default is to use UTC
+	// time.
+	LocalTime bool `mapstructure:"localtime"`
+
+	// Interval is the period after which the file is rotated regardless of its
+	// size. The default is not to rotate on a schedule.
+	Interval time.Duration `mapstructure:"interval"`
 }
 
 var _ config.Exporter = (*Config)(nil)
@@ -35,6 +88,32 @@ func (cfg *Config) Validate() error {
 	if cfg.Path == "" {
 		return errors.New("path must be non-empty")
 	}
+	if _, err := parsePathTemplate(cfg.Path); err != nil {
+		return err
+	}
+	if cfg.MaxOpenFiles < 0 {
+		return errors.New("max_open_files must be non-negative")
+	}
+	if _, ok := tracesMarshalers[cfg.FormatType]; !ok {
+		return fmt.Errorf("format type %q is not supported", cfg.FormatType)
+	}
+	if _, ok := compressFuncs[cfg.Compression]; !ok {
+		return fmt.Errorf("compression %q is not supported", cfg.Compression)
+	}
+	if cfg.Rotation != nil {
+		if cfg.Rotation.MaxMegabytes < 0 {
+			return errors.New("rotation max_megabytes must be non-negative")
+		}
+		if cfg.Rotation.MaxDays < 0 {
+			return errors.New("rotation max_days must be non-negative")
+		}
+		if cfg.Rotation.MaxBackups < 0 {
+			return errors.New("rotation max_backups must be non-negative")
+		}
+		if cfg.Rotation.Interval < 0 {
+			return errors.New("rotation interval must be non-negative")
+		}
+	}
 
 	return nil
 }
@@ -17,6 +17,7 @@ package fileexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,5 +44,55 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExporterSettings: config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "2")),
 			Path:             "./filename.json",
+			FormatType:       formatTypeJSON,
 		})
+
+	e2 := cfg.Exporters[config.NewComponentIDWithName(typeStr, "3")]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "3")),
+			Path:             "./filename.json",
+			FormatType:       formatTypeProto,
+			Compression:      compressionZSTD,
+			Rotation: &Rotation{
+				MaxMegabytes: 10,
+				MaxDays:      3,
+				MaxBackups:   3,
+				LocalTime:    true,
+				Interval:     time.Hour,
+			},
+		})
+}
+
+func TestValidateRotation(t *testing.T) {
+	cfg := &Config{Path: "./filename.json", FormatType: formatTypeJSON, Rotation: &Rotation{MaxBackups: -1}}
+	assert.EqualError(t, cfg.Validate(), "rotation max_backups must be non-negative")
+
+	cfg.Rotation = &Rotation{Interval: -time.Second}
+	assert.EqualError(t, cfg.Validate(), "rotation interval must be non-negative")
+
+	cfg.Rotation = &Rotation{MaxMegabytes: 1, MaxDays: 1, MaxBackups: 1, Interval: time.Minute}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidatePathTemplate(t *testing.T) {
+	cfg := &Config{Path: "./{service.name.json", FormatType: formatTypeJSON}
+	assert.Equal(t, errUnclosedPlaceholder, cfg.Validate())
+
+	cfg = &Config{Path: "./{service.name}.json", FormatType: formatTypeJSON, MaxOpenFiles: -1}
+	assert.EqualError(t, cfg.Validate(), "max_open_files must be non-negative")
+
+	cfg = &Config{Path: "./{service.name}.json", FormatType: formatTypeJSON, MaxOpenFiles: 10}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidateFormatAndCompression(t *testing.T) {
+	cfg := &Config{Path: "./filename.json", FormatType: "xml"}
+	assert.EqualError(t, cfg.Validate(), "format type \"xml\" is not supported")
+
+	cfg = &Config{Path: "./filename.json", FormatType: formatTypeJSON, Compression: "lz4"}
+	assert.EqualError(t, cfg.Validate(), "compression \"lz4\" is not supported")
+
+	cfg = &Config{Path: "./filename.json", FormatType: formatTypeProto, Compression: compressionGZIP}
+	assert.NoError(t, cfg.Validate())
 }
@@ -16,28 +16,73 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"encoding/binary"
 	"io"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
+	lru "github.com/hashicorp/golang-lru/simplelru"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// Marshaler configuration used for marhsaling Protobuf to JSON.
-var tracesMarshaler = ptrace.NewJSONMarshaler()
-var metricsMarshaler = pmetric.NewJSONMarshaler()
-var logsMarshaler = plog.NewJSONMarshaler()
-
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON or Protobuf binary format.
 type fileExporter struct {
-	path  string
-	file  io.WriteCloser
-	mutex sync.Mutex
+	path       string
+	rotation   *Rotation
+	marshaller *marshaller
+	exporter   exportFunc
+	file       io.WriteCloser
+	mutex      sync.Mutex
+
+	// template is set when path contains resource attribute placeholders, in
+	// which case files holds the most recently used files instead of file.
+	template     *pathTemplate
+	maxOpenFiles int
+	files        *lru.LRU
+	// written holds the templated paths written since the last interval rotation,
+	// including the ones evicted from files since then.
+	written map[string]struct{}
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// exportFunc writes a single encoded message to w.
+type exportFunc func(w io.Writer, buf []byte) error
+
+func newFileExporter(conf *Config) *fileExporter {
+	formatType := conf.FormatType
+	if formatType == "" {
+		formatType = formatTypeJSON
+	}
+	m := newMarshaller(formatType, conf.Compression)
+	exporter := exportMessageAsLine
+	if m.lengthPrefixed() {
+		exporter = exportMessageAsBuffer
+	}
+	// The path has already been validated, so the error can only be nil here.
+	template, _ := parsePathTemplate(conf.Path)
+	maxOpenFiles := conf.MaxOpenFiles
+	if maxOpenFiles <= 0 {
+		maxOpenFiles = defaultMaxOpenFiles
+	}
+	return &fileExporter{
+		path:         conf.Path,
+		rotation:     conf.Rotation,
+		marshaller:   m,
+		exporter:     exporter,
+		template:     template,
+		maxOpenFiles: maxOpenFiles,
+	}
 }
 
 func (e *fileExporter) Capabilities() consumer.Capabilities {
@@ -45,49 +90,254 @@ func (e *fileExporter) Capabilities() consumer.Capabilities {
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
-	buf, err := tracesMarshaler.MarshalTraces(td)
-	if err != nil {
-		return err
+	if e.template == nil {
+		buf, err := e.marshaller.marshalTraces(td)
+		if err != nil {
+			return err
+		}
+		return e.export("", buf)
 	}
-	return exportMessageAsLine(e, buf)
+	var errs error
+	paths, groups := e.template.groupTraces(td)
+	for _, path := range paths {
+		buf, err := e.marshaller.marshalTraces(groups[path])
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		errs = multierr.Append(errs, e.export(path, buf))
+	}
+	return errs
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
-	buf, err := metricsMarshaler.MarshalMetrics(md)
-	if err != nil {
-		return err
+	if e.template == nil {
+		buf, err := e.marshaller.marshalMetrics(md)
+		if err != nil {
+			return err
+		}
+		return e.export("", buf)
+	}
+	var errs error
+	paths, groups := e.template.groupMetrics(md)
+	for _, path := range paths {
+		buf, err := e.marshaller.marshalMetrics(groups[path])
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		errs = multierr.Append(errs, e.export(path, buf))
 	}
-	return exportMessageAsLine(e, buf)
+	return errs
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld plog.Logs) error {
-	buf, err := logsMarshaler.MarshalLogs(ld)
-	if err != nil {
-		return err
+	if e.template == nil {
+		buf, err := e.marshaller.marshalLogs(ld)
+		if err != nil {
+			return err
+		}
+		return e.export("", buf)
 	}
-	return exportMessageAsLine(e, buf)
+	var errs error
+	paths, groups := e.template.groupLogs(ld)
+	for _, path := range paths {
+		buf, err := e.marshaller.marshalLogs(groups[path])
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			continue
+		}
+		errs = multierr.Append(errs, e.export(path, buf))
+	}
+	return errs
 }
 
-func exportMessageAsLine(e *fileExporter, buf []byte) error {
+// export writes buf to the file at path, or to the single configured file when
+// the path is not templated.
+func (e *fileExporter) export(path string, buf []byte) error {
 	// Ensure only one write operation happens at a time.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if _, err := e.file.Write(buf); err != nil {
+	if e.template == nil {
+		return e.exporter(e.file, buf)
+	}
+	w, err := e.templatedFile(path)
+	if err != nil {
+		return err
+	}
+	if e.written != nil {
+		e.written[path] = struct{}{}
+	}
+	return e.exporter(w, buf)
+}
+
+// templatedFile returns the open file for path, opening it and evicting the
+// least recently used file if needed. Must be called with the mutex held.
+func (e *fileExporter) templatedFile(path string) (io.WriteCloser, error) {
+	if w, ok := e.files.Get(path); ok {
+		return w.(io.WriteCloser), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	// Files can be closed and reopened when evicted, so never truncate them.
+	w, err := e.openFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+	if err != nil {
+		return nil, err
+	}
+	e.files.Add(path, w)
+	return w, nil
+}
+
+// openFile opens path for writing, wrapping it in a rotating writer if enabled.
+func (e *fileExporter) openFile(path string, flag int) (io.WriteCloser, error) {
+	if e.rotation == nil {
+		return os.OpenFile(path, flag, 0600)
+	}
+	// The rotating writer always appends, so truncate the file first to keep
+	// the same behavior as without rotation.
+	if flag&os.O_TRUNC != 0 {
+		f, err := os.OpenFile(path, flag, 0600)
+		if err != nil {
+			return nil, err
+		}
+		if err = f.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    e.rotation.MaxMegabytes,
+		MaxAge:     e.rotation.MaxDays,
+		MaxBackups: e.rotation.MaxBackups,
+		LocalTime:  e.rotation.LocalTime,
+	}, nil
+}
+
+func exportMessageAsLine(w io.Writer, buf []byte) error {
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
 		return err
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
+	return nil
+}
+
+// exportMessageAsBuffer writes the message prefixed with its length as a 4 byte
+// big-endian unsigned integer, which is required for binary or compressed data.
+func exportMessageAsBuffer(w io.Writer, buf []byte) error {
+	data := make([]byte, 4, 4+len(buf))
+	binary.BigEndian.PutUint32(data, uint32(len(buf)))
+	if _, err := w.Write(append(data, buf...)); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (e *fileExporter) Start(context.Context, component.Host) error {
-	var err error
-	e.file, err = os.OpenFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
-	return err
+	if e.template != nil {
+		var err error
+		e.files, err = lru.NewLRU(e.maxOpenFiles, func(_ interface{}, value interface{}) {
+			// Nothing can be done about a failed close of an evicted file, the data
+			// already written has been flushed to the OS by then.
+			_ = value.(io.Closer).Close()
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if e.file, err = e.openFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC); err != nil {
+			return err
+		}
+	}
+
+	if e.rotation != nil && e.rotation.Interval > 0 {
+		if e.template != nil {
+			e.written = make(map[string]struct{})
+		}
+		e.stopCh = make(chan struct{})
+		e.wg.Add(1)
+		go e.rotateEvery(e.rotation.Interval)
+	}
+	return nil
+}
+
+// rotateEvery rotates the open files on a fixed schedule until the exporter is shut down.
+func (e *fileExporter) rotateEvery(interval time.Duration) {
+	defer e.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.rotate()
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
+func (e *fileExporter) rotate() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if logger, ok := e.file.(*lumberjack.Logger); ok {
+		rotateIfNotEmpty(logger)
+	}
+	// Templated files are rotated by path, since a file written during the interval
+	// may have been evicted and closed since then.
+	for path := range e.written {
+		if w, ok := e.files.Peek(path); ok {
+			if logger, ok := w.(*lumberjack.Logger); ok {
+				rotateIfNotEmpty(logger)
+			}
+			continue
+		}
+		w, err := e.openFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND)
+		if err != nil {
+			continue
+		}
+		if logger, ok := w.(*lumberjack.Logger); ok {
+			rotateIfNotEmpty(logger)
+		}
+		_ = w.Close()
+	}
+	e.written = make(map[string]struct{})
+}
+
+// rotateIfNotEmpty rotates the file of logger unless nothing was written to it,
+// so that an idle exporter does not leave an empty backup behind on every interval.
+func rotateIfNotEmpty(logger *lumberjack.Logger) {
+	if info, err := os.Stat(logger.Filename); err != nil || info.Size() == 0 {
+		return
+	}
+	// An error here only means the backup could not be created, the logger
+	// keeps writing to the current file so there is nothing else to do.
+	_ = logger.Rotate()
 }
 
 // Shutdown stops the exporter and is invoked during shutdown.
 func (e *fileExporter) Shutdown(context.Context) error {
+	if e.stopCh != nil {
+		close(e.stopCh)
+		e.wg.Wait()
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.files != nil {
+		var errs error
+		for _, key := range e.files.Keys() {
+			if w, ok := e.files.Peek(key); ok {
+				errs = multierr.Append(errs, w.(io.Closer).Close())
+			}
+		}
+		// The files are already closed, drop them without calling the eviction callback.
+		e.files, _ = lru.NewLRU(e.maxOpenFiles, nil)
+		return errs
+	}
+	if e.file == nil {
+		return nil
+	}
 	return e.file.Close()
 }
@@ -14,11 +14,18 @@
 package fileexporter
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -30,7 +37,7 @@ import (
 )
 
 func TestFileTracesExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	td := testdata.GenerateTracesTwoSpansSameResource()
@@ -48,7 +55,8 @@ func TestFileTracesExporter(t *testing.T) {
 
 func TestFileTracesExporterError(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
+	fe.file = mf
 	require.NotNil(t, fe)
 
 	td := testdata.GenerateTracesTwoSpansSameResource()
@@ -58,7 +66,7 @@ func TestFileTracesExporterError(t *testing.T) {
 }
 
 func TestFileMetricsExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	md := testdata.GenerateMetricsTwoMetrics()
@@ -76,7 +84,8 @@ func TestFileMetricsExporter(t *testing.T) {
 
 func TestFileMetricsExporterError(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
+	fe.file = mf
 	require.NotNil(t, fe)
 
 	md := testdata.GenerateMetricsTwoMetrics()
@@ -86,7 +95,7 @@ func TestFileMetricsExporterError(t *testing.T) {
 }
 
 func TestFileLogsExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
@@ -104,7 +113,8 @@ func TestFileLogsExporter(t *testing.T) {
 
 func TestFileLogsExporterErrors(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
+	fe.file = mf
 	require.NotNil(t, fe)
 
 	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
@@ -113,6 +123,194 @@ func TestFileLogsExporterErrors(t *testing.T) {
 	assert.NoError(t, fe.Shutdown(context.Background()))
 }
 
+func TestFileExporterFormatAndCompression(t *testing.T) {
+	tests := []struct {
+		formatType  string
+		compression string
+		unmarshaler ptrace.Unmarshaler
+		decompress  func(t *testing.T, buf []byte) []byte
+	}{
+		{
+			formatType:  formatTypeProto,
+			unmarshaler: ptrace.NewProtoUnmarshaler(),
+			decompress:  func(t *testing.T, buf []byte) []byte { return buf },
+		},
+		{
+			formatType:  formatTypeJSON,
+			compression: compressionGZIP,
+			unmarshaler: ptrace.NewJSONUnmarshaler(),
+			decompress: func(t *testing.T, buf []byte) []byte {
+				r, err := gzip.NewReader(bytes.NewReader(buf))
+				require.NoError(t, err)
+				out, err := io.ReadAll(r)
+				require.NoError(t, err)
+				return out
+			},
+		},
+		{
+			formatType:  formatTypeProto,
+			compression: compressionZSTD,
+			unmarshaler: ptrace.NewProtoUnmarshaler(),
+			decompress: func(t *testing.T, buf []byte) []byte {
+				dec, err := zstd.NewReader(nil)
+				require.NoError(t, err)
+				defer dec.Close()
+				out, err := dec.DecodeAll(buf, nil)
+				require.NoError(t, err)
+				return out
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.formatType+"_"+tt.compression, func(t *testing.T) {
+			fe := newFileExporter(&Config{
+				Path:        tempFileName(t),
+				FormatType:  tt.formatType,
+				Compression: tt.compression,
+			})
+
+			td := testdata.GenerateTracesTwoSpansSameResource()
+			require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+			require.NoError(t, fe.ConsumeTraces(context.Background(), td))
+			require.NoError(t, fe.ConsumeTraces(context.Background(), td))
+			require.NoError(t, fe.Shutdown(context.Background()))
+
+			f, err := os.Open(fe.path)
+			require.NoError(t, err)
+			defer f.Close()
+			for i := 0; i < 2; i++ {
+				size := make([]byte, 4)
+				_, err = io.ReadFull(f, size)
+				require.NoError(t, err)
+				buf := make([]byte, binary.BigEndian.Uint32(size))
+				_, err = io.ReadFull(f, buf)
+				require.NoError(t, err)
+				got, err := tt.unmarshaler.UnmarshalTraces(tt.decompress(t, buf))
+				require.NoError(t, err)
+				assert.EqualValues(t, td, got)
+			}
+			_, err = f.Read(make([]byte, 1))
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestFileExporterPathTemplate(t *testing.T) {
+	dir := t.TempDir()
+	fe := newFileExporter(&Config{
+		Path:         filepath.Join(dir, "{service.name}", "logs.json"),
+		MaxOpenFiles: 1,
+	})
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"checkout", "cart", "checkout"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().InsertString("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal(service)
+	}
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	// Only one file can be open at a time, so this reopens the checkout file.
+	require.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	unmarshaler := plog.NewJSONUnmarshaler()
+	for service, records := range map[string]int{"checkout": 2, "cart": 1} {
+		buf, err := os.ReadFile(filepath.Join(dir, service, "logs.json"))
+		require.NoError(t, err)
+		lines := bytes.Split(bytes.TrimSpace(buf), []byte("\n"))
+		require.Len(t, lines, 2)
+		for _, line := range lines {
+			got, err := unmarshaler.UnmarshalLogs(line)
+			require.NoError(t, err)
+			assert.Equal(t, records, got.LogRecordCount())
+			for i := 0; i < got.ResourceLogs().Len(); i++ {
+				v, ok := got.ResourceLogs().At(i).Resource().Attributes().Get("service.name")
+				require.True(t, ok)
+				assert.Equal(t, service, v.StringVal())
+			}
+		}
+	}
+}
+
+func TestFileExporterRotateOnInterval(t *testing.T) {
+	dir := t.TempDir()
+	fe := newFileExporter(&Config{
+		Path:     filepath.Join(dir, "telemetry.json"),
+		Rotation: &Rotation{Interval: 50 * time.Millisecond},
+	})
+
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, fe.ConsumeTraces(context.Background(), td))
+	require.Eventually(t, func() bool {
+		backups, err := filepath.Glob(filepath.Join(dir, "telemetry-*.json"))
+		return err == nil && len(backups) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	// Only the rotation after the write creates a backup, the later ones find an empty file.
+	backups, err := filepath.Glob(filepath.Join(dir, "telemetry-*.json"))
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	buf, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	got, err := ptrace.NewJSONUnmarshaler().UnmarshalTraces(buf)
+	require.NoError(t, err)
+	assert.EqualValues(t, td, got)
+}
+
+func TestFileExporterRotatesEvictedFiles(t *testing.T) {
+	dir := t.TempDir()
+	fe := newFileExporter(&Config{
+		Path:         filepath.Join(dir, "{service.name}", "logs.json"),
+		MaxOpenFiles: 1,
+		Rotation:     &Rotation{Interval: time.Hour},
+	})
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"checkout", "cart"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().InsertString("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal(service)
+	}
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	// Only one file can be open at a time, so the checkout file is evicted by the cart one.
+	require.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	fe.rotate()
+	require.NoError(t, fe.Shutdown(context.Background()))
+
+	for _, service := range []string{"checkout", "cart"} {
+		backups, err := filepath.Glob(filepath.Join(dir, service, "logs-*.json"))
+		require.NoError(t, err)
+		assert.Len(t, backups, 1, service)
+	}
+}
+
+func TestFileExporterTruncatesOnStart(t *testing.T) {
+	for name, rotation := range map[string]*Rotation{
+		"without_rotation": nil,
+		"with_rotation":    {MaxMegabytes: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "telemetry.json")
+			require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0600))
+
+			fe := newFileExporter(&Config{Path: path, Rotation: rotation})
+			require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+			ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+			require.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+			require.NoError(t, fe.Shutdown(context.Background()))
+
+			buf, err := os.ReadFile(path)
+			require.NoError(t, err)
+			got, err := plog.NewJSONUnmarshaler().UnmarshalLogs(buf)
+			require.NoError(t, err)
+			assert.EqualValues(t, ld, got)
+		})
+	}
+}
+
 // tempFileName provides a temporary file name for testing.
 func tempFileName(t *testing.T) string {
 	tmpfile, err := os.CreateTemp("", "*.json")
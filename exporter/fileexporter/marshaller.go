@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+)
+
+// Marshalers for every supported format, keyed by the value of the format setting.
+var tracesMarshalers = map[string]ptrace.Marshaler{
+	formatTypeJSON:  ptrace.NewJSONMarshaler(),
+	formatTypeProto: ptrace.NewProtoMarshaler(),
+}
+var metricsMarshalers = map[string]pmetric.Marshaler{
+	formatTypeJSON:  pmetric.NewJSONMarshaler(),
+	formatTypeProto: pmetric.NewProtoMarshaler(),
+}
+var logsMarshalers = map[string]plog.Marshaler{
+	formatTypeJSON:  plog.NewJSONMarshaler(),
+	formatTypeProto: plog.NewProtoMarshaler(),
+}
+
+// marshaller encodes telemetry data in the configured format.
+type marshaller struct {
+	tracesMarshaler  ptrace.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	logsMarshaler    plog.Marshaler
+
+	compression string
+	compressor  compressFunc
+
+	formatType string
+}
+
+func newMarshaller(formatType, compression string) *marshaller {
+	return &marshaller{
+		tracesMarshaler:  tracesMarshalers[formatType],
+		metricsMarshaler: metricsMarshalers[formatType],
+		logsMarshaler:    logsMarshalers[formatType],
+		compression:      compression,
+		compressor:       compressFuncs[compression],
+		formatType:       formatType,
+	}
+}
+
+func (m *marshaller) marshalTraces(td ptrace.Traces) ([]byte, error) {
+	buf, err := m.tracesMarshaler.MarshalTraces(td)
+	if err != nil {
+		return nil, err
+	}
+	return m.compressor(buf)
+}
+
+func (m *marshaller) marshalMetrics(md pmetric.Metrics) ([]byte, error) {
+	buf, err := m.metricsMarshaler.MarshalMetrics(md)
+	if err != nil {
+		return nil, err
+	}
+	return m.compressor(buf)
+}
+
+func (m *marshaller) marshalLogs(ld plog.Logs) ([]byte, error) {
+	buf, err := m.logsMarshaler.MarshalLogs(ld)
+	if err != nil {
+		return nil, err
+	}
+	return m.compressor(buf)
+}
+
+// lengthPrefixed reports whether messages have to be framed with their length
+// instead of being separated by newlines.
+func (m *marshaller) lengthPrefixed() bool {
+	return m.formatType == formatTypeProto || m.compression != ""
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// missingAttributeValue replaces placeholders whose resource attribute is not set.
+const missingAttributeValue = "unknown"
+
+var errUnclosedPlaceholder = errors.New("path contains an unclosed '{' placeholder")
+var errEmptyPlaceholder = errors.New("path contains an empty '{}' placeholder")
+
+// pathTemplate is a file path containing {attribute} placeholders that are
+// replaced by the value of the resource attribute with the same name.
+type pathTemplate struct {
+	// literals always has one more element than attributes, the rendered path is
+	// literals[0] + value(attributes[0]) + literals[1] + ... + literals[n].
+	literals   []string
+	attributes []string
+}
+
+// parsePathTemplate parses the path, returning nil when it contains no placeholders.
+func parsePathTemplate(path string) (*pathTemplate, error) {
+	t := &pathTemplate{}
+	rest := path
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			t.literals = append(t.literals, rest)
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, errUnclosedPlaceholder
+		}
+		name := strings.TrimSpace(rest[start+1 : start+end])
+		if name == "" {
+			return nil, errEmptyPlaceholder
+		}
+		t.literals = append(t.literals, rest[:start])
+		t.attributes = append(t.attributes, name)
+		rest = rest[start+end+1:]
+	}
+	if len(t.attributes) == 0 {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// render returns the path for a resource with the given attributes.
+func (t *pathTemplate) render(attrs pcommon.Map) string {
+	var sb strings.Builder
+	for i, attr := range t.attributes {
+		sb.WriteString(t.literals[i])
+		sb.WriteString(sanitizePathElement(attrs, attr))
+	}
+	sb.WriteString(t.literals[len(t.literals)-1])
+	return sb.String()
+}
+
+// sanitizePathElement makes sure an attribute value cannot escape the directory
+// structure defined by the template.
+func sanitizePathElement(attrs pcommon.Map, attr string) string {
+	v, ok := attrs.Get(attr)
+	if !ok {
+		return missingAttributeValue
+	}
+	s := strings.NewReplacer("/", "_", "\\", "_").Replace(v.AsString())
+	if s == "" || s == "." || s == ".." {
+		return missingAttributeValue
+	}
+	return s
+}
+
+func (t *pathTemplate) groupTraces(td ptrace.Traces) ([]string, map[string]ptrace.Traces) {
+	var paths []string
+	groups := make(map[string]ptrace.Traces)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		path := t.render(rs.Resource().Attributes())
+		group, ok := groups[path]
+		if !ok {
+			group = ptrace.NewTraces()
+			groups[path] = group
+			paths = append(paths, path)
+		}
+		rs.CopyTo(group.ResourceSpans().AppendEmpty())
+	}
+	return paths, groups
+}
+
+func (t *pathTemplate) groupMetrics(md pmetric.Metrics) ([]string, map[string]pmetric.Metrics) {
+	var paths []string
+	groups := make(map[string]pmetric.Metrics)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		path := t.render(rm.Resource().Attributes())
+		group, ok := groups[path]
+		if !ok {
+			group = pmetric.NewMetrics()
+			groups[path] = group
+			paths = append(paths, path)
+		}
+		rm.CopyTo(group.ResourceMetrics().AppendEmpty())
+	}
+	return paths, groups
+}
+
+func (t *pathTemplate) groupLogs(ld plog.Logs) ([]string, map[string]plog.Logs) {
+	var paths []string
+	groups := make(map[string]plog.Logs)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		path := t.render(rl.Resource().Attributes())
+		group, ok := groups[path]
+		if !ok {
+			group = plog.NewLogs()
+			groups[path] = group
+			paths = append(paths, path)
+		}
+		rl.CopyTo(group.ResourceLogs().AppendEmpty())
+	}
+	return paths, groups
+}
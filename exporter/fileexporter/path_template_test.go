@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParsePathTemplate(t *testing.T) {
+	tmpl, err := parsePathTemplate("./filename.json")
+	require.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	tmpl, err = parsePathTemplate("/var/otel/{service.name}/{ host.name }.jsonl")
+	require.NoError(t, err)
+	assert.Equal(t, &pathTemplate{
+		literals:   []string{"/var/otel/", "/", ".jsonl"},
+		attributes: []string{"service.name", "host.name"},
+	}, tmpl)
+
+	_, err = parsePathTemplate("/var/otel/{service.name")
+	assert.Equal(t, errUnclosedPlaceholder, err)
+
+	_, err = parsePathTemplate("/var/otel/{}.json")
+	assert.Equal(t, errEmptyPlaceholder, err)
+}
+
+func TestPathTemplateRender(t *testing.T) {
+	tmpl, err := parsePathTemplate("/var/otel/{service.name}/{host.name}.jsonl")
+	require.NoError(t, err)
+
+	attrs := pcommon.NewMap()
+	attrs.InsertString("service.name", "checkout")
+	attrs.InsertString("host.name", "node-1")
+	assert.Equal(t, "/var/otel/checkout/node-1.jsonl", tmpl.render(attrs))
+
+	attrs = pcommon.NewMap()
+	attrs.InsertString("service.name", "../../etc")
+	assert.Equal(t, "/var/otel/.._.._etc/unknown.jsonl", tmpl.render(attrs))
+
+	attrs = pcommon.NewMap()
+	attrs.InsertString("service.name", "..")
+	attrs.InsertInt("host.name", 7)
+	assert.Equal(t, "/var/otel/unknown/7.jsonl", tmpl.render(attrs))
+}
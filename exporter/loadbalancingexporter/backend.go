@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+)
+
+// endpointPlaceholder is replaced by the backend endpoint in the endpoint setting of a non-OTLP protocol.
+const endpointPlaceholder = "{endpoint}"
+
+// backendFactory creates the exporters of the backends with the configured protocol, each with its
+// own sending queue and retries when enabled.
+type backendFactory struct {
+	params component.ExporterCreateSettings
+	cfg    *Config
+
+	protocol config.Type
+	settings map[string]interface{}
+	factory  component.ExporterFactory
+}
+
+func newBackendFactory(params component.ExporterCreateSettings, cfg *Config) *backendFactory {
+	return &backendFactory{
+		params:   params,
+		cfg:      cfg,
+		protocol: otlpProtocol,
+		factory:  otlpexporter.NewFactory(),
+	}
+}
+
+// start looks up the factory of the protocol among the exporters available in the build.
+// It must be called before the exporters of the backends are created.
+func (f *backendFactory) start(host component.Host) error {
+	protocol, settings, err := f.cfg.Protocol.exporter()
+	if err != nil {
+		return err
+	}
+	if protocol == otlpProtocol {
+		return nil
+	}
+
+	factory, ok := host.GetFactory(component.KindExporter, protocol).(component.ExporterFactory)
+	if !ok {
+		return fmt.Errorf("the protocol %q isn't an exporter available in this build", protocol)
+	}
+
+	f.protocol = protocol
+	f.settings = settings
+	f.factory = factory
+	return nil
+}
+
+// exporterConfig builds the configuration of the exporter for the backend at the given endpoint.
+func (f *backendFactory) exporterConfig(endpoint string) (config.Exporter, error) {
+	if f.protocol == otlpProtocol {
+		oCfg := buildExporterConfig(f.cfg, endpoint)
+		return &oCfg, nil
+	}
+
+	settings := make(map[string]interface{}, len(f.settings)+1)
+	for k, v := range f.settings {
+		settings[k] = v
+	}
+	// the endpoint setting can be a template, for the protocols expecting a URL
+	if template, ok := settings["endpoint"].(string); ok && strings.Contains(template, endpointPlaceholder) {
+		settings["endpoint"] = strings.ReplaceAll(template, endpointPlaceholder, endpoint)
+	} else {
+		settings["endpoint"] = endpoint
+	}
+
+	cfg := f.factory.CreateDefaultConfig()
+	if err := config.UnmarshalExporter(confmap.NewFromStringMap(settings), cfg); err != nil {
+		return nil, fmt.Errorf("invalid settings for the %q protocol: %w", f.protocol, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid settings for the %q protocol: %w", f.protocol, err)
+	}
+	return cfg, nil
+}
+
+// backendSettings returns the settings of the queue and retries of the backend at the given endpoint,
+// whose ID tells the backends apart in the exporters' own metrics.
+func (f *backendFactory) backendSettings(endpoint string) *config.ExporterSettings {
+	name := endpoint
+	if lbName := f.cfg.ID().Name(); lbName != "" {
+		name = lbName + "_" + endpoint
+	}
+	settings := config.NewExporterSettings(config.NewComponentIDWithName(f.cfg.ID().Type(), name))
+	return &settings
+}
+
+func (f *backendFactory) queueOrRetryEnabled() bool {
+	return f.cfg.QueueSettings.Enabled || f.cfg.RetrySettings.Enabled
+}
+
+func (f *backendFactory) backendOptions(exp component.Exporter) []exporterhelper.Option {
+	return []exporterhelper.Option{
+		exporterhelper.WithStart(exp.Start),
+		exporterhelper.WithShutdown(exp.Shutdown),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		// the timeout is applied by the exporter of the backend
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{}),
+		exporterhelper.WithQueue(f.cfg.QueueSettings),
+		exporterhelper.WithRetry(f.cfg.RetrySettings),
+	}
+}
+
+func (f *backendFactory) createTracesExporter(ctx context.Context, endpoint string) (component.Exporter, error) {
+	cfg, err := f.exporterConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := f.factory.CreateTracesExporter(ctx, f.params, cfg)
+	if err != nil || !f.queueOrRetryEnabled() {
+		return exp, err
+	}
+	return exporterhelper.NewTracesExporter(ctx, f.params, f.backendSettings(endpoint), exp.ConsumeTraces, f.backendOptions(exp)...)
+}
+
+func (f *backendFactory) createLogsExporter(ctx context.Context, endpoint string) (component.Exporter, error) {
+	cfg, err := f.exporterConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := f.factory.CreateLogsExporter(ctx, f.params, cfg)
+	if err != nil || !f.queueOrRetryEnabled() {
+		return exp, err
+	}
+	return exporterhelper.NewLogsExporter(ctx, f.params, f.backendSettings(endpoint), exp.ConsumeLogs, f.backendOptions(exp)...)
+}
+
+func (f *backendFactory) createMetricsExporter(ctx context.Context, endpoint string) (component.Exporter, error) {
+	cfg, err := f.exporterConfig(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exp, err := f.factory.CreateMetricsExporter(ctx, f.params, cfg)
+	if err != nil || !f.queueOrRetryEnabled() {
+		return exp, err
+	}
+	return exporterhelper.NewMetricsExporter(ctx, f.params, f.backendSettings(endpoint), exp.ConsumeMetrics, f.backendOptions(exp)...)
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestBackendFactoryOTLP(t *testing.T) {
+	// prepare
+	f := newBackendFactory(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NoError(t, f.start(componenttest.NewNopHost()))
+
+	// test
+	cfg, err := f.exporterConfig("endpoint-1:4317")
+
+	// verify
+	require.NoError(t, err)
+	require.IsType(t, &otlpexporter.Config{}, cfg)
+	assert.Equal(t, "endpoint-1:4317", cfg.(*otlpexporter.Config).Endpoint)
+}
+
+func TestBackendFactoryOtherProtocol(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		settings interface{}
+		expected string
+	}{
+		{
+			desc:     "no settings",
+			expected: "endpoint-1:4318",
+		},
+		{
+			desc:     "endpoint overridden",
+			settings: map[string]interface{}{"endpoint": "http://should-be-replaced"},
+			expected: "endpoint-1:4318",
+		},
+		{
+			desc:     "endpoint template",
+			settings: map[string]interface{}{"endpoint": "https://{endpoint}/otlp", "timeout": "1s"},
+			expected: "https://endpoint-1:4318/otlp",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			lbCfg := simpleConfig()
+			lbCfg.Protocol.Others = map[string]interface{}{"otlphttp": tt.settings}
+			f := newBackendFactory(componenttest.NewNopExporterCreateSettings(), lbCfg)
+			require.NoError(t, f.start(newFactoriesHost(otlphttpexporter.NewFactory())))
+
+			// test
+			cfg, err := f.exporterConfig("endpoint-1:4318")
+			require.NoError(t, err)
+			exp, err := f.createTracesExporter(context.Background(), "endpoint-1:4318")
+
+			// verify
+			require.NoError(t, err)
+			assert.NotNil(t, exp)
+			require.IsType(t, &otlphttpexporter.Config{}, cfg)
+			assert.Equal(t, tt.expected, cfg.(*otlphttpexporter.Config).Endpoint)
+		})
+	}
+}
+
+func TestBackendFactoryUnavailableProtocol(t *testing.T) {
+	// prepare
+	lbCfg := simpleConfig()
+	lbCfg.Protocol.Others = map[string]interface{}{"zipkin": nil}
+	f := newBackendFactory(componenttest.NewNopExporterCreateSettings(), lbCfg)
+
+	// test
+	err := f.start(componenttest.NewNopHost())
+
+	// verify
+	assert.EqualError(t, err, `the protocol "zipkin" isn't an exporter available in this build`)
+}
+
+func TestBackendFactoryInvalidSettings(t *testing.T) {
+	// prepare
+	lbCfg := simpleConfig()
+	lbCfg.Protocol.Others = map[string]interface{}{"otlphttp": map[string]interface{}{"unknown": "value"}}
+	f := newBackendFactory(componenttest.NewNopExporterCreateSettings(), lbCfg)
+	require.NoError(t, f.start(newFactoriesHost(otlphttpexporter.NewFactory())))
+
+	// test
+	_, err := f.createTracesExporter(context.Background(), "endpoint-1:4318")
+
+	// verify
+	assert.Error(t, err)
+}
+
+func TestBackendQueuesAreIndependent(t *testing.T) {
+	// prepare
+	unblock := make(chan struct{})
+	sinks := map[string]*consumertest.TracesSink{}
+	factory := component.NewExporterFactory(
+		"mock",
+		func() config.Exporter {
+			return &mockBackendConfig{ExporterSettings: config.NewExporterSettings(config.NewComponentID("mock"))}
+		},
+		component.WithTracesExporter(func(_ context.Context, _ component.ExporterCreateSettings, cfg config.Exporter) (component.TracesExporter, error) {
+			endpoint := cfg.(*mockBackendConfig).Endpoint
+			sinks[endpoint] = new(consumertest.TracesSink)
+			return newMockTracesExporter(func(ctx context.Context, td ptrace.Traces) error {
+				if endpoint == "slow:4317" {
+					<-unblock
+				}
+				return sinks[endpoint].ConsumeTraces(ctx, td)
+			}), nil
+		}, component.StabilityLevelInDevelopment),
+	)
+
+	lbCfg := simpleConfig()
+	lbCfg.Protocol.Others = map[string]interface{}{"mock": nil}
+	lbCfg.QueueSettings = exporterhelperQueueSettings(1, 10)
+	f := newBackendFactory(componenttest.NewNopExporterCreateSettings(), lbCfg)
+	require.NoError(t, f.start(newFactoriesHost(factory)))
+
+	backends := map[string]component.TracesExporter{}
+	for _, endpoint := range []string{"slow:4317", "fast:4317"} {
+		exp, err := f.createTracesExporter(context.Background(), endpoint)
+		require.NoError(t, err)
+		require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+		backends[endpoint] = exp.(component.TracesExporter)
+	}
+
+	// test
+	require.NoError(t, backends["slow:4317"].ConsumeTraces(context.Background(), simpleTraces()))
+	require.NoError(t, backends["slow:4317"].ConsumeTraces(context.Background(), simpleTraces()))
+	require.NoError(t, backends["fast:4317"].ConsumeTraces(context.Background(), simpleTraces()))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return sinks["fast:4317"].SpanCount() == 1
+	}, 5*time.Second, 10*time.Millisecond, "Must not be blocked by the slow backend")
+	assert.Equal(t, 0, sinks["slow:4317"].SpanCount())
+
+	close(unblock)
+	for _, exp := range backends {
+		require.NoError(t, exp.Shutdown(context.Background()))
+	}
+	assert.Equal(t, 2, sinks["slow:4317"].SpanCount(), "Must drain the queue on shutdown")
+}
+
+type mockBackendConfig struct {
+	config.ExporterSettings `mapstructure:",squash"`
+	Endpoint                string `mapstructure:"endpoint"`
+}
+
+// factoriesHost is a host providing the given exporter factories.
+type factoriesHost struct {
+	component.Host
+	factories map[config.Type]component.ExporterFactory
+}
+
+func newFactoriesHost(factories ...component.ExporterFactory) component.Host {
+	h := &factoriesHost{
+		Host:      componenttest.NewNopHost(),
+		factories: map[config.Type]component.ExporterFactory{},
+	}
+	for _, f := range factories {
+		h.factories[f.Type()] = f
+	}
+	return h
+}
+
+func (h *factoriesHost) GetFactory(kind component.Kind, componentType config.Type) component.Factory {
+	if f, ok := h.factories[componentType]; ok && kind == component.KindExporter {
+		return f
+	}
+	return nil
+}
+
+func exporterhelperQueueSettings(numConsumers, queueSize int) exporterhelper.QueueSettings {
+	qs := exporterhelper.NewDefaultQueueSettings()
+	qs.NumConsumers = numConsumers
+	qs.QueueSize = queueSize
+	return qs
+}
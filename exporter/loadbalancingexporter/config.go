@@ -15,10 +15,15 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
 
 type routingKey int
@@ -28,23 +33,67 @@ const (
 	svcRouting
 )
 
+const otlpProtocol config.Type = "otlp"
+
 // Config defines configuration for the exporter.
 type Config struct {
 	config.ExporterSettings `mapstructure:",squash"`
 	Protocol                Protocol         `mapstructure:"protocol"`
 	Resolver                ResolverSettings `mapstructure:"resolver"`
 	RoutingKey              string           `mapstructure:"routing_key"`
+
+	// QueueSettings and RetrySettings are applied to each backend individually, so that a slow or
+	// failing backend doesn't hold back the data sent to the others.
+	exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings `mapstructure:"retry_on_failure"`
 }
 
-// Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
+var _ config.Exporter = (*Config)(nil)
+
+// Protocol holds the individual protocol-specific settings.
 type Protocol struct {
 	OTLP otlpexporter.Config `mapstructure:"otlp"`
+
+	// Others holds the settings of another exporter type available in the build, keyed by the type,
+	// e.g. otlphttp, jaeger or zipkin. When set, it's used instead of OTLP.
+	Others map[string]interface{} `mapstructure:",remain"`
+}
+
+// Validate checks if the exporter configuration is valid
+func (cfg *Config) Validate() error {
+	if _, _, err := cfg.Protocol.exporter(); err != nil {
+		return err
+	}
+	return cfg.QueueSettings.Validate()
+}
+
+// exporter returns the type and the settings of the exporter used for the backends, if not OTLP.
+func (p Protocol) exporter() (config.Type, map[string]interface{}, error) {
+	if len(p.Others) == 0 {
+		return otlpProtocol, nil, nil
+	}
+	if len(p.Others) > 1 {
+		return "", nil, errors.New("only one protocol should be specified")
+	}
+
+	for typeStr, settings := range p.Others {
+		if settings == nil {
+			return config.Type(typeStr), map[string]interface{}{}, nil
+		}
+		settingsMap, ok := settings.(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("invalid settings for the %q protocol", typeStr)
+		}
+		return config.Type(typeStr), settingsMap, nil
+	}
+	return otlpProtocol, nil, nil
 }
 
 // ResolverSettings defines the configurations for the backend resolver
 type ResolverSettings struct {
 	Static *StaticResolver `mapstructure:"static"`
 	DNS    *DNSResolver    `mapstructure:"dns"`
+	K8sSvc *K8sSvcResolver `mapstructure:"k8s"`
 }
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
@@ -59,3 +108,12 @@ type DNSResolver struct {
 	Interval time.Duration `mapstructure:"interval"`
 	Timeout  time.Duration `mapstructure:"timeout"`
 }
+
+// K8sSvcResolver defines the configuration for the resolver watching the endpoints of a Kubernetes service
+type K8sSvcResolver struct {
+	k8sconfig.APIConfig `mapstructure:",squash"`
+	// Service is the name of the service, as "name.namespace"; the namespace defaults to "default".
+	Service string `mapstructure:"service"`
+	// Ports are the ports of the service's pods to send the data to, one backend per port and pod.
+	Ports []int32 `mapstructure:"ports"`
+}
@@ -17,10 +17,13 @@ package loadbalancingexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/service/servicetest"
 )
 
@@ -33,4 +36,51 @@ func TestLoadConfig(t *testing.T) {
 	cfg, err := servicetest.LoadConfigAndValidate(filepath.Join("testdata", "config.yaml"), factories)
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
+
+	lbCfg := cfg.Exporters[config.NewComponentIDWithName(typeStr, "5")].(*Config)
+	assert.Equal(t, map[string]interface{}{"otlphttp": map[string]interface{}{"endpoint": "http://{endpoint}"}}, lbCfg.Protocol.Others)
+	assert.True(t, lbCfg.QueueSettings.Enabled)
+	assert.Equal(t, 2, lbCfg.QueueSettings.NumConsumers)
+	assert.Equal(t, 100, lbCfg.QueueSettings.QueueSize)
+	assert.True(t, lbCfg.RetrySettings.Enabled)
+	assert.Equal(t, 60*time.Second, lbCfg.RetrySettings.MaxElapsedTime)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		protocol Protocol
+		err      string
+	}{
+		{
+			desc:     "otlp",
+			protocol: Protocol{OTLP: otlpexporter.Config{}},
+		},
+		{
+			desc:     "other protocol",
+			protocol: Protocol{Others: map[string]interface{}{"otlphttp": nil}},
+		},
+		{
+			desc:     "multiple protocols",
+			protocol: Protocol{Others: map[string]interface{}{"otlphttp": nil, "zipkin": nil}},
+			err:      "only one protocol should be specified",
+		},
+		{
+			desc:     "invalid protocol settings",
+			protocol: Protocol{Others: map[string]interface{}{"otlphttp": "http://endpoint"}},
+			err:      `invalid settings for the "otlphttp" protocol`,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Protocol = tt.protocol
+
+			err := cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
 }
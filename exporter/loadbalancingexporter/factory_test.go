@@ -60,3 +60,22 @@ func TestLogExporterGetsCreatedWithValidConfiguration(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, exp)
 }
+
+func TestMetricsExporterGetsCreatedWithValidConfiguration(t *testing.T) {
+	// prepare
+	factory := NewFactory()
+	creationParams := componenttest.NewNopExporterCreateSettings()
+	cfg := &Config{
+		ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1"}},
+		},
+	}
+
+	// test
+	exp, err := factory.CreateMetricsExporter(context.Background(), creationParams, cfg)
+
+	// verify
+	assert.Nil(t, err)
+	assert.NotNil(t, exp)
+}
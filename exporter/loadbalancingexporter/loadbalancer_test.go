@@ -25,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
 
 func TestNewLoadBalancerNoResolver(t *testing.T) {
@@ -110,6 +112,31 @@ func TestWithDNSResolver(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestWithK8sResolver(t *testing.T) {
+	// prepare
+	t.Setenv("KUBERNETES_SERVICE_HOST", "127.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "6443")
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			K8sSvc: &K8sSvcResolver{
+				APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeNone},
+				Service:   "lb.observability",
+				Ports:     []int32{4317},
+			},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	res, ok := p.res.(*k8sResolver)
+
+	// verify
+	assert.True(t, ok)
+	assert.Equal(t, "observability", res.svcNs)
+}
+
 func TestWithDNSResolverNoEndpoints(t *testing.T) {
 	// prepare
 	cfg := &Config{
@@ -153,6 +180,26 @@ func TestMultipleResolvers(t *testing.T) {
 	assert.Equal(t, errMultipleResolversProvided, err)
 }
 
+func TestMultipleResolversWithK8s(t *testing.T) {
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			DNS: &DNSResolver{
+				Hostname: "service-1",
+			},
+			K8sSvc: &K8sSvcResolver{
+				Service: "lb",
+			},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errMultipleResolversProvided, err)
+}
+
 func TestStartFailureStaticResolver(t *testing.T) {
 	// prepare
 	cfg := simpleConfig()
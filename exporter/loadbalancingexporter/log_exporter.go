@@ -26,7 +26,6 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/multierr"
@@ -38,6 +37,7 @@ var _ component.LogsExporter = (*logExporterImp)(nil)
 
 type logExporterImp struct {
 	loadBalancer loadBalancer
+	backends     *backendFactory
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -45,18 +45,16 @@ type logExporterImp struct {
 
 // Create new logs exporter
 func newLogsExporter(params component.ExporterCreateSettings, cfg config.Exporter) (*logExporterImp, error) {
-	exporterFactory := otlpexporter.NewFactory()
+	backends := newBackendFactory(params, cfg.(*Config))
 
-	lb, err := newLoadBalancer(params, cfg, func(ctx context.Context, endpoint string) (component.Exporter, error) {
-		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
-		return exporterFactory.CreateLogsExporter(ctx, params, &oCfg)
-	})
+	lb, err := newLoadBalancer(params, cfg, backends.createLogsExporter)
 	if err != nil {
 		return nil, err
 	}
 
 	return &logExporterImp{
 		loadBalancer: lb,
+		backends:     backends,
 	}, nil
 }
 
@@ -65,6 +63,9 @@ func (e *logExporterImp) Capabilities() consumer.Capabilities {
 }
 
 func (e *logExporterImp) Start(ctx context.Context, host component.Host) error {
+	if err := e.backends.start(host); err != nil {
+		return err
+	}
 	return e.loadBalancer.Start(ctx, host)
 }
 
@@ -0,0 +1,256 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+)
+
+var _ component.MetricsExporter = (*metricExporterImp)(nil)
+
+const streamIDSeparator = string(byte(0))
+
+type metricExporterImp struct {
+	loadBalancer loadBalancer
+	backends     *backendFactory
+
+	stopped    bool
+	shutdownWg sync.WaitGroup
+}
+
+// Create new metrics exporter
+func newMetricsExporter(params component.ExporterCreateSettings, cfg config.Exporter) (*metricExporterImp, error) {
+	backends := newBackendFactory(params, cfg.(*Config))
+
+	lb, err := newLoadBalancer(params, cfg, backends.createMetricsExporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricExporterImp{
+		loadBalancer: lb,
+		backends:     backends,
+	}, nil
+}
+
+func (e *metricExporterImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (e *metricExporterImp) Start(ctx context.Context, host component.Host) error {
+	if err := e.backends.start(host); err != nil {
+		return err
+	}
+	return e.loadBalancer.Start(ctx, host)
+}
+
+func (e *metricExporterImp) Shutdown(context.Context) error {
+	e.stopped = true
+	e.shutdownWg.Wait()
+	return nil
+}
+
+// ConsumeMetrics splits the metrics by stream, i.e. by resource, metric name and data point attributes,
+// so that all the data points of a stream are sent to the same backend.
+func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	batches := e.splitMetricsByEndpoint(md)
+
+	var errs error
+	for endpoint, batch := range batches {
+		errs = multierr.Append(errs, e.consumeMetric(ctx, endpoint, batch.md))
+	}
+
+	return errs
+}
+
+func (e *metricExporterImp) consumeMetric(ctx context.Context, endpoint string, md pmetric.Metrics) error {
+	exp, err := e.loadBalancer.Exporter(endpoint)
+	if err != nil {
+		return err
+	}
+
+	me, ok := exp.(component.MetricsExporter)
+	if !ok {
+		expectType := (*component.MetricsExporter)(nil)
+		return fmt.Errorf("unable to export metrics, unexpected exporter type: expected %T but got %T", expectType, exp)
+	}
+
+	start := time.Now()
+	err = me.ConsumeMetrics(ctx, md)
+	duration := time.Since(start)
+	ctx, _ = tag.New(ctx, tag.Upsert(tag.MustNewKey("endpoint"), endpoint))
+
+	if err == nil {
+		sCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("success"), "true"))
+		stats.Record(sCtx, mBackendLatency.M(duration.Milliseconds()))
+	} else {
+		fCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("success"), "false"))
+		stats.Record(fCtx, mBackendLatency.M(duration.Milliseconds()))
+	}
+
+	return err
+}
+
+// metricsBatch holds the data points routed to a backend, keeping the resources, scopes and metrics
+// they belong to in the original metrics.
+type metricsBatch struct {
+	md        pmetric.Metrics
+	resources map[int]pmetric.ResourceMetrics
+	scopes    map[[2]int]pmetric.ScopeMetrics
+	metrics   map[[3]int]pmetric.Metric
+}
+
+func newMetricsBatch() *metricsBatch {
+	return &metricsBatch{
+		md:        pmetric.NewMetrics(),
+		resources: make(map[int]pmetric.ResourceMetrics),
+		scopes:    make(map[[2]int]pmetric.ScopeMetrics),
+		metrics:   make(map[[3]int]pmetric.Metric),
+	}
+}
+
+// metric returns the copy in the batch of the metric at the given indexes of the original metrics,
+// without its data points, creating it and its parents if needed.
+func (b *metricsBatch) metric(md pmetric.Metrics, i, j, k int) pmetric.Metric {
+	if m, ok := b.metrics[[3]int{i, j, k}]; ok {
+		return m
+	}
+
+	rm, ok := b.resources[i]
+	if !ok {
+		srcRm := md.ResourceMetrics().At(i)
+		rm = b.md.ResourceMetrics().AppendEmpty()
+		srcRm.Resource().CopyTo(rm.Resource())
+		rm.SetSchemaUrl(srcRm.SchemaUrl())
+		b.resources[i] = rm
+	}
+
+	sm, ok := b.scopes[[2]int{i, j}]
+	if !ok {
+		srcSm := md.ResourceMetrics().At(i).ScopeMetrics().At(j)
+		sm = rm.ScopeMetrics().AppendEmpty()
+		srcSm.Scope().CopyTo(sm.Scope())
+		sm.SetSchemaUrl(srcSm.SchemaUrl())
+		b.scopes[[2]int{i, j}] = sm
+	}
+
+	m := sm.Metrics().AppendEmpty()
+	copyMetricDescriptor(md.ResourceMetrics().At(i).ScopeMetrics().At(j).Metrics().At(k), m)
+	b.metrics[[3]int{i, j, k}] = m
+	return m
+}
+
+func (e *metricExporterImp) splitMetricsByEndpoint(md pmetric.Metrics) map[string]*metricsBatch {
+	batches := make(map[string]*metricsBatch)
+	batchFor := func(resourceID string, m pmetric.Metric, attrs pcommon.Map) *metricsBatch {
+		streamID := resourceID + streamIDSeparator + m.Name() + streamIDSeparator + attributesID(attrs)
+		endpoint := e.loadBalancer.Endpoint([]byte(streamID))
+		batch, ok := batches[endpoint]
+		if !ok {
+			batch = newMetricsBatch()
+			batches[endpoint] = batch
+		}
+		return batch
+	}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		resourceID := attributesID(rms.At(i).Resource().Attributes())
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				m := metrics.At(k)
+				switch m.DataType() {
+				case pmetric.MetricDataTypeGauge:
+					dps := m.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest := batchFor(resourceID, m, dps.At(l).Attributes()).metric(md, i, j, k)
+						dps.At(l).CopyTo(dest.Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest := batchFor(resourceID, m, dps.At(l).Attributes()).metric(md, i, j, k)
+						dps.At(l).CopyTo(dest.Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest := batchFor(resourceID, m, dps.At(l).Attributes()).metric(md, i, j, k)
+						dps.At(l).CopyTo(dest.Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest := batchFor(resourceID, m, dps.At(l).Attributes()).metric(md, i, j, k)
+						dps.At(l).CopyTo(dest.ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest := batchFor(resourceID, m, dps.At(l).Attributes()).metric(md, i, j, k)
+						dps.At(l).CopyTo(dest.Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
+		}
+	}
+
+	return batches
+}
+
+// copyMetricDescriptor copies the metric to dest, without its data points.
+func copyMetricDescriptor(src, dest pmetric.Metric) {
+	dest.SetName(src.Name())
+	dest.SetDescription(src.Description())
+	dest.SetUnit(src.Unit())
+	dest.SetDataType(src.DataType())
+
+	switch src.DataType() {
+	case pmetric.MetricDataTypeSum:
+		dest.Sum().SetAggregationTemporality(src.Sum().AggregationTemporality())
+		dest.Sum().SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricDataTypeHistogram:
+		dest.Histogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dest.ExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	}
+}
+
+// attributesID builds an identifier of the attributes, independent of their order.
+func attributesID(attrs pcommon.Map) string {
+	kvs := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		kvs = append(kvs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(kvs)
+	return strings.Join(kvs, streamIDSeparator)
+}
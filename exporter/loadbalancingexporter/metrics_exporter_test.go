@@ -0,0 +1,275 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestNewMetricsExporter(t *testing.T) {
+	for _, tt := range []struct {
+		desc   string
+		config *Config
+		err    error
+	}{
+		{
+			"simple",
+			simpleConfig(),
+			nil,
+		},
+		{
+			"empty",
+			&Config{
+				ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+			},
+			errNoResolver,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			_, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), tt.config)
+
+			// verify
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestMetricsExporterStart(t *testing.T) {
+	// prepare
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), nil)
+	require.NoError(t, err)
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NoError(t, err)
+
+	lb.res = &mockResolver{
+		onStart: func(context.Context) error {
+			return errors.New("some expected err")
+		},
+	}
+	p.loadBalancer = lb
+
+	// test
+	res := p.Start(context.Background(), componenttest.NewNopHost())
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// verify
+	require.Equal(t, errors.New("some expected err"), res)
+}
+
+func TestConsumeMetricsExporterNotFound(t *testing.T) {
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockTracesExporter(), nil
+	}
+	p := newTestMetricsExporter(t, componentFactory, "endpoint-1")
+
+	// test
+	res := p.ConsumeMetrics(context.Background(), simpleMetrics(1))
+
+	// verify
+	assert.EqualError(t, res, fmt.Sprintf("couldn't find the exporter for the endpoint %q", "endpoint-1"))
+}
+
+func TestConsumeMetricsUnexpectedExporterType(t *testing.T) {
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	p := newTestMetricsExporter(t, componentFactory, "endpoint-1")
+	p.loadBalancer.(*loadBalancerImp).exporters["endpoint-1"] = newNopMockExporter()
+
+	// test
+	res := p.ConsumeMetrics(context.Background(), simpleMetrics(1))
+
+	// verify
+	assert.EqualError(t, res, fmt.Sprintf("unable to export metrics, unexpected exporter type: expected *component.MetricsExporter but got %T", newNopMockExporter()))
+}
+
+func TestConsumeMetricsStreamRouting(t *testing.T) {
+	// prepare
+	sinks := map[string]*consumertest.MetricsSink{}
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		sinks[endpoint] = new(consumertest.MetricsSink)
+		return newMockMetricsExporter(sinks[endpoint]), nil
+	}
+	p := newTestMetricsExporter(t, componentFactory, "endpoint-1:4317", "endpoint-2:4317")
+
+	// test
+	md := simpleMetrics(50)
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+	require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+
+	// verify
+	require.Len(t, sinks, 2)
+	streams := map[string]string{}
+	total := 0
+	for endpoint, sink := range sinks {
+		require.Len(t, sink.AllMetrics(), 2, "Must receive a batch of each call")
+		for _, received := range sink.AllMetrics() {
+			total += received.DataPointCount()
+
+			rm := received.ResourceMetrics().At(0)
+			assert.Equal(t, map[string]interface{}{"service.name": "svc"}, rm.Resource().Attributes().AsRaw())
+			assert.Equal(t, "scope", rm.ScopeMetrics().At(0).Scope().Name())
+
+			metrics := rm.ScopeMetrics().At(0).Metrics()
+			for i := 0; i < metrics.Len(); i++ {
+				m := metrics.At(i)
+				require.Equal(t, pmetric.MetricDataTypeSum, m.DataType())
+				assert.True(t, m.Sum().IsMonotonic())
+				assert.Equal(t, pmetric.MetricAggregationTemporalityCumulative, m.Sum().AggregationTemporality())
+
+				dps := m.Sum().DataPoints()
+				for j := 0; j < dps.Len(); j++ {
+					id, _ := dps.At(j).Attributes().Get("id")
+					stream := m.Name() + "/" + id.AsString()
+					if previous, ok := streams[stream]; ok {
+						assert.Equal(t, previous, endpoint, "Must route a stream to the same backend")
+					}
+					streams[stream] = endpoint
+				}
+			}
+		}
+	}
+	assert.Equal(t, 2*md.DataPointCount(), total, "Must export all the data points")
+	assert.Len(t, streams, md.DataPointCount())
+}
+
+func TestSplitMetricsDataTypes(t *testing.T) {
+	// prepare
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newMockMetricsExporter(new(consumertest.MetricsSink)), nil
+	}
+	p := newTestMetricsExporter(t, componentFactory, "endpoint-1:4317")
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for _, dataType := range []pmetric.MetricDataType{
+		pmetric.MetricDataTypeGauge,
+		pmetric.MetricDataTypeSum,
+		pmetric.MetricDataTypeHistogram,
+		pmetric.MetricDataTypeExponentialHistogram,
+		pmetric.MetricDataTypeSummary,
+	} {
+		m := metrics.AppendEmpty()
+		m.SetName(dataType.String())
+		m.SetDataType(dataType)
+		switch dataType {
+		case pmetric.MetricDataTypeGauge:
+			m.Gauge().DataPoints().AppendEmpty().SetIntVal(1)
+		case pmetric.MetricDataTypeSum:
+			m.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+			m.Sum().DataPoints().AppendEmpty().SetIntVal(1)
+		case pmetric.MetricDataTypeHistogram:
+			m.Histogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+			m.Histogram().DataPoints().AppendEmpty().SetCount(1)
+		case pmetric.MetricDataTypeExponentialHistogram:
+			m.ExponentialHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+			m.ExponentialHistogram().DataPoints().AppendEmpty().SetCount(1)
+		case pmetric.MetricDataTypeSummary:
+			m.Summary().DataPoints().AppendEmpty().SetCount(1)
+		}
+	}
+
+	// test
+	batches := p.splitMetricsByEndpoint(md)
+
+	// verify
+	require.Len(t, batches, 1)
+	assert.Equal(t, md, batches["endpoint-1:4317"].md)
+}
+
+func TestAttributesID(t *testing.T) {
+	attrs := pcommon.NewMap()
+	attrs.InsertString("a", "1")
+	attrs.InsertInt("b", 2)
+
+	reordered := pcommon.NewMap()
+	reordered.InsertInt("b", 2)
+	reordered.InsertString("a", "1")
+
+	assert.Equal(t, attributesID(attrs), attributesID(reordered))
+	assert.NotEqual(t, attributesID(attrs), attributesID(pcommon.NewMap()))
+}
+
+func newTestMetricsExporter(t *testing.T, componentFactory componentFactory, endpoints ...string) *metricExporterImp {
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return endpoints, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	})
+	return p
+}
+
+// simpleMetrics builds two cumulative sums of the given number of streams each.
+func simpleMetrics(streams int) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "svc")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+	for _, name := range []string{"requests", "errors"} {
+		m := sm.Metrics().AppendEmpty()
+		m.SetName(name)
+		m.SetDataType(pmetric.MetricDataTypeSum)
+		m.Sum().SetIsMonotonic(true)
+		m.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+		for i := 0; i < streams; i++ {
+			dp := m.Sum().DataPoints().AppendEmpty()
+			dp.Attributes().InsertInt("id", int64(i))
+			dp.SetIntVal(int64(i))
+		}
+	}
+	return md
+}
+
+type mockMetricsExporter struct {
+	component.Component
+	*consumertest.MetricsSink
+}
+
+func newMockMetricsExporter(sink *consumertest.MetricsSink) component.MetricsExporter {
+	return &mockMetricsExporter{
+		Component:   mockComponent{},
+		MetricsSink: sink,
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ resolver = (*k8sResolver)(nil)
+
+const (
+	defaultK8sNamespace   = "default"
+	defaultK8sSyncTimeout = 10 * time.Second
+)
+
+var errNoSvc = errors.New("no service specified to resolve the backends")
+
+// k8sResolver watches the Endpoints object of a Kubernetes service, so that the backends
+// are updated as soon as the service's pods change, without DNS caching delays.
+type k8sResolver struct {
+	logger *zap.Logger
+
+	svcName     string
+	svcNs       string
+	ports       []int32
+	informer    cache.SharedInformer
+	syncTimeout time.Duration
+
+	// the ready addresses of the service's pods, as last seen by the informer
+	addresses         []string
+	endpoints         []string
+	onChangeCallbacks []func([]string)
+
+	stopCh             chan (struct{})
+	updateLock         sync.Mutex
+	shutdownWg         sync.WaitGroup
+	changeCallbackLock sync.RWMutex
+}
+
+// newK8sResolver creates a resolver for the service, named as "name.namespace", whose backends are
+// built with each of the given ports, or without port if none is given.
+func newK8sResolver(clt kubernetes.Interface, logger *zap.Logger, service string, ports []int32) (*k8sResolver, error) {
+	if len(service) == 0 {
+		return nil, errNoSvc
+	}
+
+	// the service can also be given as its DNS name, e.g. name.namespace.svc.cluster.local
+	nAddr := strings.SplitN(service, ".", 3)
+	svcName, svcNs := nAddr[0], defaultK8sNamespace
+	if len(nAddr) > 1 && nAddr[1] != "" {
+		svcNs = nAddr[1]
+	}
+
+	epsSelector := fields.OneTermEqualSelector("metadata.name", svcName).String()
+	epsListWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = epsSelector
+			return clt.CoreV1().Endpoints(svcNs).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = epsSelector
+			return clt.CoreV1().Endpoints(svcNs).Watch(context.Background(), options)
+		},
+	}
+
+	r := &k8sResolver{
+		logger:      logger,
+		svcName:     svcName,
+		svcNs:       svcNs,
+		ports:       ports,
+		informer:    cache.NewSharedInformer(epsListWatcher, &corev1.Endpoints{}, 0),
+		syncTimeout: defaultK8sSyncTimeout,
+		stopCh:      make(chan struct{}),
+	}
+	r.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: r.onEndpointsUpdate,
+		UpdateFunc: func(_, obj interface{}) {
+			r.onEndpointsUpdate(obj)
+		},
+		DeleteFunc: r.onEndpointsDelete,
+	})
+
+	return r, nil
+}
+
+func (r *k8sResolver) start(ctx context.Context) error {
+	go r.informer.Run(r.stopCh)
+
+	syncCtx, cancel := context.WithTimeout(ctx, r.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), r.informer.HasSynced) {
+		// the informer keeps trying in the background, and the backends will be updated once it succeeds
+		r.logger.Warn("failed to sync the endpoints of the service", zap.String("service", r.svcName), zap.String("namespace", r.svcNs))
+	}
+
+	if _, err := r.resolve(ctx); err != nil {
+		r.logger.Warn("failed to resolve", zap.Error(err))
+	}
+
+	r.logger.Debug("K8s service resolver started",
+		zap.String("service", r.svcName), zap.String("namespace", r.svcNs), zap.Int32s("ports", r.ports))
+	return nil
+}
+
+func (r *k8sResolver) shutdown(ctx context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *k8sResolver) onEndpointsUpdate(obj interface{}) {
+	eps, ok := obj.(*corev1.Endpoints)
+	if !ok || eps.Name != r.svcName {
+		return
+	}
+
+	var addresses []string
+	for _, subset := range eps.Subsets {
+		// only the ready addresses are used, the not ready ones would be failing requests
+		for _, addr := range subset.Addresses {
+			addresses = append(addresses, addr.IP)
+		}
+	}
+
+	r.updateAddresses(addresses)
+}
+
+func (r *k8sResolver) onEndpointsDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if eps, ok := obj.(*corev1.Endpoints); !ok || eps.Name != r.svcName {
+		return
+	}
+
+	r.updateAddresses(nil)
+}
+
+func (r *k8sResolver) updateAddresses(addresses []string) {
+	r.updateLock.Lock()
+	r.addresses = addresses
+	r.updateLock.Unlock()
+
+	if _, err := r.resolve(context.Background()); err != nil {
+		r.logger.Warn("failed to resolve", zap.Error(err))
+	}
+}
+
+func (r *k8sResolver) resolve(ctx context.Context) ([]string, error) {
+	r.shutdownWg.Add(1)
+	defer r.shutdownWg.Done()
+
+	// the context to use for all metrics in this function
+	mCtx, _ := tag.New(ctx, tag.Upsert(tag.MustNewKey("resolver"), "k8s"))
+
+	// the lock is held while propagating the change, so that the callbacks see the updates in order
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+
+	var backends []string
+	for _, ip := range r.addresses {
+		host := ip
+		if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+			// it's an IPv6 address
+			host = fmt.Sprintf("[%s]", ip)
+		}
+
+		if len(r.ports) == 0 {
+			backends = append(backends, host)
+			continue
+		}
+		for _, port := range r.ports {
+			backends = append(backends, fmt.Sprintf("%s:%d", host, port))
+		}
+	}
+
+	// keep it always in the same order
+	sort.Strings(backends)
+
+	successCtx, _ := tag.New(mCtx, tag.Upsert(tag.MustNewKey("success"), "true"))
+	stats.Record(successCtx, mNumResolutions.M(1))
+
+	if equalStringSlice(r.endpoints, backends) {
+		return backends, nil
+	}
+
+	// the list has changed!
+	r.endpoints = backends
+	stats.Record(mCtx, mNumBackends.M(int64(len(backends))))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+
+	return backends, nil
+}
+
+func (r *k8sResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newEndpoints(name, namespace string, ips ...string) *corev1.Endpoints {
+	var addresses []corev1.EndpointAddress
+	for _, ip := range ips {
+		addresses = append(addresses, corev1.EndpointAddress{IP: ip})
+	}
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: addresses,
+				NotReadyAddresses: []corev1.EndpointAddress{
+					{IP: "10.0.0.99"},
+				},
+			},
+		},
+	}
+}
+
+func TestK8sResolverNoService(t *testing.T) {
+	// test
+	res, err := newK8sResolver(fake.NewSimpleClientset(), zap.NewNop(), "", nil)
+
+	// verify
+	assert.Nil(t, res)
+	assert.Equal(t, errNoSvc, err)
+}
+
+func TestK8sResolverServiceName(t *testing.T) {
+	for _, tt := range []struct {
+		service   string
+		name      string
+		namespace string
+	}{
+		{service: "lb", name: "lb", namespace: "default"},
+		{service: "lb.observability", name: "lb", namespace: "observability"},
+		{service: "lb.observability.svc.cluster.local", name: "lb", namespace: "observability"},
+	} {
+		t.Run(tt.service, func(t *testing.T) {
+			res, err := newK8sResolver(fake.NewSimpleClientset(), zap.NewNop(), tt.service, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, res.svcName)
+			assert.Equal(t, tt.namespace, res.svcNs)
+		})
+	}
+}
+
+func TestInitialK8sResolution(t *testing.T) {
+	// prepare
+	clt := fake.NewSimpleClientset(
+		newEndpoints("lb", "observability", "10.0.0.2", "10.0.0.1", "fd00::1"),
+		newEndpoints("other", "observability", "10.0.0.3"),
+		newEndpoints("lb", "default", "10.0.0.4"),
+	)
+	res, err := newK8sResolver(clt, zap.NewNop(), "lb.observability", []int32{4317, 14250})
+	require.NoError(t, err)
+
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		resolved = endpoints
+	})
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+
+	// verify
+	assert.Equal(t, []string{
+		"10.0.0.1:14250", "10.0.0.1:4317",
+		"10.0.0.2:14250", "10.0.0.2:4317",
+		"[fd00::1]:14250", "[fd00::1]:4317",
+	}, resolved)
+}
+
+func TestK8sResolverWatchesChanges(t *testing.T) {
+	// prepare
+	clt := fake.NewSimpleClientset(newEndpoints("lb", "default", "10.0.0.1", "10.0.0.2"))
+	res, err := newK8sResolver(clt, zap.NewNop(), "lb", nil)
+	require.NoError(t, err)
+
+	var (
+		mu       sync.Mutex
+		resolved []string
+	)
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+	lastResolved := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return resolved
+	}
+
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, lastResolved())
+
+	// test: a pod is replaced during a rollout
+	_, err = clt.CoreV1().Endpoints("default").Update(context.Background(), newEndpoints("lb", "default", "10.0.0.2", "10.0.0.3"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"10.0.0.2", "10.0.0.3"}, lastResolved())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// test: the service is removed
+	require.NoError(t, clt.CoreV1().Endpoints("default").Delete(context.Background(), "lb", metav1.DeleteOptions{}))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		return len(lastResolved()) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestK8sResolverNoEndpoints(t *testing.T) {
+	// prepare
+	res, err := newK8sResolver(fake.NewSimpleClientset(), zap.NewNop(), "lb", []int32{4317})
+	require.NoError(t, err)
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	resolved, err := res.resolve(context.Background())
+
+	// verify
+	assert.NoError(t, err)
+	assert.Empty(t, resolved)
+}
@@ -37,6 +37,7 @@ var _ component.TracesExporter = (*traceExporterImp)(nil)
 
 type traceExporterImp struct {
 	loadBalancer loadBalancer
+	backends     *backendFactory
 	routingKey   routingKey
 
 	stopped    bool
@@ -45,17 +46,14 @@ type traceExporterImp struct {
 
 // Create new traces exporter
 func newTracesExporter(params component.ExporterCreateSettings, cfg config.Exporter) (*traceExporterImp, error) {
-	exporterFactory := otlpexporter.NewFactory()
+	backends := newBackendFactory(params, cfg.(*Config))
 
-	lb, err := newLoadBalancer(params, cfg, func(ctx context.Context, endpoint string) (component.Exporter, error) {
-		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
-		return exporterFactory.CreateTracesExporter(ctx, params, &oCfg)
-	})
+	lb, err := newLoadBalancer(params, cfg, backends.createTracesExporter)
 	if err != nil {
 		return nil, err
 	}
 
-	traceExporter := traceExporterImp{loadBalancer: lb, routingKey: traceIDRouting}
+	traceExporter := traceExporterImp{loadBalancer: lb, backends: backends, routingKey: traceIDRouting}
 
 	switch cfg.(*Config).RoutingKey {
 	case "service":
@@ -79,6 +77,9 @@ func (e *traceExporterImp) Capabilities() consumer.Capabilities {
 }
 
 func (e *traceExporterImp) Start(ctx context.Context, host component.Host) error {
+	if err := e.backends.start(host); err != nil {
+		return err
+	}
 	return e.loadBalancer.Start(ctx, host)
 }
 
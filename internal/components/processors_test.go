@@ -113,6 +113,10 @@ func TestDefaultProcessors(t *testing.T) {
 			processor:     "routing",
 			skipLifecycle: true, // Requires external exporters to be configured to route data
 		},
+		{
+			processor:     "servicegraph",
+			skipLifecycle: true, // Requires a metrics exporter to be configured to export the graph
+		},
 		{
 			processor: "span",
 			getConfigFn: func() config.Processor {
@@ -286,6 +286,9 @@ func TestDefaultReceivers(t *testing.T) {
 		{
 			receiver: "skywalking",
 		},
+		{
+			receiver: "spanmetrics",
+		},
 		{
 			receiver: "splunk_hec",
 		},
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	afterReadDelete = "delete"
+	afterReadMove   = "move"
+
+	defaultQuietPeriod = time.Minute
+)
+
+// AfterReadConfig configures what to do with the files once they have been fully consumed
+type AfterReadConfig struct {
+	Action      string        `mapstructure:"action,omitempty"       json:"action,omitempty"       yaml:"action,omitempty"`
+	MoveTo      string        `mapstructure:"move_to,omitempty"      json:"move_to,omitempty"      yaml:"move_to,omitempty"`
+	QuietPeriod time.Duration `mapstructure:"quiet_period,omitempty" json:"quiet_period,omitempty" yaml:"quiet_period,omitempty"`
+}
+
+func (c AfterReadConfig) validate(startAtBeginning bool) error {
+	switch c.Action {
+	case "":
+		return nil
+	case afterReadDelete:
+	case afterReadMove:
+		if c.MoveTo == "" {
+			return fmt.Errorf("`move_to` is required for the '%s' action", afterReadMove)
+		}
+	default:
+		return fmt.Errorf("invalid action '%s'", c.Action)
+	}
+	if c.QuietPeriod < 0 {
+		return fmt.Errorf("`quiet_period` must not be negative")
+	}
+	if !startAtBeginning {
+		return fmt.Errorf("`start_at` must be 'beginning', otherwise the files found at startup would be removed without being read")
+	}
+	return nil
+}
+
+// consumed returns whether the file has been read to the end and left untouched for the quiet period
+func (r *Reader) consumed(quietPeriod time.Duration) bool {
+	if r.file == nil {
+		return false
+	}
+	info, err := r.file.Stat()
+	if err != nil {
+		return false
+	}
+	if time.Since(info.ModTime()) < quietPeriod {
+		return false
+	}
+	if r.compressionFormat != "" {
+		return r.Exhausted
+	}
+	return r.Offset >= info.Size()
+}
+
+// finishConsumedFiles deletes or moves the files which have been consumed,
+// and returns the readers of the other files
+func (m *Manager) finishConsumedFiles(readers []*Reader) []*Reader {
+	if m.afterRead.Action == "" {
+		return readers
+	}
+
+	remaining := make([]*Reader, 0, len(readers))
+	for _, reader := range readers {
+		if !reader.consumed(m.afterRead.QuietPeriod) {
+			remaining = append(remaining, reader)
+			continue
+		}
+
+		path := reader.file.Name()
+		if err := m.afterRead.apply(path); err != nil {
+			// Keep the reader open, so the offset of the file isn't forgotten
+			m.Errorw("Failed to finish consumed file", "path", path, "action", m.afterRead.Action, zap.Error(err))
+			remaining = append(remaining, reader)
+			continue
+		}
+		reader.Close()
+		m.Debugw("Finished consumed file", "path", path, "action", m.afterRead.Action)
+	}
+	return remaining
+}
+
+func (c AfterReadConfig) apply(path string) error {
+	switch c.Action {
+	case afterReadDelete:
+		return os.Remove(path)
+	case afterReadMove:
+		if err := os.MkdirAll(c.MoveTo, 0700); err != nil {
+			return err
+		}
+		target, err := uniquePath(c.MoveTo, filepath.Base(path))
+		if err != nil {
+			return err
+		}
+		return os.Rename(path, target)
+	}
+	return nil
+}
+
+// uniquePath returns the path of the file named base in dir, or, if a file of this name already exists,
+// e.g. a file with the same name moved from another directory, the first free path with a numeric suffix
+// inserted before the extension, e.g. app.1.log
+func uniquePath(dir, base string) (string, error) {
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	for i := 0; ; i++ {
+		path := filepath.Join(dir, base)
+		if i > 0 {
+			path = filepath.Join(dir, name+"."+strconv.Itoa(i)+ext)
+		}
+		if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+			return path, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestAfterReadConfigValidate(t *testing.T) {
+	cases := []struct {
+		name             string
+		cfg              AfterReadConfig
+		startAtBeginning bool
+		err              string
+	}{
+		{
+			name: "Disabled",
+		},
+		{
+			name:             "Delete",
+			cfg:              AfterReadConfig{Action: afterReadDelete},
+			startAtBeginning: true,
+		},
+		{
+			name:             "Move",
+			cfg:              AfterReadConfig{Action: afterReadMove, MoveTo: "/archive", QuietPeriod: time.Hour},
+			startAtBeginning: true,
+		},
+		{
+			name:             "MoveWithoutDirectory",
+			cfg:              AfterReadConfig{Action: afterReadMove},
+			startAtBeginning: true,
+			err:              "`move_to` is required for the 'move' action",
+		},
+		{
+			name:             "InvalidAction",
+			cfg:              AfterReadConfig{Action: "truncate"},
+			startAtBeginning: true,
+			err:              "invalid action 'truncate'",
+		},
+		{
+			name:             "NegativeQuietPeriod",
+			cfg:              AfterReadConfig{Action: afterReadDelete, QuietPeriod: -time.Second},
+			startAtBeginning: true,
+			err:              "`quiet_period` must not be negative",
+		},
+		{
+			name: "StartAtEnd",
+			cfg:  AfterReadConfig{Action: afterReadDelete},
+			err:  "`start_at` must be 'beginning', otherwise the files found at startup would be removed without being read",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.validate(tc.startAtBeginning)
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// AfterReadDelete tests that files are deleted once read to the end
+// and left untouched for the quiet period
+func TestAfterReadDelete(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AfterRead = AfterReadConfig{Action: afterReadDelete, QuietPeriod: time.Hour}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\n")
+
+	// The file has just been written, so it's kept
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	require.FileExists(t, temp.Name())
+
+	writeString(t, temp, "testlog2\n")
+	setModTime(t, temp.Name(), time.Now().Add(-2*time.Hour))
+
+	// The file is deleted after its last entries are read
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	require.NoFileExists(t, temp.Name())
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+}
+
+// AfterReadPartialEntry tests that files are kept while their last entry isn't complete
+func TestAfterReadPartialEntry(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Splitter.Flusher.Period = 0
+	cfg.AfterRead = AfterReadConfig{Action: afterReadDelete, QuietPeriod: time.Millisecond}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\ntestlog2")
+	setModTime(t, temp.Name(), time.Now().Add(-time.Hour))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	require.FileExists(t, temp.Name())
+}
+
+// AfterReadMove tests that files are moved to the archive directory once consumed
+func TestAfterReadMove(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AfterRead = AfterReadConfig{Action: afterReadMove, MoveTo: archiveDir, QuietPeriod: time.Millisecond}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\n")
+	setModTime(t, temp.Name(), time.Now().Add(-time.Hour))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	require.NoFileExists(t, temp.Name())
+
+	content, err := os.ReadFile(filepath.Join(archiveDir, filepath.Base(temp.Name())))
+	require.NoError(t, err)
+	require.Equal(t, "testlog1\n", string(content))
+}
+
+// AfterReadMoveSameName tests that files with the same name from different directories don't overwrite each other
+func TestAfterReadMoveSameName(t *testing.T) {
+	t.Parallel()
+
+	tempDir1 := t.TempDir()
+	tempDir2 := t.TempDir()
+	archiveDir := filepath.Join(t.TempDir(), "archive")
+	cfg := NewConfig().includeDir(tempDir1).includeDir(tempDir2)
+	cfg.StartAt = "beginning"
+	cfg.AfterRead = AfterReadConfig{Action: afterReadMove, MoveTo: archiveDir, QuietPeriod: time.Millisecond}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	for i, dir := range []string{tempDir1, tempDir2} {
+		path := filepath.Join(dir, "app.log")
+		require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("testlog%d\n", i+1)), 0600))
+		setModTime(t, path, time.Now().Add(-time.Hour))
+	}
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+
+	contents := make([]string, 0, 2)
+	for _, name := range []string{"app.log", "app.1.log"} {
+		content, err := os.ReadFile(filepath.Join(archiveDir, name))
+		require.NoError(t, err)
+		contents = append(contents, string(content))
+	}
+	require.ElementsMatch(t, []string{"testlog1\n", "testlog2\n"}, contents)
+}
+
+// AfterReadFailure tests that the file is kept open when it can't be finished
+func TestAfterReadFailure(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Readers are closed after each poll on windows")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	// move_to can't be created, as its parent is a regular file
+	notADir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notADir, nil, 0600))
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.AfterRead = AfterReadConfig{Action: afterReadMove, MoveTo: filepath.Join(notADir, "archive"), QuietPeriod: time.Millisecond}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\n")
+	setModTime(t, temp.Name(), time.Now().Add(-time.Hour))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	require.FileExists(t, temp.Name())
+
+	require.Len(t, operator.knownFiles, 1)
+	_, err := operator.knownFiles[0].file.Stat()
+	require.NoError(t, err, "the reader of the file must not be closed")
+
+	// The file is still tracked, so its entries aren't read again
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+}
+
+// AfterReadCompressed tests that compressed files are finished once exhausted
+func TestAfterReadCompressed(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = map[string][]string{compressionGzip: {".gz"}}
+	cfg.AfterRead = AfterReadConfig{Action: afterReadDelete, QuietPeriod: time.Millisecond}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "app.log.gz")
+	writeCompressed(t, path, compressionGzip, "testlog1\ntestlog2\n")
+	setModTime(t, path, time.Now().Add(-time.Hour))
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+	require.NoFileExists(t, path)
+}
+
+func setModTime(t testing.TB, path string, mtime time.Time) {
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
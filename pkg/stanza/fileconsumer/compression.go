@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	compressionGzip = "gzip"
+	compressionZstd = "zstd"
+)
+
+// compressionSuffixes maps the file name suffixes to the compression format of the files
+type compressionSuffixes map[string]string
+
+func newCompressionSuffixes(formats map[string][]string) (compressionSuffixes, error) {
+	suffixes := make(compressionSuffixes)
+	for format, formatSuffixes := range formats {
+		switch format {
+		case compressionGzip, compressionZstd:
+		default:
+			return nil, fmt.Errorf("unsupported compression format '%s'", format)
+		}
+		if len(formatSuffixes) == 0 {
+			return nil, fmt.Errorf("no file name suffix configured for the '%s' compression format", format)
+		}
+		for _, suffix := range formatSuffixes {
+			if suffix == "" {
+				return nil, fmt.Errorf("empty file name suffix configured for the '%s' compression format", format)
+			}
+			if other, ok := suffixes[suffix]; ok {
+				return nil, fmt.Errorf("file name suffix '%s' configured for both the '%s' and '%s' compression formats", suffix, other, format)
+			}
+			suffixes[suffix] = format
+		}
+	}
+	return suffixes, nil
+}
+
+// format returns the compression format of the file, or an empty string when it isn't compressed.
+// When several suffixes match, the longest one wins.
+func (c compressionSuffixes) format(path string) string {
+	var format, longest string
+	for suffix, f := range c {
+		if strings.HasSuffix(path, suffix) && len(suffix) > len(longest) {
+			format, longest = f, suffix
+		}
+	}
+	return format
+}
+
+// newDecompressor returns a reader of the decompressed content of the file, starting from its beginning
+// regardless of the current offset of the file
+func newDecompressor(file *os.File, format string) (io.ReadCloser, error) {
+	src := io.NewSectionReader(file, 0, math.MaxInt64)
+	switch format {
+	case compressionGzip:
+		return gzip.NewReader(src)
+	case compressionZstd:
+		dec, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true))
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression format '%s'", format)
+	}
+}
+
+// newCompressedFingerprint creates a fingerprint from the first bytes of the decompressed content,
+// so that a file compressed when rotated keeps the fingerprint it had before
+func newCompressedFingerprint(file *os.File, format string, size int) (*Fingerprint, error) {
+	dec, err := newDecompressor(file, format)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The file is empty or its header isn't fully written yet
+		return &Fingerprint{FirstBytes: []byte{}}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("decompressing fingerprint bytes: %w", err)
+	}
+	defer dec.Close()
+
+	buf := make([]byte, size)
+	n, err := io.ReadFull(dec, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+
+	return &Fingerprint{FirstBytes: buf[:n]}, nil
+}
@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func TestNewCompressionSuffixes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		formats  map[string][]string
+		expected compressionSuffixes
+		err      string
+	}{
+		{
+			name:     "None",
+			expected: compressionSuffixes{},
+		},
+		{
+			name:     "GzipAndZstd",
+			formats:  map[string][]string{"gzip": {".gz"}, "zstd": {".zst", ".zstd"}},
+			expected: compressionSuffixes{".gz": "gzip", ".zst": "zstd", ".zstd": "zstd"},
+		},
+		{
+			name:    "UnsupportedFormat",
+			formats: map[string][]string{"bzip2": {".bz2"}},
+			err:     "unsupported compression format 'bzip2'",
+		},
+		{
+			name:    "NoSuffix",
+			formats: map[string][]string{"gzip": {}},
+			err:     "no file name suffix configured for the 'gzip' compression format",
+		},
+		{
+			name:    "EmptySuffix",
+			formats: map[string][]string{"gzip": {""}},
+			err:     "empty file name suffix configured for the 'gzip' compression format",
+		},
+		{
+			name:    "DuplicateSuffix",
+			formats: map[string][]string{"gzip": {".z", ".z"}},
+			err:     "file name suffix '.z' configured for both the 'gzip' and 'gzip' compression formats",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			suffixes, err := newCompressionSuffixes(tc.formats)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, suffixes)
+		})
+	}
+}
+
+func TestCompressionSuffixesFormat(t *testing.T) {
+	suffixes := compressionSuffixes{".gz": "gzip", ".log.gz": "zstd"}
+	require.Equal(t, "", suffixes.format("/var/log/app.log"))
+	require.Equal(t, "gzip", suffixes.format("/var/log/app.txt.gz"))
+	require.Equal(t, "zstd", suffixes.format("/var/log/app.log.gz"))
+	require.Equal(t, "", compressionSuffixes(nil).format("/var/log/app.log.gz"))
+}
+
+// ReadCompressedFiles tests that compressed files are decompressed, read to the end once,
+// including their last unterminated entry, and not read again after a restart
+func TestReadCompressedFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{compressionGzip, compressionZstd} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			cfg := NewConfig().includeDir(tempDir)
+			cfg.StartAt = "beginning"
+			cfg.Compression = map[string][]string{format: {".log.z"}}
+			operator, emitCalls := buildTestManager(t, cfg)
+			persister := testutil.NewMockPersister("test")
+			operator.persister = persister
+
+			writeCompressed(t, filepath.Join(tempDir, "app.log.z"), format, "testlog1\ntestlog2\ntestlog3")
+
+			operator.poll(context.Background())
+			waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2"), []byte("testlog3")})
+
+			operator.poll(context.Background())
+			expectNoTokens(t, emitCalls)
+			require.NoError(t, operator.Stop())
+
+			// The state of the compressed file is persisted
+			operatorTwo, emitCallsTwo := buildTestManager(t, cfg)
+			require.NoError(t, operatorTwo.Start(persister))
+			defer func() {
+				require.NoError(t, operatorTwo.Stop())
+			}()
+			expectNoTokens(t, emitCallsTwo)
+		})
+	}
+}
+
+// PartiallyWrittenCompressedFile tests that the last entry of a compressed file
+// which is still being written isn't emitted before the file is complete
+func TestPartiallyWrittenCompressedFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = map[string][]string{compressionGzip: {".gz"}}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	// The stream is flushed in the middle of the second entry, so that its first part
+	// can be decompressed before the rest of the file is written
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte("testlog1\ntest"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Flush())
+	partial := buf.Len()
+	_, err = gz.Write([]byte("log2\ntestlog3\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	path := filepath.Join(tempDir, "app.log.1.gz")
+	require.NoError(t, os.WriteFile(path, buf.Bytes()[:partial], 0600))
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	expectNoTokens(t, emitCalls)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.Write(buf.Bytes()[partial:])
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog2"), []byte("testlog3")})
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+}
+
+// RotatedCompressedFile tests that a file compressed when rotated is read
+// from where the uncompressed file was left
+func TestRotatedCompressedFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig()
+	cfg.Include = []string{filepath.Join(tempDir, "app.log*")}
+	cfg.StartAt = "beginning"
+	cfg.Compression = map[string][]string{compressionGzip: {".gz"}}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openFile(t, filepath.Join(tempDir, "app.log"))
+	writeString(t, temp, "testlog1\ntestlog2\n")
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+
+	// The last entries are written just before the rotation
+	writeString(t, temp, "testlog3\ntestlog4\n")
+	require.NoError(t, temp.Close())
+	writeCompressed(t, filepath.Join(tempDir, "app.log.1.gz"), compressionGzip, "testlog1\ntestlog2\ntestlog3\ntestlog4\n")
+	require.NoError(t, os.Remove(temp.Name()))
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog3"), []byte("testlog4")})
+}
+
+// CompressedFileStartAtEnd tests that compressed files found at startup
+// are skipped when `start_at` is configured to `end`
+func TestCompressedFileStartAtEnd(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.Compression = map[string][]string{compressionZstd: {".zst"}}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	writeCompressed(t, filepath.Join(tempDir, "old.log.zst"), compressionZstd, "testlog1\n")
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+
+	// Compressed files appearing later are read from the beginning
+	writeCompressed(t, filepath.Join(tempDir, "new.log.zst"), compressionZstd, "testlog2\n")
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+}
+
+func writeCompressed(t testing.TB, path, format, content string) {
+	var buf bytes.Buffer
+	switch format {
+	case compressionGzip:
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	case compressionZstd:
+		w, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+	}
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0600))
+}
@@ -57,6 +57,9 @@ type Config struct {
 	FingerprintSize         helper.ByteSize       `mapstructure:"fingerprint_size,omitempty"               json:"fingerprint_size,omitempty"              yaml:"fingerprint_size,omitempty"`
 	MaxLogSize              helper.ByteSize       `mapstructure:"max_log_size,omitempty"                   json:"max_log_size,omitempty"                  yaml:"max_log_size,omitempty"`
 	MaxConcurrentFiles      int                   `mapstructure:"max_concurrent_files,omitempty"           json:"max_concurrent_files,omitempty"          yaml:"max_concurrent_files,omitempty"`
+	Compression             map[string][]string   `mapstructure:"compression,omitempty"                    json:"compression,omitempty"                   yaml:"compression,omitempty"`
+	AfterRead               AfterReadConfig       `mapstructure:"after_read,omitempty"                     json:"after_read,omitempty"                    yaml:"after_read,omitempty"`
+	Header                  *HeaderConfig         `mapstructure:"header,omitempty"                         json:"header,omitempty"                        yaml:"header,omitempty"`
 	Splitter                helper.SplitterConfig `mapstructure:",squash,omitempty"                        json:",inline,omitempty"                       yaml:",inline,omitempty"`
 }
 
@@ -86,6 +89,13 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		}
 	}
 
+	finder := c.Finder
+	ordering, err := c.OrderingCriteria.build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid `ordering_criteria`: %w", err)
+	}
+	finder.ordering = ordering
+
 	if c.MaxLogSize <= 0 {
 		return nil, fmt.Errorf("`max_log_size` must be positive")
 	}
@@ -100,8 +110,20 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		return nil, fmt.Errorf("`fingerprint_size` must be at least %d bytes", MinFingerprintSize)
 	}
 
+	compression, err := newCompressionSuffixes(c.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `compression`: %w", err)
+	}
+
+	var header *headerParser
+	if c.Header != nil {
+		if header, err = c.Header.build(logger); err != nil {
+			return nil, fmt.Errorf("invalid `header`: %w", err)
+		}
+	}
+
 	// Ensure that splitter is buildable
-	_, err := c.Splitter.Build(false, int(c.MaxLogSize))
+	_, err = c.Splitter.Build(false, int(c.MaxLogSize))
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +138,13 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		return nil, fmt.Errorf("invalid start_at location '%s'", c.StartAt)
 	}
 
+	if err := c.AfterRead.validate(startAtBeginning); err != nil {
+		return nil, fmt.Errorf("invalid `after_read`: %w", err)
+	}
+	if c.AfterRead.QuietPeriod == 0 {
+		c.AfterRead.QuietPeriod = defaultQuietPeriod
+	}
+
 	return &Manager{
 		SugaredLogger: logger.With("component", "fileconsumer"),
 		cancel:        func() {},
@@ -124,12 +153,15 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 			readerConfig: &readerConfig{
 				fingerprintSize: int(c.FingerprintSize),
 				maxLogSize:      int(c.MaxLogSize),
+				compression:     compression,
+				header:          header,
 				emit:            emit,
 			},
 			fromBeginning:  startAtBeginning,
 			splitterConfig: c.Splitter,
 		},
-		finder:        c.Finder,
+		finder:        finder,
+		afterRead:     c.AfterRead,
 		roller:        newRoller(),
 		pollInterval:  c.PollInterval,
 		maxBatchFiles: c.MaxConcurrentFiles / 2,
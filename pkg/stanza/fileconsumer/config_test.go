@@ -365,6 +365,62 @@ func TestUnmarshal(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			Name:      "compression",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.Compression = map[string][]string{
+					"gzip": {".gz"},
+					"zstd": {".zst", ".zstd"},
+				}
+				return cfg
+			}(),
+		},
+		{
+			Name:      "ordering_criteria",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.Include = []string{"/var/log/apps/*.log"}
+				cfg.OrderingCriteria = OrderingCriteria{
+					Regex:   `^(?P<app>[a-z]+)-(?P<date>\d{8})\.log$`,
+					GroupBy: "app",
+					TopN:    2,
+					SortBy: []SortRule{
+						{
+							SortType: "timestamp",
+							RegexKey: "date",
+							Layout:   "%Y%m%d",
+							Location: "UTC",
+						},
+					},
+				}
+				return cfg
+			}(),
+		},
+		{
+			Name:      "after_read",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.AfterRead = AfterReadConfig{
+					Action:      "move",
+					MoveTo:      "/var/log/archive",
+					QuietPeriod: 5 * time.Minute,
+				}
+				return cfg
+			}(),
+		},
+		{
+			Name:      "header",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.Header = w3cHeaderConfig()
+				return cfg
+			}(),
+		},
 		{
 			Name:      "encoding_lower",
 			ExpectErr: false,
@@ -508,6 +564,84 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"Compression",
+			func(f *Config) {
+				f.Compression = map[string][]string{"gzip": {".gz"}}
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.Equal(t, compressionSuffixes{".gz": "gzip"}, f.readerFactory.readerConfig.compression)
+			},
+		},
+		{
+			"InvalidCompressionFormat",
+			func(f *Config) {
+				f.Compression = map[string][]string{"lz4": {".lz4"}}
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"OrderingCriteria",
+			func(f *Config) {
+				f.OrderingCriteria = OrderingCriteria{
+					SortBy: []SortRule{{SortType: sortTypeMtime}},
+				}
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.NotNil(t, f.finder.ordering)
+				require.Equal(t, defaultOrderingTopN, f.finder.ordering.topN)
+			},
+		},
+		{
+			"InvalidOrderingCriteria",
+			func(f *Config) {
+				f.OrderingCriteria = OrderingCriteria{
+					SortBy: []SortRule{{SortType: "size"}},
+				}
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"AfterRead",
+			func(f *Config) {
+				f.StartAt = "beginning"
+				f.AfterRead = AfterReadConfig{Action: "delete"}
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.Equal(t, AfterReadConfig{Action: "delete", QuietPeriod: time.Minute}, f.afterRead)
+			},
+		},
+		{
+			"AfterReadStartAtEnd",
+			func(f *Config) {
+				f.AfterRead = AfterReadConfig{Action: "delete"}
+			},
+			require.Error,
+			nil,
+		},
+		{
+			"Header",
+			func(f *Config) {
+				f.Header = w3cHeaderConfig()
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.NotNil(t, f.readerFactory.readerConfig.header)
+			},
+		},
+		{
+			"InvalidHeader",
+			func(f *Config) {
+				f.Header = &HeaderConfig{Pattern: "^#"}
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"InvalidLineEndRegex",
 			func(f *Config) {
@@ -37,6 +37,7 @@ type Manager struct {
 
 	readerFactory readerFactory
 	finder        Finder
+	afterRead     AfterReadConfig
 	roller        roller
 	persister     operator.Persister
 
@@ -63,6 +64,12 @@ func (m *Manager) Start(persister operator.Persister) error {
 			"exclude", m.finder.Exclude)
 	}
 
+	if header := m.readerFactory.readerConfig.header; header != nil {
+		if err := header.pipeline.Start(persister); err != nil {
+			return fmt.Errorf("start header metadata operators: %w", err)
+		}
+	}
+
 	// Start polling goroutine
 	m.startPoller(ctx)
 
@@ -79,6 +86,9 @@ func (m *Manager) Stop() error {
 	}
 	m.knownFiles = nil
 	m.cancel = nil
+	if header := m.readerFactory.readerConfig.header; header != nil {
+		return header.pipeline.Stop()
+	}
 	return nil
 }
 
@@ -139,6 +149,9 @@ func (m *Manager) consume(ctx context.Context, paths []string) {
 	}
 	wg.Wait()
 
+	// Files which have been fully consumed are deleted or moved, and don't need to be tracked anymore
+	readers = m.finishConsumedFiles(readers)
+
 	// Any new files that appear should be consumed entirely
 	m.readerFactory.fromBeginning = true
 
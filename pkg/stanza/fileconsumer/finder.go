@@ -21,6 +21,11 @@ import (
 type Finder struct {
 	Include []string `mapstructure:"include,omitempty" json:"include,omitempty" yaml:"include,omitempty"`
 	Exclude []string `mapstructure:"exclude,omitempty" json:"exclude,omitempty" yaml:"exclude,omitempty"`
+
+	OrderingCriteria OrderingCriteria `mapstructure:"ordering_criteria,omitempty" json:"ordering_criteria,omitempty" yaml:"ordering_criteria,omitempty"`
+
+	// ordering is compiled from OrderingCriteria in build
+	ordering *ordering
 }
 
 // FindFiles gets a list of paths given an array of glob patterns to include and exclude
@@ -46,5 +51,8 @@ func (f Finder) FindFiles() []string {
 		}
 	}
 
-	return all
+	if f.ordering == nil {
+		return all
+	}
+	return f.ordering.apply(all)
 }
@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -137,7 +138,7 @@ func TestFinder(t *testing.T) {
 				require.NoError(t, os.WriteFile(f, []byte(filepath.Base(f)), 0000))
 			}
 
-			finder := Finder{include, exclude}
+			finder := Finder{Include: include, Exclude: exclude}
 			require.ElementsMatch(t, finder.FindFiles(), expected)
 		})
 	}
@@ -150,3 +151,122 @@ func absPath(tempDir string, files []string) []string {
 	}
 	return absFiles
 }
+
+func TestFinderOrderingCriteria(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		files    []string
+		criteria OrderingCriteria
+		expected []string
+	}{
+		{
+			name:  "TimestampNewestFirst",
+			files: []string{"app-2022-09-30.log", "app-2022-10-01.log", "app-2022-09-29.log"},
+			criteria: OrderingCriteria{
+				Regex:  `app-(?P<date>\d{4}-\d{2}-\d{2})\.log`,
+				SortBy: []SortRule{{SortType: sortTypeTimestamp, RegexKey: "date", Layout: "%Y-%m-%d"}},
+			},
+			expected: []string{"app-2022-10-01.log"},
+		},
+		{
+			name:  "TimestampTopN",
+			files: []string{"app-2022-09-30.log", "app-2022-10-01.log", "app-2022-09-29.log"},
+			criteria: OrderingCriteria{
+				Regex:  `app-(?P<date>\d{4}-\d{2}-\d{2})\.log`,
+				TopN:   2,
+				SortBy: []SortRule{{SortType: sortTypeTimestamp, RegexKey: "date", Layout: "%Y-%m-%d", Location: "UTC"}},
+			},
+			expected: []string{"app-2022-10-01.log", "app-2022-09-30.log"},
+		},
+		{
+			name:  "NumericAscending",
+			files: []string{"app.10.log", "app.9.log", "app.100.log"},
+			criteria: OrderingCriteria{
+				Regex:  `app\.(?P<num>\d+)\.log`,
+				TopN:   2,
+				SortBy: []SortRule{{SortType: sortTypeNumeric, RegexKey: "num", Ascending: true}},
+			},
+			expected: []string{"app.9.log", "app.10.log"},
+		},
+		{
+			name:  "Alphabetical",
+			files: []string{"app-b.log", "app-c.log", "app-a.log"},
+			criteria: OrderingCriteria{
+				Regex:  `app-(?P<name>\w+)\.log`,
+				SortBy: []SortRule{{SortType: sortTypeAlphabetical, RegexKey: "name"}},
+			},
+			expected: []string{"app-c.log"},
+		},
+		{
+			name:  "GroupBy",
+			files: []string{"api-2.log", "api-3.log", "web-1.log", "web-5.log", "db-4.log"},
+			criteria: OrderingCriteria{
+				Regex:   `(?P<app>\w+)-(?P<num>\d+)\.log`,
+				GroupBy: "app",
+				SortBy:  []SortRule{{SortType: sortTypeNumeric, RegexKey: "num"}},
+			},
+			expected: []string{"api-3.log", "db-4.log", "web-5.log"},
+		},
+		{
+			name:  "MultipleRules",
+			files: []string{"app-2022-10-01.1.log", "app-2022-10-01.2.log", "app-2022-09-30.3.log"},
+			criteria: OrderingCriteria{
+				Regex: `app-(?P<date>\d{4}-\d{2}-\d{2})\.(?P<num>\d+)\.log`,
+				TopN:  2,
+				SortBy: []SortRule{
+					{SortType: sortTypeTimestamp, RegexKey: "date", Layout: "%Y-%m-%d"},
+					{SortType: sortTypeNumeric, RegexKey: "num", Ascending: true},
+				},
+			},
+			expected: []string{"app-2022-10-01.1.log", "app-2022-10-01.2.log"},
+		},
+		{
+			name:  "NotMatchingRegex",
+			files: []string{"app.1.log", "app.2.log", "other.log", "app.x.log"},
+			criteria: OrderingCriteria{
+				Regex:  `app\.(?P<num>\w+)\.log`,
+				TopN:   10,
+				SortBy: []SortRule{{SortType: sortTypeNumeric, RegexKey: "num"}},
+			},
+			expected: []string{"app.2.log", "app.1.log"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			for _, f := range absPath(tempDir, tc.files) {
+				require.NoError(t, os.WriteFile(f, []byte(filepath.Base(f)), 0000))
+			}
+			ordering, err := tc.criteria.build()
+			require.NoError(t, err)
+
+			finder := Finder{Include: []string{filepath.Join(tempDir, "*")}, OrderingCriteria: tc.criteria, ordering: ordering}
+			require.Equal(t, absPath(tempDir, tc.expected), finder.FindFiles())
+		})
+	}
+}
+
+func TestFinderOrderingByMtime(t *testing.T) {
+	t.Parallel()
+	tempDir := t.TempDir()
+	files := absPath(tempDir, []string{"a.log", "b.log", "c.log"})
+	now := time.Now()
+	for i, f := range files {
+		require.NoError(t, os.WriteFile(f, []byte(filepath.Base(f)), 0600))
+		mtime := now.Add(time.Duration(i%2) * time.Hour)
+		require.NoError(t, os.Chtimes(f, mtime, mtime))
+	}
+
+	criteria := OrderingCriteria{
+		TopN:   2,
+		SortBy: []SortRule{{SortType: sortTypeMtime}},
+	}
+	ordering, err := criteria.build()
+	require.NoError(t, err)
+
+	finder := Finder{Include: []string{filepath.Join(tempDir, "*")}, OrderingCriteria: criteria, ordering: ordering}
+	require.Equal(t, absPath(tempDir, []string{"b.log", "a.log"}), finder.FindFiles())
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/pipeline"
+)
+
+const headerCollectorType = "file_header_collector"
+
+// HeaderConfig configures how the header lines at the beginning of the files are parsed into metadata
+type HeaderConfig struct {
+	Pattern string `mapstructure:"pattern" json:"pattern" yaml:"pattern"`
+	// MetadataOperators are decoded when building, as stanza operators can't be unmarshaled by mapstructure
+	MetadataOperators []map[string]interface{} `mapstructure:"metadata_operators" json:"metadata_operators" yaml:"metadata_operators"`
+}
+
+func (c HeaderConfig) decodeMetadataOperators() ([]operator.Config, error) {
+	yamlBytes, err := yaml.Marshal(c.MetadataOperators)
+	if err != nil {
+		return nil, err
+	}
+	operatorCfgs := []operator.Config{}
+	if err := yaml.Unmarshal(yamlBytes, &operatorCfgs); err != nil {
+		return nil, err
+	}
+	return operatorCfgs, nil
+}
+
+func (c HeaderConfig) build(logger *zap.SugaredLogger) (*headerParser, error) {
+	if c.Pattern == "" {
+		return nil, errors.New("`pattern` is required")
+	}
+	regex, err := regexp.Compile(c.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling pattern: %w", err)
+	}
+
+	if len(c.MetadataOperators) == 0 {
+		return nil, errors.New("at least one of the `metadata_operators` is required")
+	}
+	operatorCfgs, err := c.decodeMetadataOperators()
+	if err != nil {
+		return nil, fmt.Errorf("decoding `metadata_operators`: %w", err)
+	}
+
+	outputOperator, err := helper.NewOutputConfig(headerCollectorType, headerCollectorType).Build(logger)
+	if err != nil {
+		return nil, err
+	}
+	collector := &headerCollector{OutputOperator: outputOperator}
+
+	metadataPipeline, err := pipeline.Config{
+		Operators:     operatorCfgs,
+		DefaultOutput: collector,
+	}.Build(logger)
+	if err != nil {
+		return nil, fmt.Errorf("building `metadata_operators`: %w", err)
+	}
+
+	return &headerParser{
+		regex:     regex,
+		pipeline:  metadataPipeline,
+		first:     metadataPipeline.Operators()[0],
+		collector: collector,
+	}, nil
+}
+
+// headerParser passes the header lines through the metadata operators.
+// The operators are shared by all the files, so the headers are parsed one file at a time.
+type headerParser struct {
+	mu        sync.Mutex
+	regex     *regexp.Regexp
+	pipeline  *pipeline.DirectedPipeline
+	first     operator.Operator
+	collector *headerCollector
+}
+
+// parse returns the attributes of the entries created from the header lines
+func (h *headerParser) parse(ctx context.Context, lines [][]byte) (map[string]interface{}, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.collector.attributes = make(map[string]interface{})
+	var errs error
+	for _, line := range lines {
+		ent := entry.New()
+		ent.Body = string(line)
+		errs = multierr.Append(errs, h.first.Process(ctx, ent))
+	}
+	return h.collector.attributes, errs
+}
+
+// headerCollector is the output of the metadata operators, collecting the attributes of the header entries
+type headerCollector struct {
+	helper.OutputOperator
+	attributes map[string]interface{}
+}
+
+// Process merges the fields parsed into the body, and the attributes of the entry, into the header attributes
+func (c *headerCollector) Process(_ context.Context, ent *entry.Entry) error {
+	if body, ok := ent.Body.(map[string]interface{}); ok {
+		for k, v := range body {
+			c.attributes[k] = v
+		}
+	}
+	for k, v := range ent.Attributes {
+		c.attributes[k] = v
+	}
+	return nil
+}
+
+// readHeader parses the header lines at the beginning of the file, and makes sure the offset is after them.
+// It returns false while the end of the header hasn't been written yet.
+func (r *Reader) readHeader(ctx context.Context) bool {
+	var src io.Reader = io.NewSectionReader(r.file, 0, math.MaxInt64)
+	if r.compressionFormat != "" {
+		dec, err := newDecompressor(r.file, r.compressionFormat)
+		if err != nil {
+			r.Errorw("Failed to decompress header", zap.Error(err))
+			return false
+		}
+		defer dec.Close()
+		src = dec
+	}
+
+	splitFunc, err := helper.NewNewlineSplitFunc(r.splitter.Encoding.Encoding, false)
+	if err != nil {
+		r.Errorw("Failed to split header", zap.Error(err))
+		return false
+	}
+	scanner := NewPositionalScanner(src, r.maxLogSize, 0, splitFunc)
+
+	lines := make([][]byte, 0)
+	var headerEnd int64
+	for scanner.Scan() {
+		line, err := r.splitter.Encoding.Decode(scanner.Bytes())
+		if err != nil {
+			r.Errorw("Failed to decode header", zap.Error(err))
+			return false
+		}
+		if r.header.regex.Match(line) {
+			lines = append(lines, line)
+			headerEnd = scanner.Pos()
+			continue
+		}
+
+		// The first line not matching the pattern ends the header
+		attributes, err := r.header.parse(ctx, lines)
+		if err != nil {
+			r.Errorw("Failed to parse header", zap.Error(err))
+		}
+		r.headerAttributes = attributes
+		r.fileAttributes.HeaderAttributes = attributes
+		if r.Offset < headerEnd {
+			r.Offset = headerEnd
+		}
+		return true
+	}
+
+	if err := scanner.getError(); err != nil {
+		r.Errorw("Failed during header scan", zap.Error(err))
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	_ "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/regex" // register the regex parser used in the headers
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+// w3cHeaderConfig parses the `#Fields` line of W3C extended log files into the `fields` header attribute
+func w3cHeaderConfig() *HeaderConfig {
+	return &HeaderConfig{
+		Pattern: "^#",
+		MetadataOperators: []map[string]interface{}{
+			{
+				"type":  "regex_parser",
+				"if":    `body matches "^#Fields: "`,
+				"regex": `^#Fields: (?P<fields>.*)$`,
+			},
+		},
+	}
+}
+
+func TestHeaderConfigBuild(t *testing.T) {
+	cases := []struct {
+		name   string
+		header *HeaderConfig
+		err    string
+	}{
+		{
+			name:   "Valid",
+			header: w3cHeaderConfig(),
+		},
+		{
+			name:   "MissingPattern",
+			header: &HeaderConfig{MetadataOperators: w3cHeaderConfig().MetadataOperators},
+			err:    "`pattern` is required",
+		},
+		{
+			name:   "InvalidPattern",
+			header: &HeaderConfig{Pattern: "(", MetadataOperators: w3cHeaderConfig().MetadataOperators},
+			err:    "compiling pattern: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:   "UnknownOperator",
+			header: &HeaderConfig{Pattern: "^#", MetadataOperators: []map[string]interface{}{{"type": "unknown"}}},
+			err:    "decoding `metadata_operators`: unsupported type 'unknown'",
+		},
+		{
+			name:   "NoOperator",
+			header: &HeaderConfig{Pattern: "^#"},
+			err:    "at least one of the `metadata_operators` is required",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.header.build(testutil.Logger(t))
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// HeaderMetadata tests that the header lines aren't emitted,
+// and that the attributes parsed from them are attached to the entries
+func TestHeaderMetadata(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Header = w3cHeaderConfig()
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "#Version: 1.0\n#Fields: date time cs-method\n2022-10-01 00:00:00 GET\n")
+
+	operator.poll(context.Background())
+	call := waitForEmit(t, emitCalls)
+	require.Equal(t, []byte("2022-10-01 00:00:00 GET"), call.token)
+	require.Equal(t, map[string]interface{}{"fields": "date time cs-method"}, call.attrs.HeaderAttributes)
+
+	// The header is kept for the next entries
+	writeString(t, temp, "2022-10-01 00:00:01 POST\n")
+	operator.poll(context.Background())
+	call = waitForEmit(t, emitCalls)
+	require.Equal(t, []byte("2022-10-01 00:00:01 POST"), call.token)
+	require.Equal(t, map[string]interface{}{"fields": "date time cs-method"}, call.attrs.HeaderAttributes)
+	expectNoTokens(t, emitCalls)
+}
+
+// HeaderIncomplete tests that the entries aren't read until the end of the header is written
+func TestHeaderIncomplete(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Header = w3cHeaderConfig()
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "#Fields: date time\n")
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+
+	writeString(t, temp, "2022-10-01 00:00:00\n")
+	operator.poll(context.Background())
+	call := waitForEmit(t, emitCalls)
+	require.Equal(t, []byte("2022-10-01 00:00:00"), call.token)
+	require.Equal(t, map[string]interface{}{"fields": "date time"}, call.attrs.HeaderAttributes)
+}
+
+// HeaderWithoutHeaderLines tests that files without header lines are read as usual
+func TestHeaderWithoutHeaderLines(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Header = w3cHeaderConfig()
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "testlog1\n")
+	operator.poll(context.Background())
+	call := waitForEmit(t, emitCalls)
+	require.Equal(t, []byte("testlog1"), call.token)
+	require.Empty(t, call.attrs.HeaderAttributes)
+}
+
+// HeaderAfterRotation tests that the header of a new file replaces the header of the rotated one
+func TestHeaderAfterRotation(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig()
+	cfg.Include = []string{tempDir + "/app.log"}
+	cfg.StartAt = "beginning"
+	cfg.Header = w3cHeaderConfig()
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openFile(t, tempDir+"/app.log")
+	writeString(t, temp, "#Fields: date time\n2022-10-01 00:00:00\n")
+	operator.poll(context.Background())
+	call := waitForEmit(t, emitCalls)
+	require.Equal(t, map[string]interface{}{"fields": "date time"}, call.attrs.HeaderAttributes)
+
+	require.NoError(t, temp.Close())
+	require.NoError(t, os.Rename(tempDir+"/app.log", tempDir+"/app.log.1"))
+	rotated := openFile(t, tempDir+"/app.log")
+	writeString(t, rotated, "#Fields: date time cs-method\n2022-10-02 00:00:00 GET\n")
+
+	operator.poll(context.Background())
+	call = waitForEmit(t, emitCalls)
+	require.Equal(t, []byte("2022-10-02 00:00:00 GET"), call.token)
+	require.Equal(t, map[string]interface{}{"fields": "date time cs-method"}, call.attrs.HeaderAttributes)
+}
+
+// HeaderAfterRestart tests that the header is read again when resuming from a known offset
+func TestHeaderAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Header = w3cHeaderConfig()
+	persister := testutil.NewMockPersister("test")
+
+	operatorOne, emitCallsOne := buildTestManager(t, cfg)
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "#Fields: date time\n2022-10-01 00:00:00\n")
+	require.NoError(t, operatorOne.Start(persister))
+	waitForToken(t, emitCallsOne, []byte("2022-10-01 00:00:00"))
+	require.NoError(t, operatorOne.Stop())
+
+	writeString(t, temp, "2022-10-01 00:00:01\n")
+	operatorTwo, emitCallsTwo := buildTestManager(t, cfg)
+	require.NoError(t, operatorTwo.Start(persister))
+	defer func() {
+		require.NoError(t, operatorTwo.Stop())
+	}()
+	call := waitForEmit(t, emitCallsTwo)
+	require.Equal(t, []byte("2022-10-01 00:00:01"), call.token)
+	require.Equal(t, map[string]interface{}{"fields": "date time"}, call.attrs.HeaderAttributes)
+	expectNoTokens(t, emitCallsTwo)
+}
+
+func TestHeaderCollector(t *testing.T) {
+	outputOperator, err := helper.NewOutputConfig(headerCollectorType, headerCollectorType).Build(testutil.Logger(t))
+	require.NoError(t, err)
+	collector := &headerCollector{OutputOperator: outputOperator, attributes: map[string]interface{}{}}
+
+	ent := entry.New()
+	ent.Body = map[string]interface{}{"fields": "date time"}
+	ent.Attributes = map[string]interface{}{"version": "1.0"}
+	require.NoError(t, collector.Process(context.Background(), ent))
+
+	ent = entry.New()
+	ent.Body = "#Remark: not parsed"
+	require.NoError(t, collector.Process(context.Background(), ent))
+
+	require.Equal(t, map[string]interface{}{"fields": "date time", "version": "1.0"}, collector.attributes)
+}
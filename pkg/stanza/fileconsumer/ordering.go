@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/observiq/ctimefmt"
+)
+
+const (
+	sortTypeNumeric      = "numeric"
+	sortTypeTimestamp    = "timestamp"
+	sortTypeAlphabetical = "alphabetical"
+	sortTypeMtime        = "mtime"
+
+	defaultOrderingTopN = 1
+)
+
+// OrderingCriteria selects, among the matched files, the first ones according to sort rules
+type OrderingCriteria struct {
+	Regex   string     `mapstructure:"regex,omitempty"    json:"regex,omitempty"    yaml:"regex,omitempty"`
+	GroupBy string     `mapstructure:"group_by,omitempty" json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	TopN    int        `mapstructure:"top_n,omitempty"    json:"top_n,omitempty"    yaml:"top_n,omitempty"`
+	SortBy  []SortRule `mapstructure:"sort_by,omitempty"  json:"sort_by,omitempty"  yaml:"sort_by,omitempty"`
+}
+
+// SortRule is a criterion to sort the matched files by
+type SortRule struct {
+	SortType  string `mapstructure:"sort_type,omitempty" json:"sort_type,omitempty" yaml:"sort_type,omitempty"`
+	RegexKey  string `mapstructure:"regex_key,omitempty" json:"regex_key,omitempty" yaml:"regex_key,omitempty"`
+	Ascending bool   `mapstructure:"ascending,omitempty" json:"ascending,omitempty" yaml:"ascending,omitempty"`
+	Layout    string `mapstructure:"layout,omitempty"    json:"layout,omitempty"    yaml:"layout,omitempty"`
+	Location  string `mapstructure:"location,omitempty"  json:"location,omitempty"  yaml:"location,omitempty"`
+}
+
+// ordering is the compiled form of the ordering criteria
+type ordering struct {
+	re      *regexp.Regexp
+	groupBy string
+	topN    int
+	rules   []sortRule
+}
+
+// sortRule is a sort rule along with its parsed timestamp layout and location
+type sortRule struct {
+	SortRule
+	layout   string
+	location *time.Location
+}
+
+// build validates the ordering criteria and compiles them.
+// It returns nil if there are no sort rules, in which case the files aren't filtered.
+func (c OrderingCriteria) build() (*ordering, error) {
+	if len(c.SortBy) == 0 {
+		return nil, nil
+	}
+
+	if c.TopN < 0 {
+		return nil, errors.New("`top_n` must not be negative")
+	}
+
+	o := &ordering{groupBy: c.GroupBy, topN: c.TopN, rules: make([]sortRule, 0, len(c.SortBy))}
+	if o.topN == 0 {
+		o.topN = defaultOrderingTopN
+	}
+	if c.Regex != "" {
+		var err error
+		if o.re, err = regexp.Compile(c.Regex); err != nil {
+			return nil, fmt.Errorf("compiling regex: %w", err)
+		}
+	}
+
+	if c.GroupBy != "" && !o.hasCapture(c.GroupBy) {
+		return nil, fmt.Errorf("`group_by` '%s' isn't a named capture group of the regex", c.GroupBy)
+	}
+
+	for _, r := range c.SortBy {
+		rule := sortRule{SortRule: r}
+		switch r.SortType {
+		case sortTypeMtime:
+			o.rules = append(o.rules, rule)
+			continue
+		case sortTypeNumeric, sortTypeAlphabetical:
+		case sortTypeTimestamp:
+			if r.Layout == "" {
+				return nil, errors.New("`layout` is required for the timestamp sort type")
+			}
+			var err error
+			if rule.layout, err = ctimefmt.ToNative(r.Layout); err != nil {
+				return nil, fmt.Errorf("invalid timestamp layout: %w", err)
+			}
+			if rule.location, err = time.LoadLocation(r.Location); err != nil {
+				return nil, fmt.Errorf("invalid timestamp location: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("invalid sort type '%s'", r.SortType)
+		}
+		if !o.hasCapture(r.RegexKey) {
+			return nil, fmt.Errorf("`regex_key` '%s' isn't a named capture group of the regex", r.RegexKey)
+		}
+		o.rules = append(o.rules, rule)
+	}
+	return o, nil
+}
+
+func (o *ordering) hasCapture(key string) bool {
+	return o.re != nil && o.re.SubexpIndex(key) >= 0
+}
+
+// orderedFile is a matched file along with the values it is sorted by
+type orderedFile struct {
+	path   string
+	group  string
+	values []interface{}
+}
+
+// apply sorts the files of each group, and keeps the first N of each group.
+// Files not matching the regex, or from which a value to sort by can't be extracted, are discarded.
+func (o *ordering) apply(paths []string) []string {
+	groups := make(map[string][]orderedFile)
+	groupOrder := make([]string, 0)
+PATHS:
+	for _, path := range paths {
+		var captures []string
+		if o.re != nil {
+			if captures = o.re.FindStringSubmatch(filepath.Base(path)); captures == nil {
+				continue
+			}
+		}
+		capture := func(key string) string {
+			return captures[o.re.SubexpIndex(key)]
+		}
+
+		file := orderedFile{path: path, values: make([]interface{}, 0, len(o.rules))}
+		for _, rule := range o.rules {
+			value, err := rule.value(path, capture)
+			if err != nil {
+				continue PATHS
+			}
+			file.values = append(file.values, value)
+		}
+		if o.groupBy != "" {
+			file.group = capture(o.groupBy)
+		}
+
+		if _, ok := groups[file.group]; !ok {
+			groupOrder = append(groupOrder, file.group)
+		}
+		groups[file.group] = append(groups[file.group], file)
+	}
+
+	selected := make([]string, 0, len(paths))
+	for _, group := range groupOrder {
+		files := groups[group]
+		sort.SliceStable(files, func(i, j int) bool {
+			for k, rule := range o.rules {
+				if cmp := compareValues(files[i].values[k], files[j].values[k]); cmp != 0 {
+					if rule.Ascending {
+						return cmp < 0
+					}
+					return cmp > 0
+				}
+			}
+			return false
+		})
+		if len(files) > o.topN {
+			files = files[:o.topN]
+		}
+		for _, file := range files {
+			selected = append(selected, file.path)
+		}
+	}
+	return selected
+}
+
+// value extracts the value to sort the file by
+func (r sortRule) value(path string, capture func(string) string) (interface{}, error) {
+	switch r.SortType {
+	case sortTypeMtime:
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return info.ModTime(), nil
+	case sortTypeNumeric:
+		return strconv.ParseInt(capture(r.RegexKey), 10, 64)
+	case sortTypeTimestamp:
+		return time.ParseInLocation(r.layout, capture(r.RegexKey), r.location)
+	default:
+		return capture(r.RegexKey), nil
+	}
+}
+
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case time.Time:
+		b := b.(time.Time)
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+	case string:
+		b := b.(string)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
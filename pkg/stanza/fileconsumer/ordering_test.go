@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderingCriteriaBuild(t *testing.T) {
+	cases := []struct {
+		name     string
+		criteria OrderingCriteria
+		err      string
+	}{
+		{
+			name: "NoSortRule",
+			criteria: OrderingCriteria{
+				Regex: "(",
+			},
+		},
+		{
+			name: "Mtime",
+			criteria: OrderingCriteria{
+				SortBy: []SortRule{{SortType: sortTypeMtime}},
+			},
+		},
+		{
+			name: "NegativeTopN",
+			criteria: OrderingCriteria{
+				TopN:   -1,
+				SortBy: []SortRule{{SortType: sortTypeMtime}},
+			},
+			err: "`top_n` must not be negative",
+		},
+		{
+			name: "InvalidRegex",
+			criteria: OrderingCriteria{
+				Regex:  "(",
+				SortBy: []SortRule{{SortType: sortTypeMtime}},
+			},
+			err: "compiling regex: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "UnknownGroupBy",
+			criteria: OrderingCriteria{
+				Regex:   `(?P<num>\d+)`,
+				GroupBy: "app",
+				SortBy:  []SortRule{{SortType: sortTypeNumeric, RegexKey: "num"}},
+			},
+			err: "`group_by` 'app' isn't a named capture group of the regex",
+		},
+		{
+			name: "UnknownRegexKey",
+			criteria: OrderingCriteria{
+				Regex:  `(?P<num>\d+)`,
+				SortBy: []SortRule{{SortType: sortTypeNumeric, RegexKey: "number"}},
+			},
+			err: "`regex_key` 'number' isn't a named capture group of the regex",
+		},
+		{
+			name: "RegexKeyWithoutRegex",
+			criteria: OrderingCriteria{
+				SortBy: []SortRule{{SortType: sortTypeAlphabetical, RegexKey: "name"}},
+			},
+			err: "`regex_key` 'name' isn't a named capture group of the regex",
+		},
+		{
+			name: "InvalidSortType",
+			criteria: OrderingCriteria{
+				SortBy: []SortRule{{SortType: "size"}},
+			},
+			err: "invalid sort type 'size'",
+		},
+		{
+			name: "TimestampWithoutLayout",
+			criteria: OrderingCriteria{
+				Regex:  `(?P<date>.*)`,
+				SortBy: []SortRule{{SortType: sortTypeTimestamp, RegexKey: "date"}},
+			},
+			err: "`layout` is required for the timestamp sort type",
+		},
+		{
+			name: "TimestampInvalidLocation",
+			criteria: OrderingCriteria{
+				Regex:  `(?P<date>.*)`,
+				SortBy: []SortRule{{SortType: sortTypeTimestamp, RegexKey: "date", Layout: "%Y%m%d", Location: "Nowhere/Nothing"}},
+			},
+			err: "invalid timestamp location: unknown time zone Nowhere/Nothing",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.criteria.build()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestOrderingCriteriaBuildCompiles(t *testing.T) {
+	criteria := OrderingCriteria{
+		Regex:  `app-(?P<date>\d{8})\.log`,
+		SortBy: []SortRule{{SortType: sortTypeTimestamp, RegexKey: "date", Layout: "%Y%m%d", Location: "America/New_York"}},
+	}
+	o, err := criteria.build()
+	require.NoError(t, err)
+	require.Equal(t, `app-(?P<date>\d{8})\.log`, o.re.String())
+	require.Equal(t, defaultOrderingTopN, o.topN)
+	require.Len(t, o.rules, 1)
+	require.Equal(t, "20060102", o.rules[0].layout)
+	require.Equal(t, "America/New_York", o.rules[0].location.String())
+
+	o, err = OrderingCriteria{Regex: "("}.build()
+	require.NoError(t, err)
+	require.Nil(t, o)
+}
@@ -16,7 +16,9 @@ package fileconsumer // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"go.uber.org/zap"
@@ -27,6 +29,8 @@ import (
 type readerConfig struct {
 	fingerprintSize int
 	maxLogSize      int
+	compression     compressionSuffixes
+	header          *headerParser
 	emit            EmitFunc
 }
 
@@ -36,15 +40,38 @@ type Reader struct {
 	*readerConfig
 	splitter *helper.Splitter
 
-	Fingerprint    *Fingerprint
-	Offset         int64
+	Fingerprint *Fingerprint
+	Offset      int64
+	// Exhausted is set once a compressed file has been read to the end,
+	// as compressed files aren't expected to be appended to
+	Exhausted bool `json:",omitempty"`
+
 	generation     int
 	file           *os.File
+	// readOffset is the offset of the next byte returned by Read, which is ahead of Offset
+	// when the scanner has read content that isn't a complete entry yet
+	readOffset int64
 	fileAttributes *FileAttributes
+
+	// compressionFormat is empty when the file isn't compressed,
+	// otherwise the content is read from the decompressor and the offset is in the decompressed content
+	compressionFormat string
+	decompressor      io.ReadCloser
+	// decompressErr is the error that ended the decompressed content before its end, e.g. when
+	// the file is still being compressed
+	decompressErr error
+
+	// headerAttributes is nil until the header of the file has been read
+	headerAttributes map[string]interface{}
 }
 
 // offsetToEnd sets the starting offset
 func (r *Reader) offsetToEnd() error {
+	if r.compressionFormat != "" {
+		// A compressed file is complete, skipping to its end means skipping it entirely
+		r.Exhausted = true
+		return nil
+	}
 	info, err := r.file.Stat()
 	if err != nil {
 		return fmt.Errorf("stat: %w", err)
@@ -55,12 +82,37 @@ func (r *Reader) offsetToEnd() error {
 
 // ReadToEnd will read until the end of the file
 func (r *Reader) ReadToEnd(ctx context.Context) {
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if r.header != nil && r.headerAttributes == nil && !r.readHeader(ctx) {
+		// The entries are only read once the header is complete
+		return
+	}
+
+	if r.compressionFormat != "" {
+		if r.Exhausted {
+			return
+		}
+		if err := r.openDecompressor(); err != nil {
+			r.Errorw("Failed to decompress", zap.Error(err))
+			return
+		}
+	} else if _, err := r.file.Seek(r.Offset, 0); err != nil {
 		r.Errorw("Failed to seek", zap.Error(err))
 		return
 	}
 
-	scanner := NewPositionalScanner(r, r.maxLogSize, r.Offset, r.splitter.SplitFunc)
+	splitFunc := r.splitter.SplitFunc
+	if r.compressionFormat != "" {
+		// The last entry is only flushed at the end of a complete stream. When the stream ends
+		// unexpectedly, the remaining content is read again from the same offset on the next poll.
+		splitFunc = func(data []byte, atEOF bool) (int, []byte, error) {
+			if atEOF && r.decompressErr != nil {
+				return 0, nil, nil
+			}
+			return r.splitter.SplitFunc(data, atEOF)
+		}
+	}
+	r.readOffset = r.Offset
+	scanner := NewPositionalScanner(r, r.maxLogSize, r.Offset, splitFunc)
 
 	// Iterate over the tokenized file, emitting entries as we go
 	for {
@@ -72,8 +124,12 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 
 		ok := scanner.Scan()
 		if !ok {
-			if err := scanner.getError(); err != nil {
+			if errors.Is(r.decompressErr, io.ErrUnexpectedEOF) {
+				r.Debugw("Compressed file is incomplete, waiting for the rest of it", zap.Error(r.decompressErr))
+			} else if err := scanner.getError(); err != nil {
 				r.Errorw("Failed during scan", zap.Error(err))
+			} else if r.compressionFormat != "" {
+				r.Exhausted = true
 			}
 			break
 		}
@@ -89,8 +145,33 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 	}
 }
 
+// openDecompressor starts decompressing the file from its beginning, and skips the content up to the offset
+func (r *Reader) openDecompressor() error {
+	r.closeDecompressor()
+	r.decompressErr = nil
+	dec, err := newDecompressor(r.file, r.compressionFormat)
+	if err != nil {
+		return err
+	}
+	r.decompressor = dec
+	if _, err := io.CopyN(io.Discard, dec, r.Offset); err != nil {
+		return fmt.Errorf("skip to offset: %w", err)
+	}
+	return nil
+}
+
+func (r *Reader) closeDecompressor() {
+	if r.decompressor != nil {
+		if err := r.decompressor.Close(); err != nil {
+			r.Debugw("Problem closing decompressor", zap.Error(err))
+		}
+		r.decompressor = nil
+	}
+}
+
 // Close will close the file
 func (r *Reader) Close() {
+	r.closeDecompressor()
 	if r.file != nil {
 		if err := r.file.Close(); err != nil {
 			r.Debugw("Problem closing reader", zap.Error(err))
@@ -100,20 +181,31 @@ func (r *Reader) Close() {
 
 // Read from the file and update the fingerprint if necessary
 func (r *Reader) Read(dst []byte) (int, error) {
+	var src io.Reader = r.file
+	if r.decompressor != nil {
+		src = r.decompressor
+	}
+
+	n, err := src.Read(dst)
+	if r.decompressor != nil && err != nil && !errors.Is(err, io.EOF) {
+		r.decompressErr = err
+	}
+	offset := r.readOffset
+	r.readOffset += int64(n)
+
 	// Skip if fingerprint is already built
-	// or if fingerprint is behind Offset
-	if len(r.Fingerprint.FirstBytes) == r.fingerprintSize || int(r.Offset) > len(r.Fingerprint.FirstBytes) {
-		return r.file.Read(dst)
+	// or if fingerprint is behind the read offset
+	if len(r.Fingerprint.FirstBytes) == r.fingerprintSize || int(offset) > len(r.Fingerprint.FirstBytes) {
+		return n, err
 	}
-	n, err := r.file.Read(dst)
-	appendCount := min0(n, r.fingerprintSize-int(r.Offset))
-	// return for n == 0 or r.Offset >= r.fileInput.fingerprintSize
+	appendCount := min0(n, r.fingerprintSize-int(offset))
+	// return for n == 0 or offset >= r.fileInput.fingerprintSize
 	if appendCount == 0 {
 		return n, err
 	}
 
 	// for appendCount==0, the following code would add `0` to fingerprint
-	r.Fingerprint.FirstBytes = append(r.Fingerprint.FirstBytes[:r.Offset], dst[:appendCount]...)
+	r.Fingerprint.FirstBytes = append(r.Fingerprint.FirstBytes[:offset], dst[:appendCount]...)
 	return n, err
 }
 
@@ -42,6 +42,8 @@ func (f *readerFactory) copy(old *Reader, newFile *os.File) (*Reader, error) {
 		withFile(newFile).
 		withFingerprint(old.Fingerprint.Copy()).
 		withOffset(old.Offset).
+		withExhausted(old.Exhausted).
+		withHeaderAttributes(old.headerAttributes).
 		withSplitter(old.splitter).
 		build()
 }
@@ -51,15 +53,21 @@ func (f *readerFactory) unsafeReader() (*Reader, error) {
 }
 
 func (f *readerFactory) newFingerprint(file *os.File) (*Fingerprint, error) {
+	if format := f.readerConfig.compression.format(file.Name()); format != "" {
+		return newCompressedFingerprint(file, format, f.readerConfig.fingerprintSize)
+	}
 	return NewFingerprint(file, f.readerConfig.fingerprintSize)
 }
 
 type readerBuilder struct {
 	*readerFactory
-	file     *os.File
-	fp       *Fingerprint
-	offset   int64
-	splitter *helper.Splitter
+	file      *os.File
+	fp        *Fingerprint
+	offset    int64
+	exhausted bool
+	splitter  *helper.Splitter
+
+	headerAttributes map[string]interface{}
 }
 
 func (f *readerFactory) newReaderBuilder() *readerBuilder {
@@ -86,16 +94,33 @@ func (b *readerBuilder) withOffset(offset int64) *readerBuilder {
 	return b
 }
 
+func (b *readerBuilder) withExhausted(exhausted bool) *readerBuilder {
+	b.exhausted = exhausted
+	return b
+}
+
+func (b *readerBuilder) withHeaderAttributes(attributes map[string]interface{}) *readerBuilder {
+	b.headerAttributes = attributes
+	return b
+}
+
 func (b *readerBuilder) build() (r *Reader, err error) {
 	r = &Reader{
-		readerConfig: b.readerConfig,
-		Offset:       b.offset,
+		readerConfig:     b.readerConfig,
+		Offset:           b.offset,
+		Exhausted:        b.exhausted,
+		headerAttributes: b.headerAttributes,
+	}
+
+	if b.file != nil {
+		r.compressionFormat = b.readerConfig.compression.format(b.file.Name())
 	}
 
-	if b.splitter != nil {
+	if b.splitter != nil && r.compressionFormat == "" {
 		r.splitter = b.splitter
 	} else {
-		r.splitter, err = b.splitterConfig.Build(false, b.readerConfig.maxLogSize)
+		// Compressed files are complete, so their last entry is flushed at the end of the file
+		r.splitter, err = b.splitterConfig.Build(r.compressionFormat != "", b.readerConfig.maxLogSize)
 		if err != nil {
 			return
 		}
@@ -108,6 +133,7 @@ func (b *readerBuilder) build() (r *Reader, err error) {
 		if err != nil {
 			b.Errorf("resolve attributes: %w", err)
 		}
+		r.fileAttributes.HeaderAttributes = b.headerAttributes
 
 		// unsafeReader has the file set to nil, so don't try emending its offset.
 		if !b.fromBeginning {
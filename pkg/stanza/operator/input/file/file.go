@@ -82,3 +82,12 @@ func setFileNameResolved(attrs *fileconsumer.FileAttributes, ent *entry.Entry) e
 func setFilePathResolved(attrs *fileconsumer.FileAttributes, ent *entry.Entry) error {
 	return ent.Set(entry.NewAttributeField("log.file.path_resolved"), attrs.PathResolved)
 }
+
+func setHeaderAttributes(attrs *fileconsumer.FileAttributes, ent *entry.Entry) error {
+	for k, v := range attrs.HeaderAttributes {
+		if err := ent.Set(entry.NewAttributeField(k), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -26,7 +26,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+	_ "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/regex" // register the regex parser used in the header
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
 )
 
@@ -68,6 +70,31 @@ func TestAddFileFields(t *testing.T) {
 	require.Equal(t, temp.Name(), e.Attributes["log.file.path"])
 }
 
+// AddHeaderAttributes tests that the attributes parsed from the header are added to the entries
+func TestAddHeaderAttributes(t *testing.T) {
+	t.Parallel()
+	op, logReceived, tempDir := newTestFileOperator(t, func(cfg *Config) {
+		cfg.Header = &fileconsumer.HeaderConfig{
+			Pattern: "^#",
+			MetadataOperators: []map[string]interface{}{
+				{"type": "regex_parser", "regex": `^#Fields: (?P<fields>.*)$`},
+			},
+		}
+	}, nil)
+
+	temp := openTemp(t, tempDir)
+	writeString(t, temp, "#Fields: date time\n2022-10-01 00:00:00\n")
+
+	require.NoError(t, op.Start(testutil.NewMockPersister("test")))
+	defer func() {
+		require.NoError(t, op.Stop())
+	}()
+
+	e := waitForOne(t, logReceived)
+	require.Equal(t, "2022-10-01 00:00:00", e.Body)
+	require.Equal(t, "date time", e.Attributes["fields"])
+}
+
 // AddFileResolvedFields tests that the `log.file.name_resolved` and `log.file.path_resolved` fields are included
 // when IncludeFileNameResolved and IncludeFilePathResolved are set to true
 func TestAddFileResolvedFields(t *testing.T) {
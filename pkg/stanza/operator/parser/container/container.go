@@ -0,0 +1,367 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/parser/container"
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator/helper"
+)
+
+const operatorType = "container"
+
+const (
+	dockerFormat     = "docker"
+	crioFormat       = "crio"
+	containerdFormat = "containerd"
+)
+
+const (
+	logPathField  = "log.file.path"
+	iostreamField = "log.iostream"
+
+	podNameKey       = "k8s.pod.name"
+	podUIDKey        = "k8s.pod.uid"
+	namespaceKey     = "k8s.namespace.name"
+	containerNameKey = "k8s.container.name"
+	restartCountKey  = "k8s.container.restart_count"
+)
+
+const defaultMaxLogSize = 1024 * 1024
+
+var (
+	// criRegex matches the log lines written by CRI runtimes such as CRI-O and containerd:
+	// <time> <stream> <tag> <log>
+	criRegex = regexp.MustCompile(`^([^ ]+) (stdout|stderr) ([^ ]*) ?(.*)$`)
+
+	// podLogPathRegex matches the paths of the pod logs written by the kubelet:
+	// /var/log/pods/<namespace>_<pod_name>_<pod_uid>/<container_name>/<restart_count>.log
+	podLogPathRegex = regexp.MustCompile(`^.*[\\/]([^_\\/]+)_([^_\\/]+)_([a-f0-9\-]+)[\\/]([^\._\\/]+)[\\/](\d+)\.log$`)
+)
+
+func init() {
+	operator.Register(operatorType, func() operator.Builder { return NewConfig() })
+}
+
+// NewConfig creates a new container parser config with default values
+func NewConfig() *Config {
+	return NewConfigWithID(operatorType)
+}
+
+// NewConfigWithID creates a new container parser config with default values
+func NewConfigWithID(operatorID string) *Config {
+	return &Config{
+		TransformerConfig:       helper.NewTransformerConfig(operatorID, operatorType),
+		AddMetadataFromFilePath: true,
+		MaxLogSize:              defaultMaxLogSize,
+		ForceFlushTimeout:       5 * time.Second,
+	}
+}
+
+// Config is the configuration of a container parser operator.
+type Config struct {
+	helper.TransformerConfig `mapstructure:",squash" yaml:",inline"`
+
+	Format                  string          `mapstructure:"format"                     json:"format"                     yaml:"format"`
+	AddMetadataFromFilePath bool            `mapstructure:"add_metadata_from_filepath" json:"add_metadata_from_filepath" yaml:"add_metadata_from_filepath"`
+	MaxLogSize              helper.ByteSize `mapstructure:"max_log_size"               json:"max_log_size"               yaml:"max_log_size"`
+	ForceFlushTimeout       time.Duration   `mapstructure:"force_flush_period"         json:"force_flush_period"         yaml:"force_flush_period"`
+}
+
+// Build will build a container parser operator.
+func (c Config) Build(logger *zap.SugaredLogger) (operator.Operator, error) {
+	transformer, err := c.TransformerConfig.Build(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c.Format {
+	case "", dockerFormat, crioFormat, containerdFormat:
+	default:
+		return nil, fmt.Errorf("invalid value '%s' for parameter 'format'", c.Format)
+	}
+
+	if c.ForceFlushTimeout <= 0 {
+		return nil, fmt.Errorf("'force_flush_period' must be positive")
+	}
+
+	return &Parser{
+		TransformerOperator:     transformer,
+		format:                  c.Format,
+		addMetadataFromFilePath: c.AddMetadataFromFilePath,
+		maxLogSize:              int(c.MaxLogSize),
+		forceFlushTimeout:       c.ForceFlushTimeout,
+		ticker:                  time.NewTicker(c.ForceFlushTimeout),
+		chClose:                 make(chan struct{}),
+		json:                    jsoniter.ConfigFastest,
+		batches:                 make(map[string]*batch),
+	}, nil
+}
+
+// Parser is an operator that parses the logs written by container runtimes
+// and reassembles the lines they split.
+type Parser struct {
+	helper.TransformerOperator
+	format                  string
+	addMetadataFromFilePath bool
+	maxLogSize              int
+	forceFlushTimeout       time.Duration
+	ticker                  *time.Ticker
+	chClose                 chan struct{}
+	json                    jsoniter.API
+
+	sync.Mutex
+	batches map[string]*batch
+}
+
+// containerLog is a single line written by a container runtime
+type containerLog struct {
+	time    time.Time
+	stream  string
+	log     string
+	partial bool
+}
+
+// batch holds the partial lines of a log until its last line is read
+type batch struct {
+	base       *entry.Entry
+	log        strings.Builder
+	lastUpdate time.Time
+}
+
+// dockerLog is a line written by the docker json-file logging driver
+type dockerLog struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+func (p *Parser) Start(_ operator.Persister) error {
+	go p.flushLoop()
+
+	return nil
+}
+
+func (p *Parser) flushLoop() {
+	for {
+		select {
+		case <-p.ticker.C:
+			p.Lock()
+			timeNow := time.Now()
+			for source, b := range p.batches {
+				if timeNow.Sub(b.lastUpdate) < p.forceFlushTimeout {
+					continue
+				}
+				p.flush(context.Background(), source)
+			}
+			p.Unlock()
+		case <-p.chClose:
+			p.ticker.Stop()
+			return
+		}
+	}
+}
+
+func (p *Parser) Stop() error {
+	p.Lock()
+	defer p.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for source := range p.batches {
+		p.flush(ctx, source)
+	}
+
+	close(p.chClose)
+
+	return nil
+}
+
+// Process will parse an entry written by a container runtime.
+func (p *Parser) Process(ctx context.Context, e *entry.Entry) error {
+	skip, err := p.Skip(ctx, e)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+	if skip {
+		p.Write(ctx, e)
+		return nil
+	}
+
+	l, err := p.parse(e.Body)
+	if err != nil {
+		return p.HandleEntryError(ctx, e, err)
+	}
+
+	var metadata map[string]string
+	if p.addMetadataFromFilePath {
+		if metadata, err = parseFilePath(e); err != nil {
+			return p.HandleEntryError(ctx, e, err)
+		}
+	}
+
+	e.Timestamp = l.time
+	e.AddAttribute(iostreamField, l.stream)
+	for k, v := range metadata {
+		e.AddResourceKey(k, v)
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	p.combine(ctx, e, l)
+	return nil
+}
+
+// parse will parse a value as a container log, detecting its format unless configured.
+func (p *Parser) parse(value interface{}) (containerLog, error) {
+	raw, ok := value.(string)
+	if !ok {
+		return containerLog{}, fmt.Errorf("type %T cannot be parsed as a container log", value)
+	}
+
+	format := p.format
+	if format == "" {
+		format = detectFormat(raw)
+	}
+	if format == dockerFormat {
+		return p.parseDocker(raw)
+	}
+	return parseCRI(raw)
+}
+
+// detectFormat guesses the format of a line. CRI-O and containerd share the same format.
+func detectFormat(raw string) string {
+	if strings.HasPrefix(raw, "{") {
+		return dockerFormat
+	}
+	return crioFormat
+}
+
+// parseDocker parses a line of the docker json-file logging driver. Docker splits long
+// logs into several lines, and only the last one ends with a newline.
+func (p *Parser) parseDocker(raw string) (containerLog, error) {
+	var d dockerLog
+	if err := p.json.UnmarshalFromString(raw, &d); err != nil {
+		return containerLog{}, fmt.Errorf("parsing docker log: %w", err)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, d.Time)
+	if err != nil {
+		return containerLog{}, fmt.Errorf("parsing docker log time: %w", err)
+	}
+
+	return containerLog{
+		time:    t,
+		stream:  d.Stream,
+		log:     strings.TrimSuffix(d.Log, "\n"),
+		partial: !strings.HasSuffix(d.Log, "\n"),
+	}, nil
+}
+
+// parseCRI parses a line written by a CRI runtime. Long logs are split into several
+// lines, all tagged partial (P) but the last one.
+func parseCRI(raw string) (containerLog, error) {
+	matches := criRegex.FindStringSubmatch(raw)
+	if matches == nil {
+		return containerLog{}, fmt.Errorf("log does not match the CRI format")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, matches[1])
+	if err != nil {
+		return containerLog{}, fmt.Errorf("parsing CRI log time: %w", err)
+	}
+
+	tag := strings.SplitN(matches[3], ":", 2)[0]
+	return containerLog{
+		time:    t,
+		stream:  matches[2],
+		log:     matches[4],
+		partial: tag == "P",
+	}, nil
+}
+
+// parseFilePath extracts the Kubernetes metadata from the path of a pod log.
+func parseFilePath(e *entry.Entry) (map[string]string, error) {
+	var path string
+	if err := e.Read(entry.NewAttributeField(logPathField), &path); err != nil {
+		return nil, fmt.Errorf("reading the '%s' attribute, which requires 'include_file_path': %w", logPathField, err)
+	}
+
+	matches := podLogPathRegex.FindStringSubmatch(path)
+	if matches == nil {
+		return nil, fmt.Errorf("path '%s' does not match the layout of the pod logs", path)
+	}
+
+	return map[string]string{
+		namespaceKey:     matches[1],
+		podNameKey:       matches[2],
+		podUIDKey:        matches[3],
+		containerNameKey: matches[4],
+		restartCountKey:  matches[5],
+	}, nil
+}
+
+// combine emits complete logs and batches the partial ones until their last line is read.
+// Lines are batched per file and stream.
+func (p *Parser) combine(ctx context.Context, e *entry.Entry, l containerLog) {
+	var path string
+	_ = e.Read(entry.NewAttributeField(logPathField), &path)
+	source := path + ":" + l.stream
+
+	b, ok := p.batches[source]
+	if ok && p.maxLogSize > 0 && b.log.Len()+len(l.log) > p.maxLogSize {
+		p.Warnw("Log exceeds max_log_size, emitting it in several entries", "source", source)
+		p.flush(ctx, source)
+		ok = false
+	}
+
+	if !ok {
+		if !l.partial {
+			e.Body = l.log
+			p.Write(ctx, e)
+			return
+		}
+		b = &batch{base: e}
+		p.batches[source] = b
+	}
+
+	b.log.WriteString(l.log)
+	b.lastUpdate = time.Now()
+	if !l.partial {
+		p.flush(ctx, source)
+	}
+}
+
+// flush emits the batched lines of a source as a single entry
+func (p *Parser) flush(ctx context.Context, source string) {
+	b, ok := p.batches[source]
+	if !ok {
+		return
+	}
+	delete(p.batches, source)
+
+	b.base.Body = b.log.String()
+	p.Write(ctx, b.base)
+}
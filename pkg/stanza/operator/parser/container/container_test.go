@@ -0,0 +1,387 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package container
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/entry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+const testPath = "/var/log/pods/default_my-pod_27f6f98a-9f18-4b7a-bd45-3f1b6c4d2e0f/app/1.log"
+
+func newTestParser(t *testing.T, configure func(*Config)) (operator.Operator, *testutil.FakeOutput) {
+	cfg := NewConfigWithID("test")
+	cfg.OutputIDs = []string{"fake"}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	require.NoError(t, op.Start(testutil.NewMockPersister("test")))
+	t.Cleanup(func() {
+		require.NoError(t, op.Stop())
+	})
+	return op, fake
+}
+
+func newTestEntry(body string) *entry.Entry {
+	e := entry.New()
+	e.Body = body
+	e.AddAttribute(logPathField, testPath)
+	return e
+}
+
+func TestConfigBuild(t *testing.T) {
+	op, err := NewConfigWithID("test").Build(testutil.Logger(t))
+	require.NoError(t, err)
+	require.IsType(t, &Parser{}, op)
+}
+
+func TestConfigBuildFailure(t *testing.T) {
+	cases := []struct {
+		name      string
+		configure func(*Config)
+		expectErr string
+	}{
+		{
+			"invalid_on_error",
+			func(cfg *Config) { cfg.OnError = "invalid_on_error" },
+			"invalid `on_error` field",
+		},
+		{
+			"invalid_format",
+			func(cfg *Config) { cfg.Format = "invalid" },
+			"invalid value 'invalid' for parameter 'format'",
+		},
+		{
+			"invalid_force_flush_period",
+			func(cfg *Config) { cfg.ForceFlushTimeout = 0 },
+			"'force_flush_period' must be positive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfigWithID("test")
+			tc.configure(cfg)
+			_, err := cfg.Build(testutil.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectErr)
+		})
+	}
+}
+
+func TestParser(t *testing.T) {
+	resource := map[string]interface{}{
+		"k8s.namespace.name":          "default",
+		"k8s.pod.name":                "my-pod",
+		"k8s.pod.uid":                 "27f6f98a-9f18-4b7a-bd45-3f1b6c4d2e0f",
+		"k8s.container.name":          "app",
+		"k8s.container.restart_count": "1",
+	}
+
+	cases := []struct {
+		name      string
+		format    string
+		input     string
+		stream    string
+		body      string
+		timestamp time.Time
+	}{
+		{
+			"docker",
+			"docker",
+			`{"log":"INFO: log line here\n","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+			"stdout",
+			"INFO: log line here",
+			time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+		},
+		{
+			"crio",
+			"crio",
+			"2024-04-13T07:59:37.505201169-10:00 stderr F INFO: log line here",
+			"stderr",
+			"INFO: log line here",
+			time.Date(2024, time.April, 13, 17, 59, 37, 505201169, time.UTC),
+		},
+		{
+			"containerd",
+			"containerd",
+			"2023-06-22T10:27:25.813799277Z stdout F INFO: log line here",
+			"stdout",
+			"INFO: log line here",
+			time.Date(2023, time.June, 22, 10, 27, 25, 813799277, time.UTC),
+		},
+		{
+			"detect_docker",
+			"",
+			`{"log":"INFO: log line here\n","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+			"stdout",
+			"INFO: log line here",
+			time.Date(2029, time.March, 30, 8, 31, 20, 545192187, time.UTC),
+		},
+		{
+			"detect_crio",
+			"",
+			"2024-04-13T07:59:37.505201169-10:00 stderr F INFO: log line here",
+			"stderr",
+			"INFO: log line here",
+			time.Date(2024, time.April, 13, 17, 59, 37, 505201169, time.UTC),
+		},
+		{
+			"detect_containerd",
+			"",
+			"2023-06-22T10:27:25.813799277Z stdout F INFO: log line here",
+			"stdout",
+			"INFO: log line here",
+			time.Date(2023, time.June, 22, 10, 27, 25, 813799277, time.UTC),
+		},
+		{
+			"empty_log",
+			"",
+			"2023-06-22T10:27:25.813799277Z stdout F",
+			"stdout",
+			"",
+			time.Date(2023, time.June, 22, 10, 27, 25, 813799277, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, func(cfg *Config) { cfg.Format = tc.format })
+
+			input := newTestEntry(tc.input)
+			require.NoError(t, op.Process(context.Background(), input))
+
+			e := <-fake.Received
+			require.Equal(t, tc.body, e.Body)
+			require.True(t, tc.timestamp.Equal(e.Timestamp), "unexpected timestamp %s", e.Timestamp)
+			require.Equal(t, map[string]interface{}{
+				"log.file.path": testPath,
+				"log.iostream":  tc.stream,
+			}, e.Attributes)
+			require.Equal(t, resource, e.Resource)
+		})
+	}
+}
+
+func TestParserWithoutMetadata(t *testing.T) {
+	op, fake := newTestParser(t, func(cfg *Config) { cfg.AddMetadataFromFilePath = false })
+
+	input := entry.New()
+	input.Body = "2023-06-22T10:27:25.813799277Z stdout F INFO: log line here"
+	require.NoError(t, op.Process(context.Background(), input))
+
+	e := <-fake.Received
+	require.Equal(t, "INFO: log line here", e.Body)
+	require.Empty(t, e.Resource)
+}
+
+func TestParserFailure(t *testing.T) {
+	cases := []struct {
+		name      string
+		format    string
+		input     *entry.Entry
+		expectErr string
+	}{
+		{
+			"invalid_type",
+			"",
+			func() *entry.Entry {
+				e := newTestEntry("")
+				e.Body = []byte("2023-06-22T10:27:25.813799277Z stdout F INFO")
+				return e
+			}(),
+			"type []uint8 cannot be parsed as a container log",
+		},
+		{
+			"invalid_docker",
+			"docker",
+			newTestEntry("2023-06-22T10:27:25.813799277Z stdout F INFO"),
+			"parsing docker log",
+		},
+		{
+			"invalid_docker_time",
+			"",
+			newTestEntry(`{"log":"INFO\n","stream":"stdout","time":"yesterday"}`),
+			"parsing docker log time",
+		},
+		{
+			"invalid_cri",
+			"crio",
+			newTestEntry("INFO: log line here"),
+			"log does not match the CRI format",
+		},
+		{
+			"invalid_cri_time",
+			"containerd",
+			newTestEntry("yesterday stdout F INFO: log line here"),
+			"parsing CRI log time",
+		},
+		{
+			"missing_file_path",
+			"",
+			func() *entry.Entry {
+				e := entry.New()
+				e.Body = "2023-06-22T10:27:25.813799277Z stdout F INFO"
+				return e
+			}(),
+			"reading the 'log.file.path' attribute",
+		},
+		{
+			"invalid_file_path",
+			"",
+			func() *entry.Entry {
+				e := entry.New()
+				e.Body = "2023-06-22T10:27:25.813799277Z stdout F INFO"
+				e.AddAttribute(logPathField, "/var/log/containers/app.log")
+				return e
+			}(),
+			"does not match the layout of the pod logs",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, func(cfg *Config) { cfg.Format = tc.format })
+
+			err := op.Process(context.Background(), tc.input)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectErr)
+
+			// The default on_error sends the entry unmodified
+			e := <-fake.Received
+			require.Equal(t, tc.input, e)
+			require.Empty(t, e.Resource)
+		})
+	}
+}
+
+func TestParserRecombine(t *testing.T) {
+	cases := []struct {
+		name   string
+		inputs []string
+		bodies []string
+	}{
+		{
+			"cri",
+			[]string{
+				"2023-06-22T10:27:25.813799277Z stdout P part one, ",
+				"2023-06-22T10:27:25.813799278Z stdout P part two, ",
+				"2023-06-22T10:27:25.813799279Z stdout F part three",
+				"2023-06-22T10:27:25.813799280Z stdout F complete",
+			},
+			[]string{"part one, part two, part three", "complete"},
+		},
+		{
+			"docker",
+			[]string{
+				`{"log":"part one, ","stream":"stdout","time":"2029-03-30T08:31:20.545192187Z"}`,
+				`{"log":"part two\n","stream":"stdout","time":"2029-03-30T08:31:20.545192188Z"}`,
+				`{"log":"complete\n","stream":"stdout","time":"2029-03-30T08:31:20.545192189Z"}`,
+			},
+			[]string{"part one, part two", "complete"},
+		},
+		{
+			"streams",
+			[]string{
+				"2023-06-22T10:27:25.813799277Z stdout P out one, ",
+				"2023-06-22T10:27:25.813799278Z stderr P err one, ",
+				"2023-06-22T10:27:25.813799279Z stdout F out two",
+				"2023-06-22T10:27:25.813799280Z stderr F err two",
+			},
+			[]string{"out one, out two", "err one, err two"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			op, fake := newTestParser(t, nil)
+
+			for _, input := range tc.inputs {
+				require.NoError(t, op.Process(context.Background(), newTestEntry(input)))
+			}
+			for _, body := range tc.bodies {
+				e := <-fake.Received
+				require.Equal(t, body, e.Body)
+			}
+			fake.ExpectNoEntry(t, 100*time.Millisecond)
+		})
+	}
+}
+
+func TestParserRecombineKeepsFirstTimestamp(t *testing.T) {
+	op, fake := newTestParser(t, nil)
+
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:25Z stdout P one ")))
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:26Z stdout F two")))
+
+	e := <-fake.Received
+	require.Equal(t, "one two", e.Body)
+	require.Equal(t, time.Date(2023, time.June, 22, 10, 27, 25, 0, time.UTC), e.Timestamp)
+}
+
+func TestParserMaxLogSize(t *testing.T) {
+	op, fake := newTestParser(t, func(cfg *Config) { cfg.MaxLogSize = 8 })
+
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:25Z stdout P 12345")))
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:26Z stdout P 67890")))
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:27Z stdout F abc")))
+
+	e := <-fake.Received
+	require.Equal(t, "12345", e.Body)
+	e = <-fake.Received
+	require.Equal(t, "67890abc", e.Body)
+}
+
+func TestParserForceFlush(t *testing.T) {
+	op, fake := newTestParser(t, func(cfg *Config) { cfg.ForceFlushTimeout = 50 * time.Millisecond })
+
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:25Z stdout P never finished")))
+
+	select {
+	case e := <-fake.Received:
+		require.Equal(t, "never finished", e.Body)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for the partial log to be flushed")
+	}
+}
+
+func TestParserStopFlushes(t *testing.T) {
+	cfg := NewConfigWithID("test")
+	cfg.OutputIDs = []string{"fake"}
+	op, err := cfg.Build(testutil.Logger(t))
+	require.NoError(t, err)
+	fake := testutil.NewFakeOutput(t)
+	require.NoError(t, op.SetOutputs([]operator.Operator{fake}))
+	require.NoError(t, op.Start(testutil.NewMockPersister("test")))
+
+	require.NoError(t, op.Process(context.Background(), newTestEntry("2023-06-22T10:27:25Z stdout P never finished")))
+	fake.ExpectNoEntry(t, 100*time.Millisecond)
+
+	require.NoError(t, op.Stop())
+	e := <-fake.Received
+	require.Equal(t, "never finished", e.Body)
+}
@@ -51,41 +51,33 @@ func SetValue(value pcommon.Value, val interface{}) {
 	case []byte:
 		value.SetBytesVal(pcommon.NewImmutableByteSlice(v))
 	case []string:
-		value.SliceVal().RemoveIf(func(_ pcommon.Value) bool {
-			return true
-		})
+		slice := value.SetEmptySliceVal()
 		for _, str := range v {
-			value.SliceVal().AppendEmpty().SetStringVal(str)
+			slice.AppendEmpty().SetStringVal(str)
 		}
 	case []bool:
-		value.SliceVal().RemoveIf(func(_ pcommon.Value) bool {
-			return true
-		})
+		slice := value.SetEmptySliceVal()
 		for _, b := range v {
-			value.SliceVal().AppendEmpty().SetBoolVal(b)
+			slice.AppendEmpty().SetBoolVal(b)
 		}
 	case []int64:
-		value.SliceVal().RemoveIf(func(_ pcommon.Value) bool {
-			return true
-		})
+		slice := value.SetEmptySliceVal()
 		for _, i := range v {
-			value.SliceVal().AppendEmpty().SetIntVal(i)
+			slice.AppendEmpty().SetIntVal(i)
 		}
 	case []float64:
-		value.SliceVal().RemoveIf(func(_ pcommon.Value) bool {
-			return true
-		})
+		slice := value.SetEmptySliceVal()
 		for _, f := range v {
-			value.SliceVal().AppendEmpty().SetDoubleVal(f)
+			slice.AppendEmpty().SetDoubleVal(f)
 		}
 	case [][]byte:
-		value.SliceVal().RemoveIf(func(_ pcommon.Value) bool {
-			return true
-		})
+		slice := value.SetEmptySliceVal()
 		for _, b := range v {
-			value.SliceVal().AppendEmpty().SetBytesVal(pcommon.NewImmutableByteSlice(b))
+			slice.AppendEmpty().SetBytesVal(pcommon.NewImmutableByteSlice(b))
 		}
-	default:
-		// TODO(anuraaga): Support set of map type.
+	case pcommon.Map:
+		v.CopyTo(value.SetEmptyMapVal())
+	case pcommon.Slice:
+		v.CopyTo(value.SetEmptySliceVal())
 	}
 }
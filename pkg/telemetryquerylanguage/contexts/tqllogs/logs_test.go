@@ -112,6 +112,19 @@ func Test_newPathGetSetter(t *testing.T) {
 				log.Body().SetStringVal("head")
 			},
 		},
+		{
+			name: "body map",
+			path: []tql.Field{
+				{
+					Name: "body",
+				},
+			},
+			orig:   "body",
+			newVal: newAttrs,
+			modified: func(log plog.LogRecord, il pcommon.InstrumentationScope, resource pcommon.Resource) {
+				newAttrs.CopyTo(log.Body().SetEmptyMapVal())
+			},
+		},
 		{
 			name: "flags",
 			path: []tql.Field{
@@ -352,6 +365,20 @@ func Test_newPathGetSetter(t *testing.T) {
 				log.Attributes().UpsertEmptySlice("arr_bytes").AppendEmpty().SetBytesVal(pcommon.NewImmutableByteSlice([]byte{9, 6, 4}))
 			},
 		},
+		{
+			name: "attributes map",
+			path: []tql.Field{
+				{
+					Name:   "attributes",
+					MapKey: tqltest.Strp("str"),
+				},
+			},
+			orig:   "val",
+			newVal: newAttrs,
+			modified: func(log plog.LogRecord, il pcommon.InstrumentationScope, resource pcommon.Resource) {
+				newAttrs.CopyTo(log.Attributes().UpsertEmptyMap("str"))
+			},
+		},
 		{
 			name: "dropped_attributes_count",
 			path: []tql.Field{
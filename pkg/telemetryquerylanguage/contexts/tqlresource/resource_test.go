@@ -0,0 +1,115 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tqlresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql/tqltest"
+)
+
+func Test_newPathGetSetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     []tql.Field
+		orig     interface{}
+		newVal   interface{}
+		modified func(resource pcommon.Resource)
+	}{
+		{
+			name: "resource attributes string",
+			path: []tql.Field{
+				{
+					Name: "resource",
+				},
+				{
+					Name:   "attributes",
+					MapKey: tqltest.Strp("env"),
+				},
+			},
+			orig:   "prod",
+			newVal: "dev",
+			modified: func(resource pcommon.Resource) {
+				resource.Attributes().UpsertString("env", "dev")
+			},
+		},
+		{
+			name: "resource dropped_attributes_count",
+			path: []tql.Field{
+				{
+					Name: "resource",
+				},
+				{
+					Name: "dropped_attributes_count",
+				},
+			},
+			orig:   int64(10),
+			newVal: int64(20),
+			modified: func(resource pcommon.Resource) {
+				resource.SetDroppedAttributesCount(20)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := newPathGetSetter(tt.path)
+			require.NoError(t, err)
+
+			resource := createResource()
+			got := accessor.Get(NewTransformContext(resource))
+			assert.Equal(t, tt.orig, got)
+
+			accessor.Set(NewTransformContext(resource), tt.newVal)
+
+			expected := createResource()
+			tt.modified(expected)
+			assert.Equal(t, expected, resource)
+		})
+	}
+}
+
+func Test_newPathGetSetter_invalid(t *testing.T) {
+	_, err := ParsePath(&tql.Path{Fields: []tql.Field{{Name: "attributes"}}})
+	assert.Error(t, err)
+
+	_, err = ParsePath(nil)
+	assert.Error(t, err)
+}
+
+func Test_ParseConditions(t *testing.T) {
+	conditions, err := tql.ParseConditions(
+		[]string{`resource.attributes["env"] == "prod"`, `resource.attributes["env"] == "dev"`},
+		map[string]interface{}{},
+		ParsePath,
+		ParseEnum,
+	)
+	require.NoError(t, err)
+
+	ctx := NewTransformContext(createResource())
+	assert.True(t, conditions[0](ctx))
+	assert.False(t, conditions[1](ctx))
+}
+
+func createResource() pcommon.Resource {
+	resource := pcommon.NewResource()
+	resource.Attributes().UpsertString("env", "prod")
+	resource.SetDroppedAttributesCount(10)
+	return resource
+}
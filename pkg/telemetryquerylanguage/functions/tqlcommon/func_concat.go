@@ -21,7 +21,12 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
 )
 
+// Deprecated: [v0.60.0] Use Concat instead.
 func Join(delimiter string, vals []tql.Getter) (tql.ExprFunc, error) {
+	return Concat(delimiter, vals)
+}
+
+func Concat(delimiter string, vals []tql.Getter) (tql.ExprFunc, error) {
 	return func(ctx tql.TransformContext) interface{} {
 		builder := strings.Builder{}
 		for i, rv := range vals {
@@ -0,0 +1,63 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tqlotel // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlotel"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+)
+
+const (
+	mergeStrategyInsert = "insert"
+	mergeStrategyUpdate = "update"
+	mergeStrategyUpsert = "upsert"
+)
+
+// MergeMaps merges the source map into the target map using the given strategy:
+// "insert" only adds the keys missing from target, "update" only replaces the
+// keys already in target and "upsert" does both.
+func MergeMaps(target tql.Getter, source tql.Getter, strategy string) (tql.ExprFunc, error) {
+	var merge func(targetMap pcommon.Map, k string, v pcommon.Value)
+	switch strategy {
+	case mergeStrategyInsert:
+		merge = pcommon.Map.Insert
+	case mergeStrategyUpdate:
+		merge = pcommon.Map.Update
+	case mergeStrategyUpsert:
+		merge = pcommon.Map.Upsert
+	default:
+		return nil, fmt.Errorf("invalid value for strategy, %v, must be '%v', '%v' or '%v'",
+			strategy, mergeStrategyInsert, mergeStrategyUpdate, mergeStrategyUpsert)
+	}
+
+	return func(ctx tql.TransformContext) interface{} {
+		targetMap, ok := target.Get(ctx).(pcommon.Map)
+		if !ok {
+			return nil
+		}
+		sourceMap, ok := source.Get(ctx).(pcommon.Map)
+		if !ok {
+			return nil
+		}
+		sourceMap.Range(func(k string, v pcommon.Value) bool {
+			merge(targetMap, k, v)
+			return true
+		})
+		return nil
+	}, nil
+}
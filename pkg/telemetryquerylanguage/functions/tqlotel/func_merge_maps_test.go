@@ -0,0 +1,170 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tqlotel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql/tqltest"
+)
+
+func Test_mergeMaps(t *testing.T) {
+	input := pcommon.NewMap()
+	input.InsertString("attr1", "value1")
+
+	targetGetter := &tql.StandardGetSetter{
+		Getter: func(ctx tql.TransformContext) interface{} {
+			return ctx.GetItem()
+		},
+	}
+
+	tests := []struct {
+		name     string
+		source   tql.Getter
+		strategy string
+		want     func(pcommon.Map)
+	}{
+		{
+			name: "Upsert no conflicting keys",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr2", "value2")
+					return m
+				},
+			},
+			strategy: mergeStrategyUpsert,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value1")
+				expectedValue.InsertString("attr2", "value2")
+			},
+		},
+		{
+			name: "Upsert conflicting key",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr1", "value3")
+					m.InsertString("attr2", "value2")
+					return m
+				},
+			},
+			strategy: mergeStrategyUpsert,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value3")
+				expectedValue.InsertString("attr2", "value2")
+			},
+		},
+		{
+			name: "Insert no conflicting keys",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr2", "value2")
+					return m
+				},
+			},
+			strategy: mergeStrategyInsert,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value1")
+				expectedValue.InsertString("attr2", "value2")
+			},
+		},
+		{
+			name: "Insert conflicting key",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr1", "value3")
+					m.InsertString("attr2", "value2")
+					return m
+				},
+			},
+			strategy: mergeStrategyInsert,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value1")
+				expectedValue.InsertString("attr2", "value2")
+			},
+		},
+		{
+			name: "Update no conflicting keys",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr2", "value2")
+					return m
+				},
+			},
+			strategy: mergeStrategyUpdate,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value1")
+			},
+		},
+		{
+			name: "Update conflicting key",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					m := pcommon.NewMap()
+					m.InsertString("attr1", "value3")
+					return m
+				},
+			},
+			strategy: mergeStrategyUpdate,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value3")
+			},
+		},
+		{
+			name: "Source is not a map",
+			source: tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return "not a map"
+				},
+			},
+			strategy: mergeStrategyUpsert,
+			want: func(expectedValue pcommon.Map) {
+				expectedValue.InsertString("attr1", "value1")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarioMap := pcommon.NewMap()
+			input.CopyTo(scenarioMap)
+
+			exprFunc, err := MergeMaps(targetGetter, tt.source, tt.strategy)
+			assert.NoError(t, err)
+
+			result := exprFunc(tqltest.TestTransformContext{Item: scenarioMap})
+			assert.Nil(t, result)
+
+			expected := pcommon.NewMap()
+			tt.want(expected)
+
+			assert.Equal(t, expected.Sort(), scenarioMap.Sort())
+		})
+	}
+}
+
+func Test_mergeMaps_bad_strategy(t *testing.T) {
+	input := &tql.StandardGetSetter{}
+
+	_, err := MergeMaps(input, input, "bad value")
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tqlotel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql/tqltest"
+)
+
+func Test_parseJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		target tql.Getter
+		want   func(pcommon.Map)
+	}{
+		{
+			name: "handle string",
+			target: &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return `{"test":"string value"}`
+				},
+			},
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.InsertString("test", "string value")
+			},
+		},
+		{
+			name: "handle number",
+			target: &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return `{"test":1.1}`
+				},
+			},
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.InsertDouble("test", 1.1)
+			},
+		},
+		{
+			name: "handle bool",
+			target: &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return `{"test":true}`
+				},
+			},
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.InsertBool("test", true)
+			},
+		},
+		{
+			name: "handle nested object",
+			target: &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return `{"test":{"nested":"true"}}`
+				},
+			},
+			want: func(expectedMap pcommon.Map) {
+				expectedMap.UpsertEmptyMap("test").InsertString("nested", "true")
+			},
+		},
+		{
+			name: "handle array",
+			target: &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return `{"test":["string","value"]}`
+				},
+			},
+			want: func(expectedMap pcommon.Map) {
+				slice := expectedMap.UpsertEmptySlice("test")
+				slice.AppendEmpty().SetStringVal("string")
+				slice.AppendEmpty().SetStringVal("value")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := ParseJSON(tt.target)
+			assert.NoError(t, err)
+
+			result := exprFunc(tqltest.TestTransformContext{})
+
+			expected := pcommon.NewMap()
+			tt.want(expected)
+
+			assert.Equal(t, expected.Sort(), result.(pcommon.Map).Sort())
+		})
+	}
+}
+
+func Test_parseJSON_invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+	}{
+		{
+			name:  "not a string",
+			value: int64(1),
+		},
+		{
+			name:  "nil",
+			value: nil,
+		},
+		{
+			name:  "invalid JSON",
+			value: `{"test":`,
+		},
+		{
+			name:  "not an object",
+			value: `["test"]`,
+		},
+		{
+			name:  "null",
+			value: `null`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := &tql.StandardGetSetter{
+				Getter: func(ctx tql.TransformContext) interface{} {
+					return tt.value
+				},
+			}
+			exprFunc, err := ParseJSON(target)
+			assert.NoError(t, err)
+			assert.Nil(t, exprFunc(tqltest.TestTransformContext{}))
+		})
+	}
+}
@@ -105,6 +105,10 @@ func NewGetter(val Value, functions map[string]interface{}, pathParser PathExpre
 		return pathParser(val.Path)
 	}
 
+	if val.MathExpression != nil {
+		return newMathGetter(val.MathExpression, functions, pathParser, enumParser)
+	}
+
 	if val.Invocation == nil {
 		// In practice, can't happen since the DSL grammar guarantees one is set
 		return nil, fmt.Errorf("no value field set. This is a bug in the Telemetry Query Language")
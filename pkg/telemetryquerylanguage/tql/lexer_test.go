@@ -97,6 +97,27 @@ func Test_lexer(t *testing.T) {
 			{"Bytes", "0x0102030405060708"},
 			{"RParen", ")"},
 		}},
+		{"math_operators", `(end - start) / 1000 * 2 + -1`, false, []result{
+			{"LParen", "("},
+			{"Lowercase", "end"},
+			{"OpAddSub", "-"},
+			{"Lowercase", "start"},
+			{"RParen", ")"},
+			{"OpMultDiv", "/"},
+			{"Int", "1000"},
+			{"OpMultDiv", "*"},
+			{"Int", "2"},
+			{"OpAddSub", "+"},
+			{"OpAddSub", "-"},
+			{"Int", "1"},
+		}},
+		{"subtraction_without_spaces", `x/1000000-1`, false, []result{
+			{"Lowercase", "x"},
+			{"OpMultDiv", "/"},
+			{"Int", "1000000"},
+			{"OpAddSub", "-"},
+			{"Int", "1"},
+		}},
 		{"Mixing case", `aBCd`, false, []result{
 			{"Lowercase", "a"},
 			{"Uppercase", "BC"},
@@ -0,0 +1,128 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tql // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+
+import (
+	"fmt"
+)
+
+func newMathGetter(expr *MathExpression, functions map[string]interface{}, pathParser PathExpressionParser, enumParser EnumParser) (Getter, error) {
+	getter, err := newAddSubTermGetter(expr.Left, functions, pathParser, enumParser)
+	if err != nil {
+		return nil, err
+	}
+	for _, rhs := range expr.Right {
+		rhsGetter, err := newAddSubTermGetter(rhs.Term, functions, pathParser, enumParser)
+		if err != nil {
+			return nil, err
+		}
+		getter = newMathOperationGetter(getter, rhs.Operator, rhsGetter)
+	}
+	return getter, nil
+}
+
+func newAddSubTermGetter(term *AddSubTerm, functions map[string]interface{}, pathParser PathExpressionParser, enumParser EnumParser) (Getter, error) {
+	getter, err := newMathValueGetter(term.Left, functions, pathParser, enumParser)
+	if err != nil {
+		return nil, err
+	}
+	for _, rhs := range term.Right {
+		rhsGetter, err := newMathValueGetter(rhs.Value, functions, pathParser, enumParser)
+		if err != nil {
+			return nil, err
+		}
+		getter = newMathOperationGetter(getter, rhs.Operator, rhsGetter)
+	}
+	return getter, nil
+}
+
+func newMathValueGetter(val *MathValue, functions map[string]interface{}, pathParser PathExpressionParser, enumParser EnumParser) (Getter, error) {
+	if val.SubExpression != nil {
+		return newMathGetter(val.SubExpression, functions, pathParser, enumParser)
+	}
+	if val.Literal == nil {
+		// In practice, can't happen since the DSL grammar guarantees one is set
+		return nil, fmt.Errorf("no math value field set. This is a bug in the Telemetry Query Language")
+	}
+	return NewGetter(Value{
+		Invocation: val.Literal.Invocation,
+		Float:      val.Literal.Float,
+		Int:        val.Literal.Int,
+		Path:       val.Literal.Path,
+	}, functions, pathParser, enumParser)
+}
+
+// newMathOperationGetter returns a Getter applying the operator to the values of both operands.
+// Operations on two int64 values return an int64, operations involving a float64 value return a float64.
+// The result is nil if either operand isn't a number or when dividing by zero.
+func newMathOperationGetter(lhs Getter, op MathOp, rhs Getter) Getter {
+	return &exprGetter{
+		expr: func(ctx TransformContext) interface{} {
+			x := lhs.Get(ctx)
+			y := rhs.Get(ctx)
+			switch x := x.(type) {
+			case int64:
+				switch y := y.(type) {
+				case int64:
+					return performIntOperation(x, op, y)
+				case float64:
+					return performFloatOperation(float64(x), op, y)
+				}
+			case float64:
+				switch y := y.(type) {
+				case int64:
+					return performFloatOperation(x, op, float64(y))
+				case float64:
+					return performFloatOperation(x, op, y)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func performIntOperation(x int64, op MathOp, y int64) interface{} {
+	switch op {
+	case MathOpAdd:
+		return x + y
+	case MathOpSub:
+		return x - y
+	case MathOpMult:
+		return x * y
+	case MathOpDiv:
+		if y == 0 {
+			return nil
+		}
+		return x / y
+	}
+	return nil
+}
+
+func performFloatOperation(x float64, op MathOp, y float64) interface{} {
+	switch op {
+	case MathOpAdd:
+		return x + y
+	case MathOpSub:
+		return x - y
+	case MathOpMult:
+		return x * y
+	case MathOpDiv:
+		if y == 0 {
+			return nil
+		}
+		return x / y
+	}
+	return nil
+}
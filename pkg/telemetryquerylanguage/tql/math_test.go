@@ -0,0 +1,171 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tql
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql/tqltest"
+)
+
+func mathParsePath(val *Path) (GetSetter, error) {
+	if val != nil && len(val.Fields) > 0 {
+		switch val.Fields[0].Name {
+		case "one":
+			return &StandardGetSetter{
+				Getter: func(ctx TransformContext) interface{} {
+					return int64(1)
+				},
+			}, nil
+		case "half":
+			return &StandardGetSetter{
+				Getter: func(ctx TransformContext) interface{} {
+					return 0.5
+				},
+			}, nil
+		case "name":
+			return &StandardGetSetter{
+				Getter: func(ctx TransformContext) interface{} {
+					return "name"
+				},
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("bad path %v", val)
+}
+
+func three() (ExprFunc, error) {
+	return func(ctx TransformContext) interface{} {
+		return int64(3)
+	}, nil
+}
+
+func Test_newMathGetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected interface{}
+	}{
+		{
+			name:     "int addition",
+			value:    "1 + 2",
+			expected: int64(3),
+		},
+		{
+			name:     "int subtraction",
+			value:    "one - 3",
+			expected: int64(-2),
+		},
+		{
+			name:     "int division is truncated",
+			value:    "7 / 2",
+			expected: int64(3),
+		},
+		{
+			name:     "multiplication and division take precedence",
+			value:    "1 + 2 * 3 - 4 / 2",
+			expected: int64(5),
+		},
+		{
+			name:     "operators of the same precedence are left associative",
+			value:    "8 - 4 - 2",
+			expected: int64(2),
+		},
+		{
+			name:     "parenthesized subexpression",
+			value:    "(1 + 2) * (one + 1)",
+			expected: int64(6),
+		},
+		{
+			name:     "negative operand",
+			value:    "2 * -3",
+			expected: int64(-6),
+		},
+		{
+			name:     "subtraction without spaces",
+			value:    "1-2",
+			expected: int64(-1),
+		},
+		{
+			name:     "path subtraction without spaces",
+			value:    "one-1",
+			expected: int64(0),
+		},
+		{
+			name:     "subtraction of a negative operand",
+			value:    "1 - -2",
+			expected: int64(3),
+		},
+		{
+			name:     "float operations",
+			value:    "1.5 * 2.0 + half",
+			expected: 3.5,
+		},
+		{
+			name:     "int and float operands",
+			value:    "3 / 2.0",
+			expected: 1.5,
+		},
+		{
+			name:     "function operand",
+			value:    "Three() * 2",
+			expected: int64(6),
+		},
+		{
+			name:     "int division by zero",
+			value:    "1 / (one - 1)",
+			expected: nil,
+		},
+		{
+			name:     "float division by zero",
+			value:    "half / 0.0",
+			expected: nil,
+		},
+		{
+			name:     "non numeric operand",
+			value:    "name + 1",
+			expected: nil,
+		},
+	}
+
+	functions := map[string]interface{}{
+		"Three": three,
+		"set":   func(GetSetter, Getter) (ExprFunc, error) { return nil, nil },
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseQuery(fmt.Sprintf("set(name, %s)", tt.value))
+			require.NoError(t, err)
+			require.Len(t, parsed.Invocation.Arguments, 2)
+			require.NotNil(t, parsed.Invocation.Arguments[1].MathExpression)
+
+			getter, err := NewGetter(parsed.Invocation.Arguments[1], functions, mathParsePath, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, getter.Get(tqltest.TestTransformContext{}))
+		})
+	}
+}
+
+func Test_newMathGetter_invalid(t *testing.T) {
+	parsed, err := parseQuery("set(name, unknown * 2)")
+	require.NoError(t, err)
+
+	_, err = NewGetter(parsed.Invocation.Arguments[1], map[string]interface{}{}, mathParsePath, nil)
+	assert.Error(t, err)
+}
@@ -16,6 +16,7 @@ package tql // import "github.com/open-telemetry/opentelemetry-collector-contrib
 
 import (
 	"encoding/hex"
+	"fmt"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
@@ -84,20 +85,67 @@ type Invocation struct {
 }
 
 // Value represents a part of a parsed query which is resolved to a value of some sort. This can be a telemetry path
-// expression, function call, or literal.
+// expression, function call, literal, or math expression. Function calls, numbers and paths are only parsed on their
+// own when they are not followed by a math operator, and enums when they are not the start of a function name.
 // nolint:govet
 type Value struct {
+	Invocation     *Invocation     `( @@ (?! OpAddSub | OpMultDiv)`
+	Bytes          *Bytes          `| @Bytes`
+	String         *string         `| @String`
+	Float          *float64        `| @(OpAddSub? Float) (?! OpAddSub | OpMultDiv)`
+	Int            *int64          `| @(OpAddSub? Int) (?! OpAddSub | OpMultDiv)`
+	Bool           *Boolean        `| @Boolean`
+	IsNil          *IsNil          `| @"nil"`
+	Enum           *EnumSymbol     `| @Uppercase (?! Lowercase | "(")`
+	Path           *Path           `| @@ (?! OpAddSub | OpMultDiv)`
+	MathExpression *MathExpression `| @@ )`
+}
+
+// MathExprLiteral represents an operand of a math expression.
+// nolint:govet
+type MathExprLiteral struct {
 	Invocation *Invocation `( @@`
-	Bytes      *Bytes      `| @Bytes`
-	String     *string     `| @String`
-	Float      *float64    `| @Float`
-	Int        *int64      `| @Int`
-	Bool       *Boolean    `| @Boolean`
-	IsNil      *IsNil      `| @"nil"`
-	Enum       *EnumSymbol `| @Uppercase`
+	Float      *float64    `| @(OpAddSub? Float)`
+	Int        *int64      `| @(OpAddSub? Int)`
 	Path       *Path       `| @@ )`
 }
 
+// MathValue represents either an operand or a parenthesized subexpression of a math expression.
+// nolint:govet
+type MathValue struct {
+	Literal       *MathExprLiteral `( @@`
+	SubExpression *MathExpression  `| "(" @@ ")" )`
+}
+
+// OpMultDivValue represents the right side of a multiplication or a division.
+// nolint:govet
+type OpMultDivValue struct {
+	Operator MathOp     `@OpMultDiv`
+	Value    *MathValue `@@`
+}
+
+// AddSubTerm represents an arbitrary number of math values joined by multiplications or divisions.
+// nolint:govet
+type AddSubTerm struct {
+	Left  *MathValue        `@@`
+	Right []*OpMultDivValue `@@*`
+}
+
+// OpAddSubTerm represents the right side of an addition or a subtraction.
+// nolint:govet
+type OpAddSubTerm struct {
+	Operator MathOp      `@OpAddSub`
+	Term     *AddSubTerm `@@`
+}
+
+// MathExpression represents an arithmetic expression as an arbitrary number of terms joined by
+// additions or subtractions, so that multiplications and divisions take precedence.
+// nolint:govet
+type MathExpression struct {
+	Left  *AddSubTerm     `@@`
+	Right []*OpAddSubTerm `@@*`
+}
+
 // Path represents a telemetry path expression.
 // nolint:govet
 type Path struct {
@@ -149,6 +197,41 @@ func (n *IsNil) Capture(_ []string) error {
 
 type EnumSymbol string
 
+// MathOp Type for capturing math operators.
+type MathOp int
+
+const (
+	MathOpAdd MathOp = iota
+	MathOpSub
+	MathOpMult
+	MathOpDiv
+)
+
+var mathOps = map[string]MathOp{
+	"+": MathOpAdd,
+	"-": MathOpSub,
+	"*": MathOpMult,
+	"/": MathOpDiv,
+}
+
+func (m *MathOp) Capture(values []string) error {
+	op, ok := mathOps[values[0]]
+	if !ok {
+		return fmt.Errorf("'%s' is not a valid math operator", values[0])
+	}
+	*m = op
+	return nil
+}
+
+func (m MathOp) String() string {
+	for str, op := range mathOps {
+		if op == m {
+			return str
+		}
+	}
+	return "<unknown math operator>"
+}
+
 func ParseQueries(statements []string, functions map[string]interface{}, pathParser PathExpressionParser, enumParser EnumParser) ([]Query, error) {
 	queries := make([]Query, 0)
 	var errors error
@@ -181,7 +264,42 @@ func ParseQueries(statements []string, functions map[string]interface{}, pathPar
 	return queries, nil
 }
 
-var parser = newParser()
+// ParseConditions parses standalone boolean expressions, using the same grammar as the where clause of a query,
+// into BoolExpressionEvaluators. This allows components to make decisions about telemetry without invoking a function.
+func ParseConditions(conditions []string, functions map[string]interface{}, pathParser PathExpressionParser, enumParser EnumParser) ([]BoolExpressionEvaluator, error) {
+	evaluators := make([]BoolExpressionEvaluator, 0, len(conditions))
+	var errors error
+
+	for _, condition := range conditions {
+		parsed, err := parseCondition(condition)
+		if err != nil {
+			errors = multierr.Append(errors, err)
+			continue
+		}
+		evaluator, err := newBooleanExpressionEvaluator(parsed, functions, pathParser, enumParser)
+		if err != nil {
+			errors = multierr.Append(errors, err)
+			continue
+		}
+		evaluators = append(evaluators, evaluator)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+	return evaluators, nil
+}
+
+var parser = newParser[ParsedQuery]()
+var conditionParser = newParser[BooleanExpression]()
+
+func parseCondition(raw string) (*BooleanExpression, error) {
+	parsed, err := conditionParser.ParseString("", raw)
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
 
 func parseQuery(raw string) (*ParsedQuery, error) {
 	parsed, err := parser.ParseString("", raw)
@@ -197,12 +315,14 @@ func parseQuery(raw string) (*ParsedQuery, error) {
 func buildLexer() *lexer.StatefulDefinition {
 	return lexer.MustSimple([]lexer.SimpleRule{
 		{Name: `Bytes`, Pattern: `0x[a-fA-F0-9]+`},
-		{Name: `Float`, Pattern: `[-+]?\d*\.\d+([eE][-+]?\d+)?`},
-		{Name: `Int`, Pattern: `[-+]?\d+`},
+		{Name: `Float`, Pattern: `\d*\.\d+([eE][-+]?\d+)?`},
+		{Name: `Int`, Pattern: `\d+`},
 		{Name: `String`, Pattern: `"(\\"|[^"])*"`},
 		{Name: `OpOr`, Pattern: `\b(or)\b`},
 		{Name: `OpAnd`, Pattern: `\b(and)\b`},
 		{Name: `OpComparison`, Pattern: `==|!=`},
+		{Name: `OpAddSub`, Pattern: `\+|\-`},
+		{Name: `OpMultDiv`, Pattern: `\/|\*`},
 		{Name: `Boolean`, Pattern: `\b(true|false)\b`},
 		{Name: `LParen`, Pattern: `\(`},
 		{Name: `RParen`, Pattern: `\)`},
@@ -213,14 +333,16 @@ func buildLexer() *lexer.StatefulDefinition {
 	})
 }
 
-// newParser returns a parser that can be used to read a string into a ParsedQuery. An error will be returned if the string
-// is not formatted for the DSL.
-func newParser() *participle.Parser[ParsedQuery] {
+// newParser returns a parser that can be used to read a string into a ParsedQuery, or any other grammar node such as
+// a BooleanExpression. An error will be returned if the string is not formatted for the DSL.
+func newParser[G any]() *participle.Parser[G] {
 	lex := buildLexer()
-	parser, err := participle.Build[ParsedQuery](
+	parser, err := participle.Build[G](
 		participle.Lexer(lex),
 		participle.Unquote("String"),
 		participle.Elide("whitespace"),
+		// Allows the negative lookahead in Value to backtrack out of an operand followed by a math operator.
+		participle.UseLookahead(participle.MaxLookahead),
 	)
 	if err != nil {
 		panic("Unable to initialize parser; this is a programming error in the transformprocessor:" + err.Error())
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql/tqltest"
 )
@@ -435,6 +436,155 @@ func Test_parse(t *testing.T) {
 	}
 }
 
+func Test_parse_math(t *testing.T) {
+	path := func(name string) *Path {
+		return &Path{Fields: []Field{{Name: name}}}
+	}
+	query := `set(attributes["duration_ms"], (end_time_unix_nano - start_time_unix_nano) / 1000000)`
+	expected := &ParsedQuery{
+		Invocation: Invocation{
+			Function: "set",
+			Arguments: []Value{
+				{
+					Path: &Path{
+						Fields: []Field{
+							{
+								Name:   "attributes",
+								MapKey: tqltest.Strp("duration_ms"),
+							},
+						},
+					},
+				},
+				{
+					MathExpression: &MathExpression{
+						Left: &AddSubTerm{
+							Left: &MathValue{
+								SubExpression: &MathExpression{
+									Left: &AddSubTerm{
+										Left: &MathValue{
+											Literal: &MathExprLiteral{
+												Path: path("end_time_unix_nano"),
+											},
+										},
+									},
+									Right: []*OpAddSubTerm{
+										{
+											Operator: MathOpSub,
+											Term: &AddSubTerm{
+												Left: &MathValue{
+													Literal: &MathExprLiteral{
+														Path: path("start_time_unix_nano"),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+							Right: []*OpMultDivValue{
+								{
+									Operator: MathOpDiv,
+									Value: &MathValue{
+										Literal: &MathExprLiteral{
+											Int: tqltest.Intp(1000000),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	parsed, err := parseQuery(query)
+	assert.NoError(t, err)
+	assert.EqualValues(t, expected, parsed)
+}
+
+func Test_parse_subtraction(t *testing.T) {
+	intLiteral := func(i int64) *MathValue {
+		return &MathValue{Literal: &MathExprLiteral{Int: tqltest.Intp(i)}}
+	}
+	pathLiteral := func(name string) *MathValue {
+		return &MathValue{Literal: &MathExprLiteral{Path: &Path{Fields: []Field{{Name: name}}}}}
+	}
+	subtract := func(v *MathValue) []*OpAddSubTerm {
+		return []*OpAddSubTerm{{Operator: MathOpSub, Term: &AddSubTerm{Left: v}}}
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		expected *MathExpression
+	}{
+		{
+			name:  "path minus int",
+			value: `a-1`,
+			expected: &MathExpression{
+				Left:  &AddSubTerm{Left: pathLiteral("a")},
+				Right: subtract(intLiteral(1)),
+			},
+		},
+		{
+			name:  "int minus int",
+			value: `1-2`,
+			expected: &MathExpression{
+				Left:  &AddSubTerm{Left: intLiteral(1)},
+				Right: subtract(intLiteral(2)),
+			},
+		},
+		{
+			name:  "division then subtraction",
+			value: `x/1000000-1`,
+			expected: &MathExpression{
+				Left: &AddSubTerm{
+					Left:  pathLiteral("x"),
+					Right: []*OpMultDivValue{{Operator: MathOpDiv, Value: intLiteral(1000000)}},
+				},
+				Right: subtract(intLiteral(1)),
+			},
+		},
+		{
+			name:  "subtraction of a negative int",
+			value: `1 - -2`,
+			expected: &MathExpression{
+				Left:  &AddSubTerm{Left: intLiteral(1)},
+				Right: subtract(intLiteral(-2)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseQuery(fmt.Sprintf("set(x, %s)", tt.value))
+			require.NoError(t, err)
+			require.Len(t, parsed.Invocation.Arguments, 2)
+			assert.Equal(t, tt.expected, parsed.Invocation.Arguments[1].MathExpression)
+		})
+	}
+}
+
+func Test_parse_negative_literals(t *testing.T) {
+	parsed, err := parseQuery(`set(x, -1, -1.5, +2)`)
+	require.NoError(t, err)
+	require.Len(t, parsed.Invocation.Arguments, 4)
+	assert.Equal(t, tqltest.Intp(-1), parsed.Invocation.Arguments[1].Int)
+	assert.Equal(t, tqltest.Floatp(-1.5), parsed.Invocation.Arguments[2].Float)
+	assert.Equal(t, tqltest.Intp(2), parsed.Invocation.Arguments[3].Int)
+}
+
+func Test_parseWhere_math(t *testing.T) {
+	parsed, err := parseQuery(`set(name, "test") where (attributes["count"] + 1) * 2 == 4 and (name == "foo")`)
+	assert.NoError(t, err)
+	comparison := parsed.WhereClause.Left.Left.Comparison
+	if assert.NotNil(t, comparison) {
+		assert.NotNil(t, comparison.Left.MathExpression)
+		assert.Equal(t, tqltest.Intp(4), comparison.Right.Int)
+	}
+	assert.NotNil(t, parsed.WhereClause.Left.Right[0].Value.SubExpr)
+}
+
 func Test_parse_failure(t *testing.T) {
 	tests := []string{
 		`set(`,
@@ -457,6 +607,10 @@ func Test_parse_failure(t *testing.T) {
 		`set("foo") where )`,
 		`set("foo") where (name == "fido"))`,
 		`set("foo") where ((name == "fido")`,
+		`set(name, 1 +)`,
+		`set(name, * 2)`,
+		`set(name, (1 + 2)`,
+		`set(name, "foo" + 1)`,
 	}
 	for _, tt := range tests {
 		t.Run(tt, func(t *testing.T) {
@@ -787,6 +941,71 @@ func Test_parseWhere(t *testing.T) {
 	}
 }
 
+func Test_parseCondition(t *testing.T) {
+	parsed, err := parseCondition(`name == "foo" or (true and false)`)
+	assert.NoError(t, err)
+	assert.Equal(t, &BooleanExpression{
+		Left: &Term{
+			Left: &BooleanValue{
+				Comparison: &Comparison{
+					Left: Value{
+						Path: &Path{
+							Fields: []Field{
+								{
+									Name: "name",
+								},
+							},
+						},
+					},
+					Op: "==",
+					Right: Value{
+						String: tqltest.Strp("foo"),
+					},
+				},
+			},
+		},
+		Right: []*OpOrTerm{
+			{
+				Operator: "or",
+				Term: &Term{
+					Left: &BooleanValue{
+						SubExpr: &BooleanExpression{
+							Left: &Term{
+								Left: &BooleanValue{
+									ConstExpr: Booleanp(true),
+								},
+								Right: []*OpAndBooleanValue{
+									{
+										Operator: "and",
+										Value: &BooleanValue{
+											ConstExpr: Booleanp(false),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, parsed)
+
+	_, err = parseCondition(`set(name, "foo") where true`)
+	assert.Error(t, err)
+}
+
+func Test_ParseConditions(t *testing.T) {
+	evaluators, err := ParseConditions([]string{`true`, `true and false`, `false or 1 == 1`}, DefaultFunctionsForTests(), testParsePath, testParseEnum)
+	assert.NoError(t, err)
+	assert.Len(t, evaluators, 3)
+	assert.True(t, evaluators[0](nil))
+	assert.False(t, evaluators[1](nil))
+	assert.True(t, evaluators[2](nil))
+
+	_, err = ParseConditions([]string{`true`, `name ==`, `Unknown() == 1`}, DefaultFunctionsForTests(), testParsePath, testParseEnum)
+	assert.Error(t, err)
+}
+
 var testSymbolTable = map[EnumSymbol]Enum{
 	"TEST_ENUM":     0,
 	"TEST_ENUM_ONE": 1,
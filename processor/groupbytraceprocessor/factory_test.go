@@ -20,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 )
 
 func TestDefaultConfiguration(t *testing.T) {
@@ -47,33 +48,43 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, p)
 }
 
-func TestCreateTestProcessorWithNotImplementedOptions(t *testing.T) {
+func TestCreateTestProcessorWithDiskStorage(t *testing.T) {
 	// prepare
 	f := NewFactory()
 	next := &mockProcessor{}
+	storageID := config.NewComponentID("file_storage")
 
-	// test
 	for _, tt := range []struct {
+		name        string
 		config      *Config
 		expectedErr error
 	}{
 		{
-			&Config{
-				DiscardOrphans: true,
-			},
-			errDiscardOrphansNotSupported,
+			name:        "store on disk without storage",
+			config:      &Config{StoreOnDisk: true},
+			expectedErr: errStorageIDRequired,
+		},
+		{
+			name:   "store on disk",
+			config: &Config{StoreOnDisk: true, StorageID: &storageID},
 		},
 		{
-			&Config{
-				StoreOnDisk: true,
-			},
-			errDiskStorageNotSupported,
+			name:   "discard orphans",
+			config: &Config{DiscardOrphans: true},
 		},
 	} {
-		p, err := f.CreateTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.config, next)
+		t.Run(tt.name, func(t *testing.T) {
+			// test
+			p, err := f.CreateTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.config, next)
 
-		// verify
-		assert.Error(t, tt.expectedErr, err)
-		assert.Nil(t, p)
+			// verify
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, p)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, p)
+		})
 	}
 }
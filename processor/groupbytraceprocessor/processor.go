@@ -93,20 +93,21 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
 	// start these metrics, as it might take a while for them to receive their first event
 	stats.Record(context.Background(), mTracesEvicted.M(0))
 	stats.Record(context.Background(), mIncompleteReleases.M(0))
+	stats.Record(context.Background(), mDiscardedOrphans.M(0))
 	stats.Record(context.Background(), mNumTracesConf.M(int64(sp.config.NumTraces)))
 
 	sp.eventMachine.startInBackground()
-	return sp.st.start()
+	return sp.st.start(ctx, host)
 }
 
 // Shutdown is invoked during service shutdown.
-func (sp *groupByTraceProcessor) Shutdown(_ context.Context) error {
+func (sp *groupByTraceProcessor) Shutdown(ctx context.Context) error {
 	sp.eventMachine.shutdown()
-	return sp.st.shutdown()
+	return sp.st.shutdown(ctx)
 }
 
 func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eventMachineWorker) error {
@@ -217,6 +218,13 @@ func (sp *groupByTraceProcessor) onTraceReleased(rss []ptrace.ResourceSpans) err
 		trs := trace.ResourceSpans().AppendEmpty()
 		rs.CopyTo(trs)
 	}
+
+	if sp.config.DiscardOrphans && !hasRootSpan(trace) {
+		sp.logger.Debug("discarding orphan trace", zap.Int("spans", trace.SpanCount()))
+		stats.Record(context.Background(), mDiscardedOrphans.M(1))
+		return nil
+	}
+
 	stats.Record(context.Background(),
 		mReleasedSpans.M(int64(trace.SpanCount())),
 		mReleasedTraces.M(1),
@@ -248,3 +256,20 @@ func (sp *groupByTraceProcessor) addSpans(traceID pcommon.TraceID, trace ptrace.
 	sp.logger.Debug("creating trace at the storage", zap.String("traceID", traceID.HexString()))
 	return sp.st.createOrAppend(traceID, trace)
 }
+
+// hasRootSpan returns whether any of the spans of the trace has no parent.
+func hasRootSpan(trace ptrace.Traces) bool {
+	rss := trace.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if spans.At(k).ParentSpanID().IsEmpty() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
 )
 
@@ -587,6 +588,96 @@ func BenchmarkConsumeTracesCompleteOnFirstBatch(b *testing.B) {
 	}
 }
 
+func TestTraceIsDispatchedFromDiskStorage(t *testing.T) {
+	// prepare
+	traces := simpleTraces()
+
+	wg := &sync.WaitGroup{}
+	config := Config{
+		WaitDuration: time.Nanosecond,
+		NumTraces:    10,
+		NumWorkers:   4,
+	}
+	mockProcessor := &mockProcessor{
+		onTraces: func(ctx context.Context, received ptrace.Traces) error {
+			assert.Equal(t, traces, received)
+			wg.Done()
+			return nil
+		},
+	}
+
+	st := newDiskStorage(zap.NewNop(), storagetest.NewStorageID("groupbytrace"), config.ID())
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("groupbytrace", t.TempDir())
+
+	p := newGroupByTraceProcessor(zap.NewNop(), st, mockProcessor, config)
+	ctx := context.Background()
+	require.NoError(t, p.Start(ctx, host))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	// test
+	wg.Add(1)
+	assert.NoError(t, p.ConsumeTraces(ctx, traces))
+
+	// verify
+	wg.Wait()
+}
+
+func TestOrphanTracesAreDiscarded(t *testing.T) {
+	// prepare
+	wg := &sync.WaitGroup{}
+	var received []ptrace.Traces
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, traces ptrace.Traces) error {
+			received = append(received, traces)
+			wg.Done()
+			return nil
+		},
+	}
+
+	orphan := simpleTracesWithID(pcommon.NewTraceID([16]byte{1, 2, 3, 4}))
+	orphan.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetParentSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4}))
+	complete := simpleTracesWithID(pcommon.NewTraceID([16]byte{2, 3, 4, 5}))
+	child := complete.ResourceSpans().At(0).ScopeSpans().At(0).Spans().AppendEmpty()
+	child.SetParentSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4}))
+
+	for _, tt := range []struct {
+		name           string
+		discardOrphans bool
+		expected       []ptrace.Traces
+	}{
+		{
+			name:           "discard orphans",
+			discardOrphans: true,
+			expected:       []ptrace.Traces{complete},
+		},
+		{
+			name:     "keep orphans",
+			expected: []ptrace.Traces{orphan, complete},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			received = nil
+			p := newGroupByTraceProcessor(zap.NewNop(), newMemoryStorage(), next, Config{
+				WaitDuration:   time.Nanosecond,
+				NumTraces:      10,
+				NumWorkers:     1,
+				DiscardOrphans: tt.discardOrphans,
+			})
+
+			// test
+			wg.Add(len(tt.expected))
+			assert.NoError(t, p.onTraceReleased([]ptrace.ResourceSpans{orphan.ResourceSpans().At(0)}))
+			assert.NoError(t, p.onTraceReleased([]ptrace.ResourceSpans{complete.ResourceSpans().At(0)}))
+			wg.Wait()
+
+			// verify
+			assert.ElementsMatch(t, tt.expected, received)
+		})
+	}
+}
+
 type mockProcessor struct {
 	mutex    sync.Mutex
 	onTraces func(context.Context, ptrace.Traces) error
@@ -640,13 +731,13 @@ func (st *mockStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans,
 	}
 	return nil, nil
 }
-func (st *mockStorage) start() error {
+func (st *mockStorage) start(context.Context, component.Host) error {
 	if st.onStart != nil {
 		return st.onStart()
 	}
 	return nil
 }
-func (st *mockStorage) shutdown() error {
+func (st *mockStorage) shutdown(context.Context) error {
 	if st.onShutdown != nil {
 		return st.onShutdown()
 	}
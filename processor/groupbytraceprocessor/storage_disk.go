@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	extensionstorage "go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// slotCountKey is the key of the number of index slots ever allocated, which bounds the
+// slots to read when purging the traces of a previous run.
+const slotCountKey = "index/slots"
+
+// diskStorage keeps only the trace IDs in memory, serializing the spans of each trace
+// with a storage extension client. Every batch appended to a trace is stored under its
+// own key. Each stored trace also owns an index slot, holding its ID and its number of
+// batches, so that the traces left behind by a previous run can be purged on start.
+type diskStorage struct {
+	sync.Mutex
+	storageID   config.ComponentID
+	componentID config.ComponentID
+	logger      *zap.Logger
+	client      extensionstorage.Client
+	// traces holds the index slot and the number of batches of each trace currently in the storage
+	traces map[pcommon.TraceID]storedTrace
+	// slotCount is the number of index slots allocated so far, freeSlots the ones not used anymore
+	slotCount                 int
+	freeSlots                 []int
+	marshaler                 ptrace.Marshaler
+	unmarshaler               ptrace.Unmarshaler
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+var _ storage = (*diskStorage)(nil)
+
+type storedTrace struct {
+	slot    int
+	batches int
+}
+
+func newDiskStorage(logger *zap.Logger, storageID config.ComponentID, componentID config.ComponentID) *diskStorage {
+	st := &diskStorage{
+		storageID:                 storageID,
+		componentID:               componentID,
+		logger:                    logger,
+		traces:                    make(map[pcommon.TraceID]storedTrace),
+		marshaler:                 ptrace.NewProtoMarshaler(),
+		unmarshaler:               ptrace.NewProtoUnmarshaler(),
+		metricsCollectionInterval: time.Second,
+	}
+	return st
+}
+
+func (st *diskStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	st.Lock()
+	defer st.Unlock()
+
+	buf, err := st.marshaler.MarshalTraces(td)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal trace %q: %w", traceID.HexString(), err)
+	}
+
+	trace, exists := st.traces[traceID]
+	newSlot := false
+	if !exists {
+		if n := len(st.freeSlots); n > 0 {
+			trace.slot = st.freeSlots[n-1]
+		} else {
+			trace.slot = st.slotCount
+			newSlot = true
+		}
+	}
+
+	ops := []extensionstorage.Operation{
+		extensionstorage.SetOperation(batchKey(traceID, trace.batches), buf),
+		extensionstorage.SetOperation(slotKey(trace.slot), encodeSlot(traceID, trace.batches+1)),
+	}
+	if newSlot {
+		ops = append(ops, extensionstorage.SetOperation(slotCountKey, []byte(strconv.Itoa(st.slotCount+1))))
+	}
+	if err = st.client.Batch(context.Background(), ops...); err != nil {
+		return fmt.Errorf("couldn't write trace %q to the storage: %w", traceID.HexString(), err)
+	}
+
+	if newSlot {
+		st.slotCount++
+	} else if !exists {
+		st.freeSlots = st.freeSlots[:len(st.freeSlots)-1]
+	}
+	trace.batches++
+	st.traces[traceID] = trace
+	return nil
+}
+
+func (st *diskStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	trace, ok := st.traces[traceID]
+	if !ok {
+		return nil, nil
+	}
+	return st.load(traceID, trace.batches)
+}
+
+func (st *diskStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	trace, ok := st.traces[traceID]
+	if !ok {
+		return nil, nil
+	}
+
+	rss, err := st.load(traceID, trace.batches)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := deleteOperations(traceID, trace.batches)
+	ops = append(ops, extensionstorage.DeleteOperation(slotKey(trace.slot)))
+	if err = st.client.Batch(context.Background(), ops...); err != nil {
+		return nil, fmt.Errorf("couldn't delete trace %q from the storage: %w", traceID.HexString(), err)
+	}
+
+	delete(st.traces, traceID)
+	st.freeSlots = append(st.freeSlots, trace.slot)
+	return rss, nil
+}
+
+func (st *diskStorage) start(ctx context.Context, host component.Host) error {
+	extension, ok := host.GetExtensions()[st.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", st.storageID)
+	}
+
+	storageExtension, ok := extension.(extensionstorage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", st.storageID)
+	}
+
+	client, err := storageExtension.GetClient(ctx, component.KindProcessor, st.componentID, "")
+	if err != nil {
+		return err
+	}
+	st.client = client
+
+	if err = st.purge(ctx); err != nil {
+		return fmt.Errorf("couldn't purge the traces of the previous run from the storage: %w", err)
+	}
+
+	go st.periodicMetrics()
+	return nil
+}
+
+func (st *diskStorage) shutdown(ctx context.Context) error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	if st.client == nil {
+		return nil
+	}
+	return st.client.Close(ctx)
+}
+
+// purge deletes the traces stored by a previous run, which can't be released anymore since
+// the state of the processor is only kept in memory.
+func (st *diskStorage) purge(ctx context.Context) error {
+	buf, err := st.client.Get(ctx, slotCountKey)
+	if err != nil || buf == nil {
+		return err
+	}
+	slotCount, err := strconv.Atoi(string(buf))
+	if err != nil {
+		return fmt.Errorf("invalid index slot count %q: %w", buf, err)
+	}
+
+	slotOps := make([]extensionstorage.Operation, slotCount)
+	for i := range slotOps {
+		slotOps[i] = extensionstorage.GetOperation(slotKey(i))
+	}
+	if err = st.client.Batch(ctx, slotOps...); err != nil {
+		return err
+	}
+
+	var ops []extensionstorage.Operation
+	purged := 0
+	for i, op := range slotOps {
+		if op.Value == nil {
+			continue
+		}
+		traceID, batches, err := decodeSlot(op.Value)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, deleteOperations(traceID, batches)...)
+		ops = append(ops, extensionstorage.DeleteOperation(slotKey(i)))
+		purged++
+	}
+	ops = append(ops, extensionstorage.DeleteOperation(slotCountKey))
+	if err = st.client.Batch(ctx, ops...); err != nil {
+		return err
+	}
+	if purged > 0 {
+		st.logger.Info("Purged the traces left in the storage by a previous run", zap.Int("traces", purged))
+	}
+	return nil
+}
+
+// load reads the n batches stored for the trace.
+func (st *diskStorage) load(traceID pcommon.TraceID, n int) ([]ptrace.ResourceSpans, error) {
+	ops := make([]extensionstorage.Operation, n)
+	for i := range ops {
+		ops[i] = extensionstorage.GetOperation(batchKey(traceID, i))
+	}
+	if err := st.client.Batch(context.Background(), ops...); err != nil {
+		return nil, fmt.Errorf("couldn't read trace %q from the storage: %w", traceID.HexString(), err)
+	}
+
+	var result []ptrace.ResourceSpans
+	for _, op := range ops {
+		trace, err := st.unmarshaler.UnmarshalTraces(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't unmarshal trace %q: %w", traceID.HexString(), err)
+		}
+		result = append(result, resourceSpansOf(trace)...)
+	}
+	return result, nil
+}
+
+// deleteOperations returns the operations deleting the n batches stored for the trace.
+func deleteOperations(traceID pcommon.TraceID, n int) []extensionstorage.Operation {
+	ops := make([]extensionstorage.Operation, 0, n+1)
+	for i := 0; i < n; i++ {
+		ops = append(ops, extensionstorage.DeleteOperation(batchKey(traceID, i)))
+	}
+	return ops
+}
+
+// encodeSlot serializes the index slot of a trace: its ID followed by its number of batches.
+func encodeSlot(traceID pcommon.TraceID, batches int) []byte {
+	id := traceID.Bytes()
+	buf := make([]byte, len(id)+binary.MaxVarintLen64)
+	copy(buf, id[:])
+	n := binary.PutUvarint(buf[len(id):], uint64(batches))
+	return buf[:len(id)+n]
+}
+
+func decodeSlot(buf []byte) (pcommon.TraceID, int, error) {
+	var id [16]byte
+	if len(buf) <= len(id) {
+		return pcommon.NewTraceID(id), 0, fmt.Errorf("invalid index slot of %d bytes", len(buf))
+	}
+	copy(id[:], buf)
+	batches, n := binary.Uvarint(buf[len(id):])
+	if n <= 0 {
+		return pcommon.NewTraceID(id), 0, fmt.Errorf("invalid batch count in the index slot of trace %q", pcommon.NewTraceID(id).HexString())
+	}
+	return pcommon.NewTraceID(id), int(batches), nil
+}
+
+func batchKey(traceID pcommon.TraceID, i int) string {
+	return traceID.HexString() + "/" + strconv.Itoa(i)
+}
+
+func slotKey(slot int) string {
+	return "index/" + strconv.Itoa(slot)
+}
+
+func (st *diskStorage) periodicMetrics() {
+	numTraces := st.count()
+	stats.Record(context.Background(), mNumTracesInMemory.M(int64(numTraces)))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *diskStorage) count() int {
+	st.Lock()
+	defer st.Unlock()
+	return len(st.traces)
+}
+
+func resourceSpansOf(trace ptrace.Traces) []ptrace.ResourceSpans {
+	rss := trace.ResourceSpans()
+	result := make([]ptrace.ResourceSpans, 0, rss.Len())
+	for i := 0; i < rss.Len(); i++ {
+		result = append(result, rss.At(i))
+	}
+	return result
+}
@@ -0,0 +1,307 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+func newStartedDiskStorage(t *testing.T) *diskStorage {
+	st := startDiskStorage(t, t.TempDir())
+	t.Cleanup(func() {
+		assert.NoError(t, st.shutdown(context.Background()))
+	})
+	return st
+}
+
+func startDiskStorage(t *testing.T, dir string) *diskStorage {
+	st := newDiskStorage(zap.NewNop(), storagetest.NewStorageID("groupbytrace"), config.NewComponentID(typeStr))
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("groupbytrace", dir)
+	require.NoError(t, st.start(context.Background(), host))
+	return st
+}
+
+func TestDiskCreateAndGetTrace(t *testing.T) {
+	// prepare
+	st := newStartedDiskStorage(t)
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.NewTraceID([16]byte{1, 2, 3, 4}),
+		pcommon.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	baseTrace := ptrace.NewTraces()
+	rss := baseTrace.ResourceSpans()
+	rs := rss.AppendEmpty()
+	ils := rs.ScopeSpans().AppendEmpty()
+	span := ils.Spans().AppendEmpty()
+
+	// test
+	for _, traceID := range traceIDs {
+		span.SetTraceID(traceID)
+		assert.NoError(t, st.createOrAppend(traceID, baseTrace))
+	}
+
+	// verify
+	assert.Equal(t, 2, st.count())
+	for _, traceID := range traceIDs {
+		expected := []ptrace.ResourceSpans{baseTrace.ResourceSpans().At(0)}
+		expected[0].ScopeSpans().At(0).Spans().At(0).SetTraceID(traceID)
+
+		retrieved, err := st.get(traceID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, retrieved)
+	}
+}
+
+func TestDiskDeleteTrace(t *testing.T) {
+	// prepare
+	st := newStartedDiskStorage(t)
+
+	traceID := pcommon.NewTraceID([16]byte{1, 2, 3, 4})
+
+	trace := ptrace.NewTraces()
+	rss := trace.ResourceSpans()
+	rs := rss.AppendEmpty()
+	ils := rs.ScopeSpans().AppendEmpty()
+	span := ils.Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+
+	assert.NoError(t, st.createOrAppend(traceID, trace))
+
+	// test
+	deleted, err := st.delete(traceID)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.ResourceSpans{trace.ResourceSpans().At(0)}, deleted)
+	assert.Equal(t, 0, st.count())
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestDiskAppendSpans(t *testing.T) {
+	// prepare
+	st := newStartedDiskStorage(t)
+
+	traceID := pcommon.NewTraceID([16]byte{1, 2, 3, 4})
+
+	trace := ptrace.NewTraces()
+	rss := trace.ResourceSpans()
+	rs := rss.AppendEmpty()
+	ils := rs.ScopeSpans().AppendEmpty()
+	span := ils.Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4}))
+
+	assert.NoError(t, st.createOrAppend(traceID, trace))
+
+	secondTrace := ptrace.NewTraces()
+	secondRss := secondTrace.ResourceSpans()
+	secondRs := secondRss.AppendEmpty()
+	secondIls := secondRs.ScopeSpans().AppendEmpty()
+	secondSpan := secondIls.Spans().AppendEmpty()
+	secondSpan.SetName("second-name")
+	secondSpan.SetTraceID(traceID)
+	secondSpan.SetSpanID(pcommon.NewSpanID([8]byte{5, 6, 7, 8}))
+
+	expected := []ptrace.ResourceSpans{
+		ptrace.NewResourceSpans(),
+		ptrace.NewResourceSpans(),
+	}
+	ils.CopyTo(expected[0].ScopeSpans().AppendEmpty())
+	secondIls.CopyTo(expected[1].ScopeSpans().AppendEmpty())
+
+	// test
+	err := st.createOrAppend(traceID, secondTrace)
+	require.NoError(t, err)
+
+	// override something in the second span, to make sure we are storing a copy
+	secondSpan.SetName("changed-second-name")
+
+	// verify
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 2)
+	assert.Equal(t, "second-name", retrieved[1].ScopeSpans().At(0).Spans().At(0).Name())
+
+	// now that we checked that the secondSpan change here didn't have an effect, revert
+	// so that we can compare the that everything else has the same value
+	secondSpan.SetName("second-name")
+	assert.Equal(t, expected, retrieved)
+}
+
+func TestDiskAppendStoresBatchesSeparately(t *testing.T) {
+	st := newStartedDiskStorage(t)
+	ctx := context.Background()
+
+	traceID := pcommon.NewTraceID([16]byte{1, 2, 3, 4})
+	trace := ptrace.NewTraces()
+	trace.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetTraceID(traceID)
+
+	require.NoError(t, st.createOrAppend(traceID, trace))
+	first, err := st.client.Get(ctx, batchKey(traceID, 0))
+	require.NoError(t, err)
+
+	// appending doesn't rewrite the batches already stored
+	require.NoError(t, st.createOrAppend(traceID, trace))
+	unchanged, err := st.client.Get(ctx, batchKey(traceID, 0))
+	require.NoError(t, err)
+	assert.Equal(t, first, unchanged)
+	second, err := st.client.Get(ctx, batchKey(traceID, 1))
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	_, err = st.delete(traceID)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		buf, err := st.client.Get(ctx, batchKey(traceID, i))
+		require.NoError(t, err)
+		assert.Nil(t, buf)
+	}
+}
+
+func TestDiskPurgesPreviousRun(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.NewTraceID([16]byte{1, 2, 3, 4}),
+		pcommon.NewTraceID([16]byte{1, 3, 4, 5}),
+		pcommon.NewTraceID([16]byte{2, 3, 4, 5}),
+	}
+	trace := ptrace.NewTraces()
+	trace.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+
+	st := startDiskStorage(t, dir)
+	for _, traceID := range traceIDs {
+		require.NoError(t, st.createOrAppend(traceID, trace))
+		require.NoError(t, st.createOrAppend(traceID, trace))
+	}
+	_, err := st.delete(traceIDs[1])
+	require.NoError(t, err)
+	require.NoError(t, st.shutdown(ctx))
+
+	// the traces of the previous run can't be released anymore, so they are deleted
+	st = startDiskStorage(t, dir)
+	defer func() {
+		assert.NoError(t, st.shutdown(ctx))
+	}()
+	assert.Equal(t, 0, st.count())
+	for _, traceID := range traceIDs {
+		for i := 0; i < 2; i++ {
+			buf, err := st.client.Get(ctx, batchKey(traceID, i))
+			require.NoError(t, err)
+			assert.Nil(t, buf)
+		}
+	}
+	for i := 0; i < len(traceIDs); i++ {
+		buf, err := st.client.Get(ctx, slotKey(i))
+		require.NoError(t, err)
+		assert.Nil(t, buf)
+	}
+	buf, err := st.client.Get(ctx, slotCountKey)
+	require.NoError(t, err)
+	assert.Nil(t, buf)
+}
+
+func TestDiskIndexSlots(t *testing.T) {
+	st := newStartedDiskStorage(t)
+	ctx := context.Background()
+
+	first := pcommon.NewTraceID([16]byte{1, 2, 3, 4})
+	second := pcommon.NewTraceID([16]byte{1, 3, 4, 5})
+	trace := ptrace.NewTraces()
+	trace.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+
+	require.NoError(t, st.createOrAppend(first, trace))
+	require.NoError(t, st.createOrAppend(first, trace))
+	require.NoError(t, st.createOrAppend(second, trace))
+
+	// each trace owns a slot holding its ID and its number of batches
+	for slot, expected := range []struct {
+		traceID pcommon.TraceID
+		batches int
+	}{{first, 2}, {second, 1}} {
+		buf, err := st.client.Get(ctx, slotKey(slot))
+		require.NoError(t, err)
+		traceID, batches, err := decodeSlot(buf)
+		require.NoError(t, err)
+		assert.Equal(t, expected.traceID, traceID)
+		assert.Equal(t, expected.batches, batches)
+	}
+	buf, err := st.client.Get(ctx, slotCountKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(buf))
+
+	// the slot of a deleted trace is reused by the next new trace
+	_, err = st.delete(first)
+	require.NoError(t, err)
+	buf, err = st.client.Get(ctx, slotKey(0))
+	require.NoError(t, err)
+	assert.Nil(t, buf)
+
+	third := pcommon.NewTraceID([16]byte{2, 3, 4, 5})
+	require.NoError(t, st.createOrAppend(third, trace))
+	buf, err = st.client.Get(ctx, slotKey(0))
+	require.NoError(t, err)
+	traceID, batches, err := decodeSlot(buf)
+	require.NoError(t, err)
+	assert.Equal(t, third, traceID)
+	assert.Equal(t, 1, batches)
+	buf, err = st.client.Get(ctx, slotCountKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(buf))
+}
+
+func TestDiskStartWithInvalidExtension(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		host *storagetest.StorageHost
+	}{
+		{
+			name: "missing extension",
+			host: storagetest.NewStorageHost(),
+		},
+		{
+			name: "non-storage extension",
+			host: storagetest.NewStorageHost().WithNonStorageExtension("groupbytrace"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newDiskStorage(zap.NewNop(), storagetest.NewNonStorageID("groupbytrace"), config.NewComponentID(typeStr))
+			if tt.name == "missing extension" {
+				st = newDiskStorage(zap.NewNop(), storagetest.NewStorageID("groupbytrace"), config.NewComponentID(typeStr))
+			}
+			assert.Error(t, st.start(context.Background(), tt.host))
+		})
+	}
+}
@@ -30,6 +30,14 @@ type Config struct {
 	// have different sampling rates: if they use the same seed all passing one layer may pass the other even if they have
 	// different sampling rates, configuring different seeds avoids that.
 	HashSeed uint32 `mapstructure:"hash_seed"`
+
+	// FromAttribute (logs only) is the name of a log record attribute used for sampling log records that
+	// don't have a trace ID, eg.: a request ID. Log records with the same value are given the same decision.
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// SamplingPriority (logs only) is the name of a log record attribute that overrides the sampling decision:
+	// zero drops the log record and a value greater than zero keeps it, like the "sampling.priority" span tag.
+	SamplingPriority string `mapstructure:"sampling_priority"`
 }
 
 var _ config.Processor = (*Config)(nil)
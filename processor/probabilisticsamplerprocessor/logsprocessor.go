@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type logsamplerprocessor struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+	fromAttribute      string
+	samplingPriority   string
+}
+
+// newLogsProcessor returns a processor.LogsProcessor that will perform head sampling according to the given
+// configuration.
+func newLogsProcessor(ctx context.Context, set component.ProcessorCreateSettings, cfg *Config, nextConsumer consumer.Logs) (component.LogsProcessor, error) {
+	lsp := &logsamplerprocessor{
+		// Adjust sampling percentage on private so recalculations are avoided.
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           cfg.HashSeed,
+		fromAttribute:      cfg.FromAttribute,
+		samplingPriority:   cfg.SamplingPriority,
+	}
+
+	return processorhelper.NewLogsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		lsp.processLogs,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+}
+
+func (lsp *logsamplerprocessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(l plog.LogRecord) bool {
+				return !lsp.sampled(l)
+			})
+			// Filter out empty ScopeLogs
+			return sl.LogRecords().Len() == 0
+		})
+		// Filter out empty ResourceLogs
+		return rl.ScopeLogs().Len() == 0
+	})
+	if ld.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+func (lsp *logsamplerprocessor) sampled(l plog.LogRecord) bool {
+	if lsp.samplingPriority != "" {
+		switch parseSamplingPriority(l.Attributes(), lsp.samplingPriority) {
+		case mustSampleSpan:
+			return true
+		case doNotSampleSpan:
+			return false
+		}
+	}
+
+	// Log records are hashed on the trace ID, the same way spans are, so that the logs
+	// and the spans of a sampled trace are kept together when using the same seed.
+	if tid := l.TraceID(); !tid.IsEmpty() {
+		tidBytes := tid.Bytes()
+		return hash(tidBytes[:], lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+	}
+	if lsp.fromAttribute != "" {
+		if value, ok := l.Attributes().Get(lsp.fromAttribute); ok {
+			return hash([]byte(value.AsString()), lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+		}
+	}
+
+	// Otherwise the timestamp and the body of the log record are hashed, so that
+	// a given log record is sampled the same way by all the collectors of a tier.
+	return hash(logRecordKey(l), lsp.hashSeed)&bitMaskHashBuckets < lsp.scaledSamplingRate
+}
+
+// logRecordKey returns the bytes hashed to sample a log record that has
+// neither a trace ID nor the configured attribute.
+func logRecordKey(l plog.LogRecord) []byte {
+	ts := l.Timestamp()
+	if ts == 0 {
+		ts = l.ObservedTimestamp()
+	}
+	key := make([]byte, 8, 8+len(l.Body().AsString()))
+	binary.BigEndian.PutUint64(key, uint64(ts))
+	return append(key, l.Body().AsString()...)
+}
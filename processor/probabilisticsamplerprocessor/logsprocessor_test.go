@@ -0,0 +1,287 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/idutils"
+)
+
+func TestNewLogsProcessor(t *testing.T) {
+	tests := []struct {
+		name         string
+		nextConsumer consumer.Logs
+		cfg          *Config
+		wantErr      bool
+	}{
+		{
+			name: "nil_nextConsumer",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 15.5,
+			},
+			wantErr: true,
+		},
+		{
+			name:         "happy_path",
+			nextConsumer: consumertest.NewNop(),
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 15.5,
+			},
+		},
+		{
+			name:         "happy_path_attributes",
+			nextConsumer: consumertest.NewNop(),
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 13.33,
+				HashSeed:           4321,
+				FromAttribute:      "request.id",
+				SamplingPriority:   "priority",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.cfg, tt.nextConsumer)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func Test_logsamplerprocessor_SamplingPercentageRange(t *testing.T) {
+	tests := []struct {
+		name            string
+		cfg             *Config
+		newLogRecord    func(i int, l plog.LogRecord)
+		acceptableDelta float64
+	}{
+		{
+			name: "trace_id_sampling",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 25,
+			},
+			newLogRecord: func(i int, l plog.LogRecord) {
+				l.SetTraceID(idutils.UInt64ToTraceID(uint64(i), uint64(i)))
+			},
+			acceptableDelta: 1,
+		},
+		{
+			name: "attribute_sampling",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 25,
+				FromAttribute:      "request.id",
+			},
+			newLogRecord: func(i int, l plog.LogRecord) {
+				l.Attributes().InsertString("request.id", fmt.Sprintf("request-%d", i))
+			},
+			acceptableDelta: 1,
+		},
+		{
+			name: "timestamp_and_body_sampling",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 25,
+			},
+			newLogRecord: func(i int, l plog.LogRecord) {
+				l.SetTimestamp(pcommon.Timestamp(1660000000000000000 + i))
+				l.Body().SetStringVal(fmt.Sprintf("log record %d", i))
+			},
+			acceptableDelta: 1,
+		},
+		{
+			name: "sampling_all",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 100,
+			},
+			newLogRecord:    func(int, plog.LogRecord) {},
+			acceptableDelta: 0,
+		},
+	}
+	const numLogRecords = 1e5
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			lsp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.cfg, sink)
+			require.NoError(t, err)
+
+			ld := plog.NewLogs()
+			records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			for i := 0; i < numLogRecords; i++ {
+				tt.newLogRecord(i, records.AppendEmpty())
+			}
+			require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+			actualPercentage := float64(sink.LogRecordCount()) / numLogRecords * 100
+			assert.InDelta(t, tt.cfg.SamplingPercentage, actualPercentage, tt.acceptableDelta)
+		})
+	}
+}
+
+func Test_logsamplerprocessor_ConsistentWithTraces(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 50,
+		HashSeed:           22,
+	}
+	logsSink := new(consumertest.LogsSink)
+	lsp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, logsSink)
+	require.NoError(t, err)
+	tracesSink := new(consumertest.TracesSink)
+	tsp, err := newTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, tracesSink)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := 1; i <= 100; i++ {
+		traceID := idutils.UInt64ToTraceID(uint64(i), uint64(i))
+		records.AppendEmpty().SetTraceID(traceID)
+		spans.AppendEmpty().SetTraceID(traceID)
+	}
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), td))
+
+	var sampledLogs, sampledSpans []pcommon.TraceID
+	for _, ld := range logsSink.AllLogs() {
+		records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			sampledLogs = append(sampledLogs, records.At(i).TraceID())
+		}
+	}
+	for _, td := range tracesSink.AllTraces() {
+		spans := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		for i := 0; i < spans.Len(); i++ {
+			sampledSpans = append(sampledSpans, spans.At(i).TraceID())
+		}
+	}
+	assert.NotEmpty(t, sampledLogs)
+	assert.Equal(t, sampledSpans, sampledLogs, "Must sample the same traces for logs and spans")
+}
+
+func Test_logsamplerprocessor_Deterministic(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 50,
+		HashSeed:           22,
+	}
+	newLogs := func() plog.Logs {
+		ld := plog.NewLogs()
+		records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		for i := 0; i < 100; i++ {
+			l := records.AppendEmpty()
+			l.SetObservedTimestamp(pcommon.Timestamp(1660000000000000000 + i))
+			l.Body().SetStringVal(fmt.Sprintf("log record %d", i))
+		}
+		return ld
+	}
+
+	var sampled [][]string
+	for run := 0; run < 2; run++ {
+		sink := new(consumertest.LogsSink)
+		lsp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+		require.NoError(t, err)
+		require.NoError(t, lsp.ConsumeLogs(context.Background(), newLogs()))
+
+		var bodies []string
+		for _, ld := range sink.AllLogs() {
+			records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			for i := 0; i < records.Len(); i++ {
+				bodies = append(bodies, records.At(i).Body().AsString())
+			}
+		}
+		sampled = append(sampled, bodies)
+	}
+	assert.NotEmpty(t, sampled[0])
+	assert.Less(t, len(sampled[0]), 100)
+	assert.Equal(t, sampled[0], sampled[1], "Must sample the same log records on every run")
+}
+
+func Test_logsamplerprocessor_SamplingPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority pcommon.Value
+		sampled  bool
+	}{
+		{name: "must_sample_int", priority: pcommon.NewValueInt(2), sampled: true},
+		{name: "must_sample_string", priority: pcommon.NewValueString("1"), sampled: true},
+		{name: "must_not_sample_int", priority: pcommon.NewValueInt(0), sampled: false},
+		{name: "must_not_sample_double", priority: pcommon.NewValueDouble(0), sampled: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, percentage := range []float32{0, 100} {
+				cfg := &Config{
+					ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+					SamplingPercentage: percentage,
+					SamplingPriority:   "priority",
+				}
+				sink := new(consumertest.LogsSink)
+				lsp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+				require.NoError(t, err)
+
+				ld := plog.NewLogs()
+				l := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+				l.SetTraceID(idutils.UInt64ToTraceID(1, 1))
+				l.Attributes().Insert("priority", tt.priority)
+				require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+
+				if tt.sampled {
+					assert.Equal(t, 1, sink.LogRecordCount(), "sampling percentage %v", percentage)
+				} else {
+					assert.Equal(t, 0, sink.LogRecordCount(), "sampling percentage %v", percentage)
+				}
+			}
+		})
+	}
+}
+
+func Test_logsamplerprocessor_DropsEmptyLogs(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 0,
+	}
+	sink := new(consumertest.LogsSink)
+	lsp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().SetTraceID(idutils.UInt64ToTraceID(1, 1))
+	require.NoError(t, lsp.ConsumeLogs(context.Background(), ld))
+	assert.Empty(t, sink.AllLogs(), "Must not forward data without log records")
+}
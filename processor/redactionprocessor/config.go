@@ -15,6 +15,8 @@
 package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 )
 
@@ -35,6 +37,22 @@ type Config struct {
 	// allowed span attributes. Values that match are masked
 	BlockedValues []string `mapstructure:"blocked_values"`
 
+	// Detectors is a list of built-in detectors of sensitive values, applied
+	// to allowed attributes like the BlockedValues. Possible values are
+	// `credit_card`, `email`, `ipv4` and `ipv6`.
+	Detectors []string `mapstructure:"detectors"`
+
+	// Mode controls what happens to the values matching the BlockedValues or
+	// the Detectors. Possible values are `mask` (the default) to replace the
+	// matching part with asterisks, `hash` to replace it with its HMAC-SHA256
+	// keyed with HashKey, and `drop` to remove the whole attribute.
+	Mode string `mapstructure:"mode"`
+
+	// HashKey is the secret key of the HMAC-SHA256 replacing the matching
+	// values in the `hash` mode. It is required in that mode, since unkeyed
+	// hashes of low entropy values like card numbers are easily reversed.
+	HashKey string `mapstructure:"hash_key"`
+
 	// Summary controls the verbosity level of the diagnostic attributes that
 	// the processor adds to the spans when it redacts or masks other
 	// attributes. In some contexts a list of redacted attributes leaks
@@ -42,3 +60,23 @@ type Config struct {
 	// configuration. Possible values are `debug`, `info`, and `silent`.
 	Summary string `mapstructure:"summary"`
 }
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid
+func (cfg *Config) Validate() error {
+	switch cfg.Mode {
+	case "", maskMode, hashMode, dropMode:
+	default:
+		return fmt.Errorf("unknown mode %q, must be one of %q, %q or %q", cfg.Mode, maskMode, hashMode, dropMode)
+	}
+	if cfg.Mode == hashMode && cfg.HashKey == "" {
+		return fmt.Errorf("hash_key must be set in the %q mode", hashMode)
+	}
+	for _, name := range cfg.Detectors {
+		if _, ok := detectors[name]; !ok {
+			return fmt.Errorf("unknown detector %q", name)
+		}
+	}
+	return nil
+}
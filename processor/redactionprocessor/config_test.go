@@ -41,6 +41,16 @@ func TestLoadConfig(t *testing.T) {
 				Summary:           debug,
 			},
 		},
+		{
+			id: config.NewComponentIDWithName(typeStr, "detectors"),
+			expected: &Config{
+				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				AllowAllKeys:      true,
+				Detectors:         []string{creditCardDetector, emailDetector},
+				Mode:              hashMode,
+				HashKey:           "secret",
+			},
+		},
 		{
 			id:       config.NewComponentIDWithName(typeStr, "empty"),
 			expected: createDefaultConfig(),
@@ -64,3 +74,28 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{name: "default", cfg: &Config{}},
+		{name: "all_options", cfg: &Config{Mode: dropMode, Detectors: []string{creditCardDetector, emailDetector, ipv4Detector, ipv6Detector}}},
+		{name: "hash_mode", cfg: &Config{Mode: hashMode, HashKey: "secret"}},
+		{name: "unknown_mode", cfg: &Config{Mode: "encrypt"}, wantErr: true},
+		{name: "hash_mode_without_key", cfg: &Config{Mode: hashMode}, wantErr: true},
+		{name: "unknown_detector", cfg: &Config{Detectors: []string{"phone"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
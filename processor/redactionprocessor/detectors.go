@@ -0,0 +1,98 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/redactionprocessor"
+
+import (
+	"net"
+	"regexp"
+)
+
+const (
+	creditCardDetector = "credit_card"
+	emailDetector      = "email"
+	ipv4Detector       = "ipv4"
+	ipv6Detector       = "ipv6"
+)
+
+// valueMatcher finds the sensitive parts of attribute values
+type valueMatcher struct {
+	regex *regexp.Regexp
+	// valid optionally discards the matches of regex that are false positives
+	valid func(match string) bool
+}
+
+// replace returns the value with all of its matches replaced by the result of
+// repl, and whether anything was matched
+func (m *valueMatcher) replace(value string, repl func(match string) string) (string, bool) {
+	matched := false
+	replaced := m.regex.ReplaceAllStringFunc(value, func(match string) string {
+		if m.valid != nil && !m.valid(match) {
+			return match
+		}
+		matched = true
+		return repl(match)
+	})
+	return replaced, matched
+}
+
+// detectors are the built-in matchers that can be enabled by name, in
+// addition to the blocked values configured as regular expressions
+var detectors = map[string]*valueMatcher{
+	// Card numbers have 13 to 19 digits, optionally grouped with spaces or
+	// dashes, and must pass the Luhn checksum
+	creditCardDetector: {
+		regex: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: luhnValid,
+	},
+	emailDetector: {
+		regex: regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`),
+	},
+	ipv4Detector: {
+		regex: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\.){3}(?:25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9]?[0-9])\b`),
+	},
+	// The regular expression only finds candidates, which are then parsed to
+	// discard values like timestamps
+	ipv6Detector: {
+		regex: regexp.MustCompile(`(?:[0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}`),
+		valid: func(match string) bool {
+			ip := net.ParseIP(match)
+			return ip != nil && ip.To4() == nil
+		},
+	},
+}
+
+// luhnValid checks the digits of the card number against the Luhn checksum,
+// see https://en.wikipedia.org/wiki/Luhn_algorithm
+func luhnValid(number string) bool {
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			// Skip the separators between groups of digits
+			continue
+		}
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
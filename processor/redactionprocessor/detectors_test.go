@@ -0,0 +1,57 @@
+// Copyright  OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLuhnValid(t *testing.T) {
+	assert.True(t, luhnValid("4111111111111111"))
+	assert.True(t, luhnValid("4111-1111-1111-1111"))
+	assert.True(t, luhnValid("5500 0000 0000 0004"))
+	assert.False(t, luhnValid("4111111111111112"))
+	assert.False(t, luhnValid("1234567890123"))
+}
+
+// TestDetectors validates the matches of the built-in detectors
+func TestDetectors(t *testing.T) {
+	tests := []struct {
+		detector string
+		value    string
+		expected string
+	}{
+		{detector: creditCardDetector, value: "card 4111111111111111", expected: "card ****"},
+		{detector: creditCardDetector, value: "card 4111 1111 1111 1111 used", expected: "card **** used"},
+		{detector: creditCardDetector, value: "order 4111111111111112", expected: "order 4111111111111112"},
+		{detector: creditCardDetector, value: "phone 555-0100", expected: "phone 555-0100"},
+		{detector: emailDetector, value: "sent to jane.doe+test@example.co.uk", expected: "sent to ****"},
+		{detector: emailDetector, value: "user@localhost", expected: "user@localhost"},
+		{detector: ipv4Detector, value: "from 192.168.0.1:8080", expected: "from ****:8080"},
+		{detector: ipv4Detector, value: "version 1.2.300.4", expected: "version 1.2.300.4"},
+		{detector: ipv6Detector, value: "from 2001:db8::ff00:42:8329", expected: "from ****"},
+		{detector: ipv6Detector, value: "from ::1", expected: "from ****"},
+		{detector: ipv6Detector, value: "at 10:30:00", expected: "at 10:30:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.detector+" "+tt.value, func(t *testing.T) {
+			replaced, matched := detectors[tt.detector].replace(tt.value, func(string) string { return "****" })
+			assert.Equal(t, tt.expected, replaced)
+			assert.Equal(t, tt.value != tt.expected, matched)
+		})
+	}
+}
@@ -16,6 +16,9 @@ package redactionprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"regexp"
 	"sort"
@@ -24,44 +27,42 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 )
 
-var _ component.TracesProcessor = (*redaction)(nil)
-
 type redaction struct {
 	// Attribute keys allowed in a span
 	allowList map[string]string
-	// Attribute values blocked in a span
-	blockRegexList map[string]*regexp.Regexp
+	// Attribute values blocked in a span, by regular expression or detector,
+	// applied in the configured order
+	blockList []*valueMatcher
 	// Redaction processor configuration
 	config *Config
 	// Logger
 	logger *zap.Logger
-	// Next trace consumer in line
-	next consumer.Traces
 }
 
 // newRedaction creates a new instance of the redaction processor
-func newRedaction(ctx context.Context, config *Config, logger *zap.Logger, next consumer.Traces) (*redaction, error) {
+func newRedaction(ctx context.Context, config *Config, logger *zap.Logger) (*redaction, error) {
 	allowList := makeAllowList(config)
-	blockRegexList, err := makeBlockRegexList(ctx, config)
+	blockList, err := makeBlockList(ctx, config)
 	if err != nil {
 		// TODO: Placeholder for an error metric in the next PR
 		return nil, fmt.Errorf("failed to process block list: %w", err)
 	}
 
 	return &redaction{
-		allowList:      allowList,
-		blockRegexList: blockRegexList,
-		config:         config,
-		logger:         logger,
-		next:           next,
+		allowList: allowList,
+		blockList: blockList,
+		config:    config,
+		logger:    logger,
 	}, nil
 }
 
-// processTraces implements ProcessMetricsFunc. It processes the incoming data
+// processTraces implements ProcessTracesFunc. It processes the incoming data
 // and returns the data to be sent to the next component
 func (s *redaction) processTraces(ctx context.Context, batch ptrace.Traces) (ptrace.Traces, error) {
 	for i := 0; i < batch.ResourceSpans().Len(); i++ {
@@ -71,6 +72,26 @@ func (s *redaction) processTraces(ctx context.Context, batch ptrace.Traces) (ptr
 	return batch, nil
 }
 
+// processLogs implements ProcessLogsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processLogs(ctx context.Context, logs plog.Logs) (plog.Logs, error) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		s.processResourceLog(ctx, rl)
+	}
+	return logs, nil
+}
+
+// processMetrics implements ProcessMetricsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processMetrics(ctx context.Context, metrics pmetric.Metrics) (pmetric.Metrics, error) {
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		rm := metrics.ResourceMetrics().At(i)
+		s.processResourceMetric(ctx, rm)
+	}
+	return metrics, nil
+}
+
 // processResourceSpan processes the RS and all of its spans and then returns the last
 // view metric context. The context can be used for tests
 func (s *redaction) processResourceSpan(ctx context.Context, rs ptrace.ResourceSpans) {
@@ -91,12 +112,98 @@ func (s *redaction) processResourceSpan(ctx context.Context, rs ptrace.ResourceS
 	}
 }
 
-// processAttrs redacts the attributes of a resource span or a span
+// processResourceLog processes the resource and all of its log records,
+// including their bodies
+func (s *redaction) processResourceLog(ctx context.Context, rl plog.ResourceLogs) {
+	rlAttrs := rl.Resource().Attributes()
+	s.processAttrs(ctx, &rlAttrs)
+
+	for j := 0; j < rl.ScopeLogs().Len(); j++ {
+		sl := rl.ScopeLogs().At(j)
+		for k := 0; k < sl.LogRecords().Len(); k++ {
+			logRecord := sl.LogRecords().At(k)
+			logAttrs := logRecord.Attributes()
+
+			toDelete, toBlock := s.redactAttrs(&logAttrs)
+			// The body is not subject to the allowed keys, only its values
+			// are checked against the block list
+			switch s.processBody(logRecord.Body()) {
+			case bodyMasked:
+				toBlock = append(toBlock, bodyKey)
+			case bodyDropped:
+				toDelete = append(toDelete, bodyKey)
+			}
+			s.summarizeRedacted(toDelete, &logAttrs)
+			s.summarizeMasked(toBlock, &logAttrs)
+		}
+	}
+}
+
+// processResourceMetric processes the resource and the attributes of all the
+// data points of its metrics. No summary is added to metrics, as new attributes
+// would change the identity of their time series
+func (s *redaction) processResourceMetric(_ context.Context, rm pmetric.ResourceMetrics) {
+	rmAttrs := rm.Resource().Attributes()
+	s.redactAttrs(&rmAttrs)
+
+	for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+		sm := rm.ScopeMetrics().At(j)
+		for k := 0; k < sm.Metrics().Len(); k++ {
+			s.processMetric(sm.Metrics().At(k))
+		}
+	}
+}
+
+// processMetric redacts the attributes of the data points of a metric
+func (s *redaction) processMetric(metric pmetric.Metric) {
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs := dps.At(i).Attributes()
+			s.redactAttrs(&attrs)
+		}
+	case pmetric.MetricDataTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs := dps.At(i).Attributes()
+			s.redactAttrs(&attrs)
+		}
+	case pmetric.MetricDataTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs := dps.At(i).Attributes()
+			s.redactAttrs(&attrs)
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs := dps.At(i).Attributes()
+			s.redactAttrs(&attrs)
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attrs := dps.At(i).Attributes()
+			s.redactAttrs(&attrs)
+		}
+	}
+}
+
+// processAttrs redacts the attributes of a resource or a span, and adds the
+// summary of the changes to them
 func (s *redaction) processAttrs(_ context.Context, attributes *pcommon.Map) {
 	// TODO: Use the context for recording metrics
-	var toDelete []string
-	var toBlock []string
+	toDelete, toBlock := s.redactAttrs(attributes)
+
+	// Add diagnostic information to the attributes
+	s.summarizeRedacted(toDelete, attributes)
+	s.summarizeMasked(toBlock, attributes)
+}
 
+// redactAttrs redacts the attributes and returns the keys of the deleted and
+// masked attributes
+func (s *redaction) redactAttrs(attributes *pcommon.Map) (toDelete []string, toBlock []string) {
 	// Identify attributes to redact and mask in the following sequence
 	// 1. Make a list of attribute keys to redact
 	// 2. Mask any blocked values for the other attributes
@@ -115,14 +222,25 @@ func (s *redaction) processAttrs(_ context.Context, attributes *pcommon.Map) {
 			}
 		}
 
+		// Only string values are checked against the block list
+		if value.Type() != pcommon.ValueTypeString {
+			return true
+		}
+
 		// Mask any blocked values for the other attributes
+		if s.config.Mode == dropMode {
+			if s.matchValue(value.StringVal()) {
+				toDelete = append(toDelete, k)
+			}
+			return true
+		}
 		strVal := value.StringVal()
-		for _, compiledRE := range s.blockRegexList {
-			match := compiledRE.MatchString(strVal)
-			if match {
+		for _, matcher := range s.blockList {
+			maskedValue, matched := matcher.replace(strVal, s.mask)
+			if matched {
 				toBlock = append(toBlock, k)
 
-				maskedValue := compiledRE.ReplaceAllString(strVal, "****")
+				strVal = maskedValue
 				value.SetStringVal(maskedValue)
 			}
 		}
@@ -133,57 +251,138 @@ func (s *redaction) processAttrs(_ context.Context, attributes *pcommon.Map) {
 	for _, k := range toDelete {
 		attributes.Remove(k)
 	}
-	// Add diagnostic information to the span
-	s.summarizeRedactedSpan(toDelete, attributes)
-	s.summarizeMaskedSpan(toBlock, attributes)
+	return toDelete, toBlock
 }
 
-// ConsumeTraces implements the SpanProcessor interface
-func (s *redaction) ConsumeTraces(ctx context.Context, batch ptrace.Traces) error {
-	batch, err := s.processTraces(ctx, batch)
-	if err != nil {
-		return err
+type bodyResult int
+
+const (
+	bodyUnchanged bodyResult = iota
+	bodyMasked
+	bodyDropped
+)
+
+// processBody checks the values of a log record body against the block list.
+// String bodies are checked as a whole, while only the top level string
+// values of map bodies are checked
+func (s *redaction) processBody(body pcommon.Value) bodyResult {
+	if s.config.Mode == dropMode {
+		return s.dropBody(body)
 	}
 
-	err = s.next.ConsumeTraces(ctx, batch)
-	return err
+	var values []pcommon.Value
+	switch body.Type() {
+	case pcommon.ValueTypeString:
+		values = append(values, body)
+	case pcommon.ValueTypeMap:
+		body.MapVal().Range(func(_ string, v pcommon.Value) bool {
+			if v.Type() == pcommon.ValueTypeString {
+				values = append(values, v)
+			}
+			return true
+		})
+	}
+
+	result := bodyUnchanged
+	for _, value := range values {
+		strVal := value.StringVal()
+		for _, matcher := range s.blockList {
+			maskedValue, matched := matcher.replace(strVal, s.mask)
+			if matched {
+				result = bodyMasked
+
+				strVal = maskedValue
+				value.SetStringVal(maskedValue)
+			}
+		}
+	}
+	return result
 }
 
-// summarizeRedactedSpan adds diagnostic information about redacted attribute keys
-func (s *redaction) summarizeRedactedSpan(toDelete []string, attributes *pcommon.Map) {
-	redactedSpanCount := int64(len(toDelete))
-	if redactedSpanCount == 0 {
+// dropBody removes a string body matching the block list, or the top level
+// string values of a map body that match it
+func (s *redaction) dropBody(body pcommon.Value) bodyResult {
+	switch body.Type() {
+	case pcommon.ValueTypeString:
+		if s.matchValue(body.StringVal()) {
+			pcommon.NewValueEmpty().CopyTo(body)
+			return bodyDropped
+		}
+	case pcommon.ValueTypeMap:
+		result := bodyUnchanged
+		body.MapVal().RemoveIf(func(_ string, v pcommon.Value) bool {
+			if v.Type() == pcommon.ValueTypeString && s.matchValue(v.StringVal()) {
+				result = bodyDropped
+				return true
+			}
+			return false
+		})
+		return result
+	}
+	return bodyUnchanged
+}
+
+// matchValue reports whether the value matches any of the block list
+func (s *redaction) matchValue(value string) bool {
+	for _, matcher := range s.blockList {
+		if _, matched := matcher.replace(value, func(match string) string { return match }); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// mask returns the replacement of a blocked value for the configured mode
+func (s *redaction) mask(match string) string {
+	if s.config.Mode == hashMode {
+		mac := hmac.New(sha256.New, []byte(s.config.HashKey))
+		mac.Write([]byte(match))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	return "****"
+}
+
+// summarizeRedacted adds diagnostic information about redacted attribute keys
+// to the attributes of a span, a resource or a log record
+func (s *redaction) summarizeRedacted(toDelete []string, attributes *pcommon.Map) {
+	redactedCount := int64(len(toDelete))
+	if redactedCount == 0 {
 		return
 	}
-	// Record summary as span attributes
+	// Record summary as attributes
 	if s.config.Summary == debug {
 		sort.Strings(toDelete)
 		attributes.InsertString(redactedKeys, strings.Join(toDelete, ","))
 	}
 	if s.config.Summary == info || s.config.Summary == debug {
-		attributes.InsertInt(redactedKeyCount, redactedSpanCount)
+		attributes.InsertInt(redactedKeyCount, redactedCount)
 	}
 }
 
-// summarizeMaskedSpan adds diagnostic information about masked attribute values
-func (s *redaction) summarizeMaskedSpan(toBlock []string, attributes *pcommon.Map) {
-	maskedSpanCount := int64(len(toBlock))
-	if maskedSpanCount == 0 {
+// summarizeMasked adds diagnostic information about masked attribute values
+// to the attributes of a span, a resource or a log record
+func (s *redaction) summarizeMasked(toBlock []string, attributes *pcommon.Map) {
+	maskedCount := int64(len(toBlock))
+	if maskedCount == 0 {
 		return
 	}
-	// Records summary as span attributes
+	// Record summary as attributes
 	if s.config.Summary == debug {
 		sort.Strings(toBlock)
 		attributes.InsertString(maskedValues, strings.Join(toBlock, ","))
 	}
 	if s.config.Summary == info || s.config.Summary == debug {
-		attributes.InsertInt(maskedValueCount, maskedSpanCount)
+		attributes.InsertInt(maskedValueCount, maskedCount)
 	}
 }
 
 const (
 	debug            = "debug"
 	info             = "info"
+	maskMode         = "mask"
+	hashMode         = "hash"
+	dropMode         = "drop"
+	bodyKey          = "body"
 	redactedKeys     = "redaction.redacted.keys"
 	redactedKeyCount = "redaction.redacted.count"
 	maskedValues     = "redaction.masked.keys"
@@ -216,18 +415,36 @@ func makeAllowList(c *Config) map[string]string {
 	return allowList
 }
 
-// makeBlockRegexList precompiles all the blocked regex patterns
-func makeBlockRegexList(_ context.Context, config *Config) (map[string]*regexp.Regexp, error) {
-	blockRegexList := make(map[string]*regexp.Regexp, len(config.BlockedValues))
+// makeBlockList precompiles all the blocked regex patterns and adds the
+// enabled detectors, keeping the order of the configuration so that values
+// are always matched the same way
+func makeBlockList(_ context.Context, config *Config) ([]*valueMatcher, error) {
+	blockList := make([]*valueMatcher, 0, len(config.BlockedValues)+len(config.Detectors))
+	seen := make(map[string]struct{}, len(config.BlockedValues)+len(config.Detectors))
 	for _, pattern := range config.BlockedValues {
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			// TODO: Placeholder for an error metric in the next PR
 			return nil, fmt.Errorf("error compiling regex in block list: %w", err)
 		}
-		blockRegexList[pattern] = re
+		if _, ok := seen[pattern]; ok {
+			continue
+		}
+		seen[pattern] = struct{}{}
+		blockList = append(blockList, &valueMatcher{regex: re})
+	}
+	for _, name := range config.Detectors {
+		detector, ok := detectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector in block list: %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		blockList = append(blockList, detector)
 	}
-	return blockRegexList, nil
+	return blockList, nil
 }
 
 // Capabilities specifies what this processor does, such as whether it mutates data
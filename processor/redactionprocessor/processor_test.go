@@ -21,17 +21,19 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap/zaptest"
 )
 
 func TestCapabilities(t *testing.T) {
 	config := &Config{}
-	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
 	assert.NoError(t, err)
 
 	cap := processor.Capabilities()
@@ -40,8 +42,7 @@ func TestCapabilities(t *testing.T) {
 
 func TestStartShutdown(t *testing.T) {
 	config := &Config{}
-	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -144,8 +145,6 @@ func TestAllowAllKeysMaskValues(t *testing.T) {
 	assert.Equal(t, "placeholder ****", value.StringVal())
 }
 
-// TODO: Test redaction with metric tags in a metrics PR
-
 // TestRedactSummaryDebug validates that the processor writes a verbose summary
 // of any attributes it deleted to the new redaction.redacted.keys and
 // redaction.redacted.count span attributes while set to full debug output
@@ -350,6 +349,352 @@ func TestMultipleBlockValues(t *testing.T) {
 	assert.Equal(t, "mystery ****", mysteryValue.StringVal())
 }
 
+// TestDetectorsMaskValues validates that the processor masks the values found
+// by the enabled detectors
+func TestDetectorsMaskValues(t *testing.T) {
+	config := &Config{
+		AllowAllKeys: true,
+		Detectors:    []string{creditCardDetector, emailDetector, ipv4Detector},
+		Summary:      "debug",
+	}
+	allowed := map[string]pcommon.Value{
+		"id":    pcommon.NewValueInt(5),
+		"order": pcommon.NewValueString("order 4111111111111112"),
+	}
+	masked := map[string]pcommon.Value{
+		"card":    pcommon.NewValueString("card 4111 1111 1111 1111"),
+		"contact": pcommon.NewValueString("jane@example.com from 10.0.0.1"),
+	}
+
+	_, _, next := runTest(t, allowed, nil, masked, config)
+
+	attr := next.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	card, _ := attr.Get("card")
+	assert.Equal(t, "card ****", card.StringVal())
+	contact, _ := attr.Get("contact")
+	assert.Equal(t, "**** from ****", contact.StringVal())
+	order, _ := attr.Get("order")
+	assert.Equal(t, "order 4111111111111112", order.StringVal())
+	maskedKeys, ok := attr.Get(maskedValues)
+	assert.True(t, ok)
+	assert.Equal(t, "card,contact,contact", maskedKeys.StringVal())
+}
+
+// TestHashMode validates that the processor replaces the blocked values with
+// their hash
+func TestHashMode(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Mode:          hashMode,
+		HashKey:       "secret",
+	}
+	masked := map[string]pcommon.Value{
+		"name": pcommon.NewValueString("placeholder 4111111111111111"),
+	}
+
+	_, _, next := runTest(t, nil, nil, masked, config)
+
+	attr := next.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	value, _ := attr.Get("name")
+	assert.Equal(t, "placeholder d6c005134ac50dec0e01cbc4aeaf3fbdb511c4ceeb55f909c29def3b0cffba36", value.StringVal())
+
+	config.HashKey = "other"
+	_, _, next = runTest(t, nil, nil, masked, config)
+	attr = next.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	value, _ = attr.Get("name")
+	assert.NotEqual(t, "placeholder d6c005134ac50dec0e01cbc4aeaf3fbdb511c4ceeb55f909c29def3b0cffba36", value.StringVal(), "Must depend on the key")
+}
+
+// TestBlockListOrder validates that the matchers are applied in the
+// configured order, so that overlapping matches are always masked the same way
+func TestBlockListOrder(t *testing.T) {
+	config := &Config{
+		AllowAllKeys:  true,
+		BlockedValues: []string{"secret-[0-9]+", "[0-9]+", "secret"},
+		Detectors:     []string{emailDetector, emailDetector},
+		Mode:          hashMode,
+		HashKey:       "secret",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	require.Len(t, processor.blockList, 4, "Must ignore duplicated matchers")
+
+	var expected string
+	for i := 0; i < 20; i++ {
+		attrs := pcommon.NewMap()
+		attrs.InsertString("value", "secret-123")
+		processor.processAttrs(context.Background(), &attrs)
+		value, _ := attrs.Get("value")
+		if i == 0 {
+			expected = value.StringVal()
+			continue
+		}
+		require.Equal(t, expected, value.StringVal(), "Must mask the value the same way every time")
+	}
+}
+
+// TestDropMode validates that the processor removes the attributes with
+// blocked values
+func TestDropMode(t *testing.T) {
+	config := &Config{
+		AllowedKeys: []string{"id", "name"},
+		Detectors:   []string{creditCardDetector},
+		Mode:        dropMode,
+		Summary:     "debug",
+	}
+	allowed := map[string]pcommon.Value{
+		"id": pcommon.NewValueInt(5),
+	}
+	redacted := map[string]pcommon.Value{
+		"name":        pcommon.NewValueString("placeholder 4111111111111111"),
+		"credit_card": pcommon.NewValueString("4111111111111111"),
+	}
+
+	_, _, next := runTest(t, allowed, redacted, nil, config)
+
+	attr := next.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	_, ok := attr.Get("name")
+	assert.False(t, ok)
+	_, ok = attr.Get("credit_card")
+	assert.False(t, ok)
+	_, ok = attr.Get("id")
+	assert.True(t, ok)
+	redactedKeys, ok := attr.Get(redactedKeys)
+	assert.True(t, ok)
+	assert.Equal(t, "credit_card,name", redactedKeys.StringVal())
+	_, ok = attr.Get(maskedValues)
+	assert.False(t, ok)
+}
+
+// TestBlockedValuesOnlyMatchStrings validates that values which aren't
+// strings are left unchanged, even by patterns matching the empty string
+func TestBlockedValuesOnlyMatchStrings(t *testing.T) {
+	for _, mode := range []string{maskMode, dropMode} {
+		t.Run(mode, func(t *testing.T) {
+			config := &Config{
+				AllowAllKeys:  true,
+				BlockedValues: []string{".*"},
+				Mode:          mode,
+				HashKey:       "secret",
+			}
+			processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+			require.NoError(t, err)
+
+			attrs := pcommon.NewMap()
+			attrs.InsertInt("int", 5)
+			attrs.InsertBool("bool", true)
+			attrs.InsertDouble("double", 1.5)
+			attrs.Insert("map", pcommon.NewValueMap())
+			attrs.Insert("slice", pcommon.NewValueSlice())
+			attrs.InsertString("string", "value")
+
+			toDelete, toBlock := processor.redactAttrs(&attrs)
+
+			for _, k := range []string{"int", "bool", "double", "map", "slice"} {
+				value, ok := attrs.Get(k)
+				require.True(t, ok, k)
+				assert.NotEqual(t, pcommon.ValueTypeString, value.Type(), k)
+			}
+			if mode == dropMode {
+				assert.Equal(t, []string{"string"}, toDelete)
+				_, ok := attrs.Get("string")
+				assert.False(t, ok)
+			} else {
+				assert.Equal(t, []string{"string"}, toBlock)
+			}
+		})
+	}
+}
+
+// TestProcessLogs validates that the processor redacts the attributes and
+// masks the bodies of log records
+func TestProcessLogs(t *testing.T) {
+	config := &Config{
+		AllowedKeys: []string{"id", "name"},
+		Detectors:   []string{emailDetector},
+		Summary:     "debug",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("host", "server-0")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+
+	first := records.AppendEmpty()
+	first.Body().SetStringVal("password reset for jane@example.com")
+	first.Attributes().InsertInt("id", 5)
+	first.Attributes().InsertString("name", "jane@example.com")
+	first.Attributes().InsertString("password", "hunter2")
+
+	second := records.AppendEmpty()
+	pcommon.NewValueMap().CopyTo(second.Body())
+	second.Body().MapVal().InsertString("user", "jane@example.com")
+	second.Body().MapVal().InsertInt("attempts", 3)
+
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	outRL := out.ResourceLogs().At(0)
+	_, ok := outRL.Resource().Attributes().Get("host")
+	assert.False(t, ok)
+
+	outFirst := outRL.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, "password reset for ****", outFirst.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{
+		"id":             int64(5),
+		"name":           "****",
+		redactedKeys:     "password",
+		redactedKeyCount: int64(1),
+		maskedValues:     "body,name",
+		maskedValueCount: int64(2),
+	}, outFirst.Attributes().AsRaw())
+
+	outSecond := outRL.ScopeLogs().At(0).LogRecords().At(1)
+	assert.Equal(t, map[string]interface{}{
+		"user":     "****",
+		"attempts": int64(3),
+	}, outSecond.Body().MapVal().AsRaw())
+}
+
+// TestProcessLogsDropMode validates that the processor removes the bodies of
+// log records with blocked values
+func TestProcessLogsDropMode(t *testing.T) {
+	config := &Config{
+		AllowAllKeys: true,
+		Detectors:    []string{emailDetector},
+		Mode:         dropMode,
+		Summary:      "info",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStringVal("password reset for jane@example.com")
+
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	outRecord := out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, pcommon.ValueTypeEmpty, outRecord.Body().Type())
+	assert.Equal(t, map[string]interface{}{redactedKeyCount: int64(1)}, outRecord.Attributes().AsRaw())
+}
+
+// TestProcessLogsDropModeMapBody validates that the processor only removes
+// the blocked values of map bodies
+func TestProcessLogsDropModeMapBody(t *testing.T) {
+	config := &Config{
+		AllowAllKeys: true,
+		Detectors:    []string{emailDetector},
+		Mode:         dropMode,
+		Summary:      "debug",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	matching := records.AppendEmpty()
+	pcommon.NewValueMap().CopyTo(matching.Body())
+	matching.Body().MapVal().InsertString("user", "jane@example.com")
+	matching.Body().MapVal().InsertString("action", "password reset")
+	matching.Body().MapVal().InsertInt("attempts", 3)
+	clean := records.AppendEmpty()
+	pcommon.NewValueMap().CopyTo(clean.Body())
+	clean.Body().MapVal().InsertString("action", "login")
+
+	out, err := processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	outRecords := out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, map[string]interface{}{
+		"action":   "password reset",
+		"attempts": int64(3),
+	}, outRecords.At(0).Body().MapVal().AsRaw())
+	assert.Equal(t, map[string]interface{}{
+		redactedKeys:     bodyKey,
+		redactedKeyCount: int64(1),
+	}, outRecords.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{"action": "login"}, outRecords.At(1).Body().MapVal().AsRaw())
+	assert.Empty(t, outRecords.At(1).Attributes().AsRaw())
+}
+
+// TestProcessMetrics validates that the processor redacts the attributes of
+// the data points of all the metric types
+func TestProcessMetrics(t *testing.T) {
+	config := &Config{
+		AllowedKeys: []string{"client"},
+		Detectors:   []string{ipv4Detector},
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("host", "server-0")
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	var attrs []pcommon.Map
+	gauge := ms.AppendEmpty()
+	gauge.SetDataType(pmetric.MetricDataTypeGauge)
+	attrs = append(attrs, gauge.Gauge().DataPoints().AppendEmpty().Attributes())
+	sum := ms.AppendEmpty()
+	sum.SetDataType(pmetric.MetricDataTypeSum)
+	attrs = append(attrs, sum.Sum().DataPoints().AppendEmpty().Attributes())
+	histogram := ms.AppendEmpty()
+	histogram.SetDataType(pmetric.MetricDataTypeHistogram)
+	attrs = append(attrs, histogram.Histogram().DataPoints().AppendEmpty().Attributes())
+	expHistogram := ms.AppendEmpty()
+	expHistogram.SetDataType(pmetric.MetricDataTypeExponentialHistogram)
+	attrs = append(attrs, expHistogram.ExponentialHistogram().DataPoints().AppendEmpty().Attributes())
+	summary := ms.AppendEmpty()
+	summary.SetDataType(pmetric.MetricDataTypeSummary)
+	attrs = append(attrs, summary.Summary().DataPoints().AppendEmpty().Attributes())
+	for _, attr := range attrs {
+		attr.InsertString("client", "192.168.0.1")
+		attr.InsertString("user", "jane")
+	}
+
+	out, err := processor.processMetrics(context.Background(), metrics)
+	require.NoError(t, err)
+
+	_, ok := out.ResourceMetrics().At(0).Resource().Attributes().Get("host")
+	assert.False(t, ok)
+	for _, attr := range attrs {
+		assert.Equal(t, map[string]interface{}{"client": "****"}, attr.AsRaw())
+	}
+}
+
+// TestProcessMetricsNoSummary validates that no summary is added to metrics,
+// which would change the identity of their time series
+func TestProcessMetricsNoSummary(t *testing.T) {
+	config := &Config{
+		AllowedKeys: []string{"client"},
+		Detectors:   []string{ipv4Detector},
+		Summary:     debug,
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().InsertString("host", "server-0")
+	gauge := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	gauge.SetDataType(pmetric.MetricDataTypeGauge)
+	attr := gauge.Gauge().DataPoints().AppendEmpty().Attributes()
+	attr.InsertString("client", "192.168.0.1")
+	attr.InsertString("user", "jane")
+
+	out, err := processor.processMetrics(context.Background(), metrics)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{}, out.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	assert.Equal(t, map[string]interface{}{"client": "****"}, attr.AsRaw())
+}
+
 // runTest transforms the test input data and passes it through the processor
 func runTest(
 	t *testing.T,
@@ -386,9 +731,11 @@ func runTest(
 	// test
 	ctx := context.Background()
 	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(ctx, config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(ctx, config, zaptest.NewLogger(t))
+	assert.NoError(t, err)
+	outBatch, err := processor.processTraces(ctx, inBatch)
 	assert.NoError(t, err)
-	err = processor.ConsumeTraces(ctx, inBatch)
+	err = next.ConsumeTraces(ctx, outBatch)
 
 	// verify
 	assert.NoError(t, err)
@@ -417,8 +764,7 @@ func BenchmarkRedactSummaryDebug(b *testing.B) {
 		"credit_card": pcommon.NewValueString("would be nice"),
 	}
 	ctx := context.Background()
-	next := new(consumertest.TracesSink)
-	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b), next)
+	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b))
 
 	for i := 0; i < b.N; i++ {
 		runBenchmark(allowed, redacted, masked, processor)
@@ -444,8 +790,7 @@ func BenchmarkMaskSummaryDebug(b *testing.B) {
 		"url":  pcommon.NewValueString("https://www.this_is_testing_url.com"),
 	}
 	ctx := context.Background()
-	next := new(consumertest.TracesSink)
-	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b), next)
+	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b))
 
 	for i := 0; i < b.N; i++ {
 		runBenchmark(allowed, nil, masked, processor)
@@ -479,5 +824,5 @@ func runBenchmark(
 		v.CopyTo(span.Attributes().UpsertEmpty(k))
 	}
 
-	_ = processor.ConsumeTraces(context.Background(), inBatch)
+	_, _ = processor.processTraces(context.Background(), inBatch)
 }
@@ -26,6 +26,11 @@ var (
 	errNoExporters            = errors.New("no exporters defined for the route")
 	errNoTableItems           = errors.New("the routing table is empty")
 	errNoMissingFromAttribute = errors.New("the FromAttribute property is empty")
+	errValueAndCondition      = errors.New("value and condition can't both be set for the same route")
+	errMixedRoutesInContext   = errors.New("routes with a condition can only be combined with routes with a value when attribute_source is 'resource'")
+	errInvalidGranularity     = errors.New("the granularity must be either 'resource' or 'record'")
+	errRecordFromContext      = errors.New("routing records on a value requires attribute_source to be 'resource'")
+	errRecordDropAttribute    = errors.New("drop_resource_routing_attribute can't be used when routing records")
 )
 
 // Config defines configuration for the Routing processor.
@@ -49,7 +54,7 @@ type Config struct {
 	// this could be the HTTP/gRPC header from the original request/RPC. Typically, aggregation processors (batch, groupbytrace)
 	// will create a new context, so, those should be avoided when using this processor.Although the HTTP spec allows headers to be repeated,
 	// this processor will only use the first value.
-	// Required.
+	// Required unless all the routes of the table use a condition.
 	FromAttribute string `mapstructure:"from_attribute"`
 
 	// DropRoutingResourceAttribute controls whether to remove the resource attribute used for routing.
@@ -57,6 +62,17 @@ type Config struct {
 	// Optional.
 	DropRoutingResourceAttribute bool `mapstructure:"drop_resource_routing_attribute"`
 
+	// Granularity defines what is routed as a whole. The allowed values are:
+	// - "resource" - the resource and all its telemetry is sent to the matching routes
+	// - "record" - every span, log record or metric data point is sent to the
+	//   matching routes on its own, along with a copy of its resource and scope.
+	//   Values are looked up in the attributes of the record first, and then in
+	//   the attributes of its resource. Conditions are evaluated using the traces,
+	//   logs or metrics TQL context of the record.
+	// The default value is "resource".
+	// Optional.
+	Granularity Granularity `mapstructure:"granularity"`
+
 	// Table contains the routing table for this processor.
 	// Required.
 	Table []RoutingTableItem `mapstructure:"table"`
@@ -64,9 +80,31 @@ type Config struct {
 
 // Validate checks if the processor configuration is valid.
 func (c *Config) Validate() error {
-	// validate that every route has a value for the routing attribute and has
-	// at least one exporter
+	if c.Granularity != "" && c.Granularity != resourceGranularity && c.Granularity != recordGranularity {
+		return errInvalidGranularity
+	}
+
+	// validate that every route has either a value for the routing attribute
+	// or a condition, and has at least one exporter
+	hasValues := false
 	for _, item := range c.Table {
+		if len(item.Value) != 0 && len(item.Condition) != 0 {
+			return fmt.Errorf("invalid route %s: %w", item.Value, errValueAndCondition)
+		}
+
+		if len(item.Condition) != 0 {
+			if len(item.Exporters) == 0 {
+				return fmt.Errorf("invalid route %s: %w", item.Condition, errNoExporters)
+			}
+			// Record conditions depend on the signal, they are parsed when the processor starts.
+			if c.Granularity != recordGranularity {
+				if _, err := parseResourceCondition(item.Condition); err != nil {
+					return fmt.Errorf("invalid route condition %q: %w", item.Condition, err)
+				}
+			}
+			continue
+		}
+
 		if len(item.Value) == 0 {
 			return fmt.Errorf("invalid (empty) route : %w", errEmptyRoute)
 		}
@@ -74,6 +112,7 @@ func (c *Config) Validate() error {
 		if len(item.Exporters) == 0 {
 			return fmt.Errorf("invalid route %s: %w", item.Value, errNoExporters)
 		}
+		hasValues = true
 	}
 
 	// validate that there's at least one item in the table
@@ -81,8 +120,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid routing table: %w", errNoTableItems)
 	}
 
-	// we also need a "FromAttribute" value
-	if len(c.FromAttribute) == 0 {
+	if c.Granularity == recordGranularity {
+		if hasValues && c.AttributeSource != resourceAttributeSource {
+			return errRecordFromContext
+		}
+		if c.DropRoutingResourceAttribute {
+			return errRecordDropAttribute
+		}
+	}
+
+	if hasValues && c.hasConditions() && c.AttributeSource != resourceAttributeSource {
+		return errMixedRoutesInContext
+	}
+
+	// we also need a "FromAttribute" value when routing on values
+	if hasValues && len(c.FromAttribute) == 0 {
 		return fmt.Errorf(
 			"invalid attribute to read the route's value from: %w",
 			errNoMissingFromAttribute,
@@ -96,6 +148,17 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// hasConditions reports whether any route of the table uses a condition, in
+// which case the routing decision is made for every resource.
+func (c *Config) hasConditions() bool {
+	for _, item := range c.Table {
+		if len(item.Condition) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 type AttributeSource string
 
 const (
@@ -105,11 +168,26 @@ const (
 	defaultAttributeSource = contextAttributeSource
 )
 
+type Granularity string
+
+const (
+	resourceGranularity = Granularity("resource")
+	recordGranularity   = Granularity("record")
+
+	defaultGranularity = resourceGranularity
+)
+
 // RoutingTableItem specifies how data should be routed to the different exporters
 type RoutingTableItem struct {
-	// Value represents a possible value for the field specified under FromAttribute. Required.
+	// Value represents a possible value for the field specified under FromAttribute.
+	// Either Value or Condition is required.
 	Value string `mapstructure:"value"`
 
+	// Condition is a TQL boolean expression evaluated against each resource, e.g.
+	// `resource.attributes["env"] == "prod"`. Resources are routed to every route
+	// whose condition is true. Either Value or Condition is required.
+	Condition string `mapstructure:"condition"`
+
 	// Exporters contains the list of exporters to use when the value from the FromAttribute field matches this table item.
 	// When no exporters are specified, the ones specified under DefaultExporters are used, if any.
 	// The routing processor will fail upon the first failure from these exporters.
@@ -35,6 +35,7 @@ func TestLoadConfig(t *testing.T) {
 				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 				DefaultExporters:  []string{"otlp"},
 				AttributeSource:   "context",
+				Granularity:       "resource",
 				FromAttribute:     "X-Tenant",
 				Table: []RoutingTableItem{
 					{
@@ -54,6 +55,7 @@ func TestLoadConfig(t *testing.T) {
 				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 				DefaultExporters:  []string{"logging/default"},
 				AttributeSource:   "context",
+				Granularity:       "resource",
 				FromAttribute:     "X-Custom-Metrics-Header",
 				Table: []RoutingTableItem{
 					{
@@ -73,6 +75,7 @@ func TestLoadConfig(t *testing.T) {
 				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 				DefaultExporters:  []string{"logging/default"},
 				AttributeSource:   "context",
+				Granularity:       "resource",
 				FromAttribute:     "X-Custom-Logs-Header",
 				Table: []RoutingTableItem{
 					{
@@ -51,6 +51,16 @@ func (e extractor) extractAttrFromResource(r pcommon.Resource) string {
 	return routingAttribute.AsString()
 }
 
+// extractAttrFromRecord extracts the string value of the requested attribute
+// from the attributes of a span, log record or data point, falling back to the
+// attributes of its resource.
+func (e extractor) extractAttrFromRecord(attrs pcommon.Map, r pcommon.Resource) string {
+	if routingAttribute, found := attrs.Get(e.fromAttr); found {
+		return routingAttribute.AsString()
+	}
+	return e.extractAttrFromResource(r)
+}
+
 func (e extractor) extractFromContext(ctx context.Context) string {
 	// right now, we only support looking up attributes from requests that have
 	// gone through the gRPC server in that case, it will add the HTTP headers
@@ -45,6 +45,7 @@ func createDefaultConfig() config.Processor {
 	return &Config{
 		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
 		AttributeSource:   defaultAttributeSource,
+		Granularity:       defaultGranularity,
 	}
 }
 
@@ -115,6 +115,103 @@ func TestProcessorFailsWithNoFromAttribute(t *testing.T) {
 	assert.ErrorIs(t, cfg.Validate(), errNoMissingFromAttribute)
 }
 
+func TestProcessorConditionRoutesDoNotRequireFromAttribute(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		DefaultExporters:  []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Condition: `resource.attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestProcessorFailsWithInvalidRouteCondition(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		Table: []RoutingTableItem{
+			{
+				Condition: `attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestProcessorFailsWithValueAndCondition(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		FromAttribute:     "X-Tenant",
+		AttributeSource:   resourceAttributeSource,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Condition: `resource.attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	}
+	assert.ErrorIs(t, cfg.Validate(), errValueAndCondition)
+}
+
+func TestProcessorFailsWithConditionAndValueRoutesFromContext(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		FromAttribute:     "X-Tenant",
+		AttributeSource:   contextAttributeSource,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+			{
+				Condition: `resource.attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	}
+	assert.ErrorIs(t, cfg.Validate(), errMixedRoutesInContext)
+
+	cfg.AttributeSource = resourceAttributeSource
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestProcessorRecordGranularityValidation(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		FromAttribute:     "X-Tenant",
+		AttributeSource:   resourceAttributeSource,
+		Granularity:       recordGranularity,
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+			{
+				// Record conditions are only parsed when the processor starts.
+				Condition: `attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.DropRoutingResourceAttribute = true
+	assert.ErrorIs(t, cfg.Validate(), errRecordDropAttribute)
+
+	cfg.DropRoutingResourceAttribute = false
+	cfg.AttributeSource = contextAttributeSource
+	assert.ErrorIs(t, cfg.Validate(), errRecordFromContext)
+
+	cfg.AttributeSource = resourceAttributeSource
+	cfg.Granularity = "span"
+	assert.ErrorIs(t, cfg.Validate(), errInvalidGranularity)
+}
+
 func TestShouldNotFailWhenNextIsProcessor(t *testing.T) {
 	// prepare
 	factory := NewFactory()
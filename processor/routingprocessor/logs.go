@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqllogs"
 )
 
 var _ component.LogsProcessor = (*logProcessor)(nil)
@@ -43,7 +45,7 @@ func newLogProcessor(logger *zap.Logger, cfg config.Processor) *logProcessor {
 		config: oCfg,
 
 		extractor: newExtractor(oCfg.FromAttribute, logger),
-		router:    newRouter[component.LogsExporter](*oCfg, logger),
+		router:    newRouter[component.LogsExporter](*oCfg, logger, conditionParserFor(oCfg, tqllogs.ParsePath, tqllogs.ParseEnum)),
 	}
 }
 
@@ -57,11 +59,11 @@ func (p *logProcessor) Start(_ context.Context, host component.Host) error {
 
 func (p *logProcessor) ConsumeLogs(ctx context.Context, tl plog.Logs) error {
 	var errs error
-	switch p.config.AttributeSource {
-	case resourceAttributeSource:
+	switch {
+	case p.config.Granularity == recordGranularity:
+		errs = multierr.Append(errs, p.routeLogRecords(ctx, tl))
+	case p.config.AttributeSource == resourceAttributeSource || p.config.hasConditions():
 		errs = multierr.Append(errs, p.route(ctx, tl))
-	case contextAttributeSource:
-		fallthrough
 	default:
 		errs = multierr.Append(errs, p.routeForContext(ctx, tl))
 	}
@@ -78,34 +80,33 @@ func (p *logProcessor) route(ctx context.Context, l plog.Logs) error {
 		exporters []component.LogsExporter
 		resLogs   plog.ResourceLogsSlice
 	}{}
-	var errs error
 
+	var errs error
 	resLogsSlice := l.ResourceLogs()
 	for i := 0; i < resLogsSlice.Len(); i++ {
 		resLogs := resLogsSlice.At(i)
 
-		attrValue := p.extractor.extractAttrFromResource(resLogs.Resource())
-		exp := p.router.defaultExporters
-		// If we have an exporter list defined for that attribute value then use it.
-		if e, ok := p.router.exporters[attrValue]; ok {
-			exp = e
-			if p.config.DropRoutingResourceAttribute {
-				resLogs.Resource().Attributes().Remove(p.config.FromAttribute)
-			}
+		var attrValue string
+		if p.config.FromAttribute != "" {
+			attrValue = p.extractor.extractAttrFromResource(resLogs.Resource())
+		}
+		routes, valueMatched := p.router.routesForResource(attrValue, resLogs.Resource())
+		if valueMatched && p.config.DropRoutingResourceAttribute {
+			resLogs.Resource().Attributes().Remove(p.config.FromAttribute)
 		}
 
-		if rEntry, ok := groups[attrValue]; ok {
-			resLogs.MoveTo(rEntry.resLogs.AppendEmpty())
-		} else {
-			newResLogs := plog.NewResourceLogsSlice()
-			resLogs.MoveTo(newResLogs.AppendEmpty())
-
-			groups[attrValue] = struct {
-				exporters []component.LogsExporter
-				resLogs   plog.ResourceLogsSlice
-			}{
-				exporters: exp,
-				resLogs:   newResLogs,
+		for j, route := range routes {
+			rEntry, ok := groups[route.key]
+			if !ok {
+				rEntry.exporters = route.exporters
+				rEntry.resLogs = plog.NewResourceLogsSlice()
+				groups[route.key] = rEntry
+			}
+			// A resource matching several routes is copied to all of them but the last one.
+			if j == len(routes)-1 {
+				resLogs.MoveTo(rEntry.resLogs.AppendEmpty())
+			} else {
+				resLogs.CopyTo(rEntry.resLogs.AppendEmpty())
 			}
 		}
 	}
@@ -122,6 +123,90 @@ func (p *logProcessor) route(ctx context.Context, l plog.Logs) error {
 	return errs
 }
 
+// routeLogRecords routes every log record on its own. The log records sent to
+// the same route are regrouped under copies of their original resource and scope.
+func (p *logProcessor) routeLogRecords(ctx context.Context, l plog.Logs) error {
+	groups := map[string]*logGroup{}
+
+	var errs error
+	resLogsSlice := l.ResourceLogs()
+	for i := 0; i < resLogsSlice.Len(); i++ {
+		resLogs := resLogsSlice.At(i)
+		scopeLogsSlice := resLogs.ScopeLogs()
+		for j := 0; j < scopeLogsSlice.Len(); j++ {
+			scopeLogs := scopeLogsSlice.At(j)
+			logRecords := scopeLogs.LogRecords()
+			for k := 0; k < logRecords.Len(); k++ {
+				logRecord := logRecords.At(k)
+
+				var attrValue string
+				if p.config.FromAttribute != "" {
+					attrValue = p.extractor.extractAttrFromRecord(logRecord.Attributes(), resLogs.Resource())
+				}
+				tCtx := tqllogs.NewTransformContext(logRecord, scopeLogs.Scope(), resLogs.Resource())
+				routes, _ := p.router.routesFor(attrValue, tCtx)
+				for _, route := range routes {
+					g, ok := groups[route.key]
+					if !ok {
+						g = newLogGroup(route.exporters)
+						groups[route.key] = g
+					}
+					logRecord.CopyTo(g.scopeLogsFor(i, resLogs, j, scopeLogs).LogRecords().AppendEmpty())
+				}
+			}
+		}
+	}
+
+	for _, g := range groups {
+		for _, e := range g.exporters {
+			errs = multierr.Append(errs, e.ConsumeLogs(ctx, g.logs))
+		}
+	}
+	return errs
+}
+
+// logGroup holds the log records routed to the same exporters.
+type logGroup struct {
+	exporters []component.LogsExporter
+	logs      plog.Logs
+
+	// resIdx and scopeIdx are the indexes, in the incoming logs, of the
+	// resource and scope of the last log record added to the group.
+	resIdx    int
+	scopeIdx  int
+	scopeLogs plog.ScopeLogs
+}
+
+func newLogGroup(exporters []component.LogsExporter) *logGroup {
+	return &logGroup{
+		exporters: exporters,
+		logs:      plog.NewLogs(),
+		resIdx:    -1,
+		scopeIdx:  -1,
+	}
+}
+
+// scopeLogsFor returns the scope logs of the group the log records of the
+// given resource and scope are added to. Log records are visited in order, so
+// the resource and scope only have to be copied when they differ from the last ones.
+func (g *logGroup) scopeLogsFor(resIdx int, resLogs plog.ResourceLogs, scopeIdx int, scopeLogs plog.ScopeLogs) plog.ScopeLogs {
+	if g.resIdx != resIdx {
+		rl := g.logs.ResourceLogs().AppendEmpty()
+		resLogs.Resource().CopyTo(rl.Resource())
+		rl.SetSchemaUrl(resLogs.SchemaUrl())
+		g.resIdx = resIdx
+		g.scopeIdx = -1
+	}
+	if g.scopeIdx != scopeIdx {
+		rls := g.logs.ResourceLogs()
+		g.scopeLogs = rls.At(rls.Len() - 1).ScopeLogs().AppendEmpty()
+		scopeLogs.Scope().CopyTo(g.scopeLogs.Scope())
+		g.scopeLogs.SetSchemaUrl(scopeLogs.SchemaUrl())
+		g.scopeIdx = scopeIdx
+	}
+	return g.scopeLogs
+}
+
 func (p *logProcessor) routeForContext(ctx context.Context, l plog.Logs) error {
 	value := p.extractor.extractFromContext(ctx)
 	exporters, ok := p.router.exporters[value]
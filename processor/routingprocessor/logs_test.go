@@ -273,6 +273,110 @@ func TestLogs_AreCorrectlySplitPerResourceAttributeRouting(t *testing.T) {
 	)
 }
 
+func TestLogs_RoutingWorks_ConditionAndValue(t *testing.T) {
+	defaultExp := &mockLogsExporter{}
+	acmeExp := &mockLogsExporter{}
+	prodExp := &mockLogsExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				config.LogsDataType: {
+					config.NewComponentID("otlp"):      defaultExp,
+					config.NewComponentID("otlp/acme"): acmeExp,
+					config.NewComponentID("otlp/prod"): prodExp,
+				},
+			}
+		},
+	}
+
+	exp := newLogProcessor(zap.NewNop(), &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  resourceAttributeSource,
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+			{
+				Condition: `resource.attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	l := plog.NewLogs()
+	rl := l.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("X-Tenant", "acme")
+	rl.Resource().Attributes().InsertString("env", "prod")
+	rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), l))
+	assert.Len(t, defaultExp.AllLogs(), 0)
+	require.Len(t, acmeExp.AllLogs(), 1)
+	assert.Equal(t, 1, acmeExp.AllLogs()[0].LogRecordCount())
+	require.Len(t, prodExp.AllLogs(), 1)
+	assert.Equal(t, 1, prodExp.AllLogs()[0].LogRecordCount())
+}
+
+func TestLogs_RoutingWorks_RecordGranularity(t *testing.T) {
+	defaultExp := &mockLogsExporter{}
+	acmeExp := &mockLogsExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				config.LogsDataType: {
+					config.NewComponentID("otlp"):      defaultExp,
+					config.NewComponentID("otlp/acme"): acmeExp,
+				},
+			}
+		},
+	}
+
+	exp := newLogProcessor(zap.NewNop(), &Config{
+		DefaultExporters: []string{"otlp"},
+		Granularity:      recordGranularity,
+		Table: []RoutingTableItem{
+			{
+				Condition: `attributes["X-Tenant"] == "acme"`,
+				Exporters: []string{"otlp/acme"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	l := plog.NewLogs()
+	rl := l.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().InsertString("host.name", "node-1")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	for _, tenant := range []string{"acme", "other", "acme"} {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.Attributes().InsertString("X-Tenant", tenant)
+		lr.Body().SetStringVal(tenant)
+	}
+
+	require.NoError(t, exp.ConsumeLogs(context.Background(), l))
+
+	require.Len(t, acmeExp.AllLogs(), 1)
+	acme := acmeExp.AllLogs()[0]
+	assert.Equal(t, 2, acme.LogRecordCount())
+	require.Equal(t, 1, acme.ResourceLogs().Len())
+	hostName, _ := acme.ResourceLogs().At(0).Resource().Attributes().Get("host.name")
+	assert.Equal(t, "node-1", hostName.StringVal())
+	require.Equal(t, 1, acme.ResourceLogs().At(0).ScopeLogs().Len())
+	assert.Equal(t, "scope", acme.ResourceLogs().At(0).ScopeLogs().At(0).Scope().Name())
+
+	require.Len(t, defaultExp.AllLogs(), 1)
+	require.Equal(t, 1, defaultExp.AllLogs()[0].LogRecordCount())
+	assert.Equal(t, "other", defaultExp.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().StringVal())
+}
+
 type mockLogsExporter struct {
 	mockComponent
 	consumertest.LogsSink
@@ -20,9 +20,12 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqlmetrics"
 )
 
 var _ component.MetricsProcessor = (*metricsProcessor)(nil)
@@ -43,7 +46,7 @@ func newMetricProcessor(logger *zap.Logger, cfg config.Processor) *metricsProces
 		config: oCfg,
 
 		extractor: newExtractor(oCfg.FromAttribute, logger),
-		router:    newRouter[component.MetricsExporter](*oCfg, logger),
+		router:    newRouter[component.MetricsExporter](*oCfg, logger, conditionParserFor(oCfg, tqlmetrics.ParsePath, tqlmetrics.ParseEnum)),
 	}
 }
 
@@ -57,11 +60,11 @@ func (p *metricsProcessor) Start(_ context.Context, host component.Host) error {
 
 func (p *metricsProcessor) ConsumeMetrics(ctx context.Context, m pmetric.Metrics) error {
 	var errs error
-	switch p.config.AttributeSource {
-	case resourceAttributeSource:
+	switch {
+	case p.config.Granularity == recordGranularity:
+		errs = multierr.Append(errs, p.routeDataPoints(ctx, m))
+	case p.config.AttributeSource == resourceAttributeSource || p.config.hasConditions():
 		errs = multierr.Append(errs, p.route(ctx, m))
-	case contextAttributeSource:
-		fallthrough
 	default:
 		errs = multierr.Append(errs, p.routeForContext(ctx, m))
 	}
@@ -84,44 +87,192 @@ func (p *metricsProcessor) route(ctx context.Context, tm pmetric.Metrics) error
 	for i := 0; i < resMetricsSlice.Len(); i++ {
 		resMetrics := resMetricsSlice.At(i)
 
-		attrValue := p.extractor.extractAttrFromResource(resMetrics.Resource())
-		exp := p.router.defaultExporters
-		// If we have an exporter list defined for that attribute value then use it.
-		if e, ok := p.router.exporters[attrValue]; ok {
-			exp = e
-			if p.config.DropRoutingResourceAttribute {
-				resMetrics.Resource().Attributes().Remove(p.config.FromAttribute)
-			}
+		var attrValue string
+		if p.config.FromAttribute != "" {
+			attrValue = p.extractor.extractAttrFromResource(resMetrics.Resource())
+		}
+		routes, valueMatched := p.router.routesForResource(attrValue, resMetrics.Resource())
+		if valueMatched && p.config.DropRoutingResourceAttribute {
+			resMetrics.Resource().Attributes().Remove(p.config.FromAttribute)
 		}
 
-		if rEntry, ok := groups[attrValue]; ok {
-			resMetrics.MoveTo(rEntry.resMetrics.AppendEmpty())
-		} else {
-			newResMetrics := pmetric.NewResourceMetricsSlice()
-			resMetrics.MoveTo(newResMetrics.AppendEmpty())
-
-			groups[attrValue] = struct {
-				exporters  []component.MetricsExporter
-				resMetrics pmetric.ResourceMetricsSlice
-			}{
-				exporters:  exp,
-				resMetrics: newResMetrics,
+		for j, route := range routes {
+			rEntry, ok := groups[route.key]
+			if !ok {
+				rEntry.exporters = route.exporters
+				rEntry.resMetrics = pmetric.NewResourceMetricsSlice()
+				groups[route.key] = rEntry
+			}
+			// A resource matching several routes is copied to all of them but the last one.
+			if j == len(routes)-1 {
+				resMetrics.MoveTo(rEntry.resMetrics.AppendEmpty())
+			} else {
+				resMetrics.CopyTo(rEntry.resMetrics.AppendEmpty())
 			}
 		}
 	}
 
 	for _, g := range groups {
-		m := pmetric.NewMetrics()
-		m.ResourceMetrics().EnsureCapacity(g.resMetrics.Len())
-		g.resMetrics.MoveAndAppendTo(m.ResourceMetrics())
+		tm := pmetric.NewMetrics()
+		tm.ResourceMetrics().EnsureCapacity(g.resMetrics.Len())
+		g.resMetrics.MoveAndAppendTo(tm.ResourceMetrics())
 
 		for _, e := range g.exporters {
-			errs = multierr.Append(errs, e.ConsumeMetrics(ctx, m))
+			errs = multierr.Append(errs, e.ConsumeMetrics(ctx, tm))
 		}
 	}
 	return errs
 }
 
+// routeDataPoints routes every data point on its own. The data points sent to
+// the same route are regrouped under copies of their original resource, scope
+// and metric.
+func (p *metricsProcessor) routeDataPoints(ctx context.Context, m pmetric.Metrics) error {
+	groups := map[string]*metricGroup{}
+
+	var errs error
+	resMetricsSlice := m.ResourceMetrics()
+	for i := 0; i < resMetricsSlice.Len(); i++ {
+		resMetrics := resMetricsSlice.At(i)
+		scopeMetricsSlice := resMetrics.ScopeMetrics()
+		for j := 0; j < scopeMetricsSlice.Len(); j++ {
+			scopeMetrics := scopeMetricsSlice.At(j)
+			metrics := scopeMetrics.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+
+				// route sends a single data point to its routes, appendTo copies it
+				// to the metric of a group.
+				route := func(dataPoint interface{}, attrs pcommon.Map, appendTo func(dest pmetric.Metric)) {
+					var attrValue string
+					if p.config.FromAttribute != "" {
+						attrValue = p.extractor.extractAttrFromRecord(attrs, resMetrics.Resource())
+					}
+					tCtx := tqlmetrics.NewTransformContext(dataPoint, metric, metrics, scopeMetrics.Scope(), resMetrics.Resource())
+					routes, _ := p.router.routesFor(attrValue, tCtx)
+					for _, route := range routes {
+						g, ok := groups[route.key]
+						if !ok {
+							g = newMetricGroup(route.exporters)
+							groups[route.key] = g
+						}
+						appendTo(g.metricFor(i, resMetrics, j, scopeMetrics, k, metric))
+					}
+				}
+
+				switch metric.DataType() {
+				case pmetric.MetricDataTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						route(dp, dp.Attributes(), func(dest pmetric.Metric) { dp.CopyTo(dest.Gauge().DataPoints().AppendEmpty()) })
+					}
+				case pmetric.MetricDataTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						route(dp, dp.Attributes(), func(dest pmetric.Metric) { dp.CopyTo(dest.Sum().DataPoints().AppendEmpty()) })
+					}
+				case pmetric.MetricDataTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						route(dp, dp.Attributes(), func(dest pmetric.Metric) { dp.CopyTo(dest.Histogram().DataPoints().AppendEmpty()) })
+					}
+				case pmetric.MetricDataTypeExponentialHistogram:
+					dps := metric.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						route(dp, dp.Attributes(), func(dest pmetric.Metric) { dp.CopyTo(dest.ExponentialHistogram().DataPoints().AppendEmpty()) })
+					}
+				case pmetric.MetricDataTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						route(dp, dp.Attributes(), func(dest pmetric.Metric) { dp.CopyTo(dest.Summary().DataPoints().AppendEmpty()) })
+					}
+				}
+			}
+		}
+	}
+
+	for _, g := range groups {
+		for _, e := range g.exporters {
+			errs = multierr.Append(errs, e.ConsumeMetrics(ctx, g.metrics))
+		}
+	}
+	return errs
+}
+
+// metricGroup holds the data points routed to the same exporters.
+type metricGroup struct {
+	exporters []component.MetricsExporter
+	metrics   pmetric.Metrics
+
+	// resIdx, scopeIdx and metricIdx are the indexes, in the incoming metrics,
+	// of the resource, scope and metric of the last data point added to the group.
+	resIdx       int
+	scopeIdx     int
+	metricIdx    int
+	scopeMetrics pmetric.ScopeMetrics
+	metric       pmetric.Metric
+}
+
+func newMetricGroup(exporters []component.MetricsExporter) *metricGroup {
+	return &metricGroup{
+		exporters: exporters,
+		metrics:   pmetric.NewMetrics(),
+		resIdx:    -1,
+		scopeIdx:  -1,
+		metricIdx: -1,
+	}
+}
+
+// metricFor returns the metric of the group the data points of the given
+// resource, scope and metric are added to. Data points are visited in order,
+// so the resource, scope and metric only have to be copied when they differ
+// from the last ones.
+func (g *metricGroup) metricFor(resIdx int, resMetrics pmetric.ResourceMetrics, scopeIdx int, scopeMetrics pmetric.ScopeMetrics, metricIdx int, metric pmetric.Metric) pmetric.Metric {
+	if g.resIdx != resIdx {
+		rm := g.metrics.ResourceMetrics().AppendEmpty()
+		resMetrics.Resource().CopyTo(rm.Resource())
+		rm.SetSchemaUrl(resMetrics.SchemaUrl())
+		g.resIdx = resIdx
+		g.scopeIdx = -1
+	}
+	if g.scopeIdx != scopeIdx {
+		rms := g.metrics.ResourceMetrics()
+		g.scopeMetrics = rms.At(rms.Len() - 1).ScopeMetrics().AppendEmpty()
+		scopeMetrics.Scope().CopyTo(g.scopeMetrics.Scope())
+		g.scopeMetrics.SetSchemaUrl(scopeMetrics.SchemaUrl())
+		g.scopeIdx = scopeIdx
+		g.metricIdx = -1
+	}
+	if g.metricIdx != metricIdx {
+		g.metric = g.scopeMetrics.Metrics().AppendEmpty()
+		copyMetricDescription(metric, g.metric)
+		g.metricIdx = metricIdx
+	}
+	return g.metric
+}
+
+// copyMetricDescription copies everything but the data points of the metric.
+func copyMetricDescription(src pmetric.Metric, dest pmetric.Metric) {
+	dest.SetName(src.Name())
+	dest.SetDescription(src.Description())
+	dest.SetUnit(src.Unit())
+	dest.SetDataType(src.DataType())
+	switch src.DataType() {
+	case pmetric.MetricDataTypeSum:
+		dest.Sum().SetAggregationTemporality(src.Sum().AggregationTemporality())
+		dest.Sum().SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricDataTypeHistogram:
+		dest.Histogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dest.ExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	}
+}
+
 func (p *metricsProcessor) routeForContext(ctx context.Context, m pmetric.Metrics) error {
 	value := p.extractor.extractFromContext(ctx)
 	exporters, ok := p.router.exporters[value]
@@ -110,6 +110,74 @@ func TestMetrics_AreCorrectlySplitPerResourceAttributeRouting(t *testing.T) {
 	)
 }
 
+func TestMetrics_RoutingWorks_RecordGranularity(t *testing.T) {
+	defaultExp := &mockMetricsExporter{}
+	acmeExp := &mockMetricsExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				config.MetricsDataType: {
+					config.NewComponentID("otlp"):      defaultExp,
+					config.NewComponentID("otlp/acme"): acmeExp,
+				},
+			}
+		},
+	}
+
+	exp := newMetricProcessor(zap.NewNop(), &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  resourceAttributeSource,
+		Granularity:      recordGranularity,
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	m := pmetric.NewMetrics()
+	rm := m.ResourceMetrics().AppendEmpty()
+	sm := rm.ScopeMetrics().AppendEmpty()
+	metric := sm.Metrics().AppendEmpty()
+	metric.SetName("requests")
+	metric.SetUnit("1")
+	metric.SetDataType(pmetric.MetricDataTypeSum)
+	metric.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	metric.Sum().SetIsMonotonic(true)
+	for _, tenant := range []string{"acme", "other", "acme"} {
+		metric.Sum().DataPoints().AppendEmpty().Attributes().InsertString("X-Tenant", tenant)
+	}
+	metric = sm.Metrics().AppendEmpty()
+	metric.SetName("latency")
+	metric.SetDataType(pmetric.MetricDataTypeHistogram)
+	metric.Histogram().DataPoints().AppendEmpty().Attributes().InsertString("X-Tenant", "other")
+
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), m))
+
+	require.Len(t, acmeExp.AllMetrics(), 1)
+	acme := acmeExp.AllMetrics()[0]
+	assert.Equal(t, 2, acme.DataPointCount())
+	require.Equal(t, 1, acme.MetricCount())
+	requests := acme.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", requests.Name())
+	assert.Equal(t, "1", requests.Unit())
+	assert.Equal(t, pmetric.MetricAggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	assert.True(t, requests.Sum().IsMonotonic())
+
+	require.Len(t, defaultExp.AllMetrics(), 1)
+	assert.Equal(t, 2, defaultExp.AllMetrics()[0].DataPointCount())
+	metrics := defaultExp.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "requests", metrics.At(0).Name())
+	assert.Equal(t, "latency", metrics.At(1).Name())
+	assert.Equal(t, pmetric.MetricDataTypeHistogram, metrics.At(1).DataType())
+}
+
 func TestMetrics_RoutingWorks_Context(t *testing.T) {
 	defaultExp := &mockMetricsExporter{}
 	mExp := &mockMetricsExporter{}
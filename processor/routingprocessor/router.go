@@ -17,10 +17,16 @@ package routingprocessor // import "github.com/open-telemetry/opentelemetry-coll
 import (
 	"errors"
 	"fmt"
+	"strconv"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqlresource"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlcommon"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
 )
 
 var (
@@ -28,23 +34,75 @@ var (
 	errExporterNotFound        = errors.New("exporter not found")
 )
 
+// conditionFunctions contains the TQL functions that can be used in routing conditions.
+var conditionFunctions = map[string]interface{}{
+	"IsMatch": tqlcommon.IsMatch,
+	"Concat":  tqlcommon.Concat,
+	// Join is kept as a deprecated alias of Concat for the existing conditions.
+	"Join": tqlcommon.Join, //nolint:staticcheck
+}
+
+// conditionParser parses a routing condition for the context the router
+// evaluates conditions against.
+type conditionParser func(condition string) (tql.BoolExpressionEvaluator, error)
+
+// newConditionParser creates a conditionParser for the given TQL context.
+func newConditionParser(pathParser tql.PathExpressionParser, enumParser tql.EnumParser) conditionParser {
+	return func(condition string) (tql.BoolExpressionEvaluator, error) {
+		evaluators, err := tql.ParseConditions([]string{condition}, conditionFunctions, pathParser, enumParser)
+		if err != nil {
+			return nil, err
+		}
+		return evaluators[0], nil
+	}
+}
+
+// parseResourceCondition parses a routing condition evaluated against a resource.
+var parseResourceCondition = newConditionParser(tqlresource.ParsePath, tqlresource.ParseEnum)
+
+// conditionParserFor returns the parser for conditions evaluated at the
+// configured granularity, the record path and enum parsers being the ones of
+// the signal the router is created for.
+func conditionParserFor(config *Config, recordPathParser tql.PathExpressionParser, recordEnumParser tql.EnumParser) conditionParser {
+	if config.Granularity == recordGranularity {
+		return newConditionParser(recordPathParser, recordEnumParser)
+	}
+	return parseResourceCondition
+}
+
+// conditionRoute holds the exporters for every resource, or record, matching a TQL condition.
+type conditionRoute[E component.Exporter] struct {
+	condition string
+	evaluate  tql.BoolExpressionEvaluator
+	exporters []E
+}
+
+// matchedRoute identifies a set of exporters a resource, or record, is routed to.
+type matchedRoute[E component.Exporter] struct {
+	key       string
+	exporters []E
+}
+
 // router registers exporters and default exporters for an exporter. router can
 // be instantiated with component.TracesExporter, component.MetricsExporter, and
 // component.LogsExporter type arguments.
 type router[E component.Exporter] struct {
-	config Config
-	logger *zap.Logger
+	config         Config
+	logger         *zap.Logger
+	parseCondition conditionParser
 
 	defaultExporters []E
 	exporters        map[string][]E
+	conditions       []conditionRoute[E]
 }
 
 // newRouter creates a new router instance with its type parameter constrained
 // to component.Exporter.
-func newRouter[E component.Exporter](config Config, logger *zap.Logger) router[E] {
+func newRouter[E component.Exporter](config Config, logger *zap.Logger, parseCondition conditionParser) router[E] {
 	return router[E]{
-		logger: logger,
-		config: config,
+		logger:         logger,
+		config:         config,
+		parseCondition: parseCondition,
 
 		exporters: make(map[string][]E),
 	}
@@ -65,12 +123,56 @@ func (r *router[E]) registerExporters(exporters map[config.ComponentID]component
 
 	// exporters for each route
 	for _, entry := range r.config.Table {
-		r.registerRouteExporters(entry.Value, available, entry.Exporters)
+		if entry.Condition == "" {
+			r.exporters[entry.Value] = append(r.exporters[entry.Value], r.registerRouteExporters(entry.Value, available, entry.Exporters)...)
+			continue
+		}
+
+		evaluate, err := r.parseCondition(entry.Condition)
+		if err != nil {
+			return fmt.Errorf("invalid route condition %q: %w", entry.Condition, err)
+		}
+		r.conditions = append(r.conditions, conditionRoute[E]{
+			condition: entry.Condition,
+			evaluate:  evaluate,
+			exporters: r.registerRouteExporters(entry.Condition, available, entry.Exporters),
+		})
 	}
 
 	return nil
 }
 
+// routesForResource returns the routes matching the resource, see routesFor.
+func (r *router[E]) routesForResource(attrValue string, resource pcommon.Resource) ([]matchedRoute[E], bool) {
+	return r.routesFor(attrValue, tqlresource.NewTransformContext(resource))
+}
+
+// routesFor returns the routes matching the telemetry: the route for the value
+// of its routing attribute, if any, and every route whose condition evaluates
+// to true in the given context. When no route matches, the default exporters
+// are used. The second return value reports whether the route for the
+// attribute value matched.
+func (r *router[E]) routesFor(attrValue string, tCtx tql.TransformContext) ([]matchedRoute[E], bool) {
+	var routes []matchedRoute[E]
+	valueMatched := false
+	if e, ok := r.exporters[attrValue]; ok {
+		routes = append(routes, matchedRoute[E]{key: "value/" + attrValue, exporters: e})
+		valueMatched = true
+	}
+
+	for i, c := range r.conditions {
+		if c.evaluate(tCtx) {
+			routes = append(routes, matchedRoute[E]{key: "condition/" + strconv.Itoa(i), exporters: c.exporters})
+		}
+	}
+
+	if len(routes) == 0 {
+		// Unmatched telemetry keeps being grouped by its attribute value.
+		routes = append(routes, matchedRoute[E]{key: "default/" + attrValue, exporters: r.defaultExporters})
+	}
+	return routes, valueMatched
+}
+
 // registerDefaultExporters registers the configured default exporters
 // using the provided available exporters map.
 func (r *router[E]) registerDefaultExporters(availableExporters map[string]component.Exporter) {
@@ -95,18 +197,19 @@ func (r *router[E]) registerDefaultExporters(availableExporters map[string]compo
 	}
 }
 
-// registerRouteExporters registers the requested exporters using the provided
+// registerRouteExporters returns the requested exporters using the provided
 // available exporters map to check if they were available.
 func (r *router[E]) registerRouteExporters(
 	route string,
 	availableExporters map[string]component.Exporter,
 	exporters []string,
-) {
+) []E {
 	r.logger.Debug("Registering exporter for route",
 		zap.String("route", route),
 		zap.Any("requested", exporters),
 	)
 
+	var registered []E
 	for _, e := range exporters {
 		v, ok := availableExporters[e]
 		if !ok {
@@ -125,6 +228,7 @@ func (r *router[E]) registerRouteExporters(
 			)
 			continue
 		}
-		r.exporters[route] = append(r.exporters[route], v.(E))
+		registered = append(registered, v.(E))
 	}
+	return registered
 }
@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqltraces"
 )
 
 var _ component.TracesProcessor = (*tracesProcessor)(nil)
@@ -43,7 +45,7 @@ func newTracesProcessor(logger *zap.Logger, cfg config.Processor) *tracesProcess
 		config: oCfg,
 
 		extractor: newExtractor(oCfg.FromAttribute, logger),
-		router:    newRouter[component.TracesExporter](*oCfg, logger),
+		router:    newRouter[component.TracesExporter](*oCfg, logger, conditionParserFor(oCfg, tqltraces.ParsePath, tqltraces.ParseEnum)),
 	}
 }
 
@@ -57,11 +59,11 @@ func (p *tracesProcessor) Start(_ context.Context, host component.Host) error {
 
 func (p *tracesProcessor) ConsumeTraces(ctx context.Context, t ptrace.Traces) error {
 	var errs error
-	switch p.config.AttributeSource {
-	case resourceAttributeSource:
+	switch {
+	case p.config.Granularity == recordGranularity:
+		errs = multierr.Append(errs, p.routeSpans(ctx, t))
+	case p.config.AttributeSource == resourceAttributeSource || p.config.hasConditions():
 		errs = multierr.Append(errs, p.route(ctx, t))
-	case contextAttributeSource:
-		fallthrough
 	default:
 		errs = multierr.Append(errs, p.routeForContext(ctx, t))
 	}
@@ -84,28 +86,27 @@ func (p *tracesProcessor) route(ctx context.Context, t ptrace.Traces) error {
 	for i := 0; i < resSpansSlice.Len(); i++ {
 		resSpans := resSpansSlice.At(i)
 
-		attrValue := p.extractor.extractAttrFromResource(resSpans.Resource())
-		exp := p.router.defaultExporters
-		// If we have an exporter list defined for that attribute value then use it.
-		if e, ok := p.router.exporters[attrValue]; ok {
-			exp = e
-			if p.config.DropRoutingResourceAttribute {
-				resSpans.Resource().Attributes().Remove(p.config.FromAttribute)
-			}
+		var attrValue string
+		if p.config.FromAttribute != "" {
+			attrValue = p.extractor.extractAttrFromResource(resSpans.Resource())
+		}
+		routes, valueMatched := p.router.routesForResource(attrValue, resSpans.Resource())
+		if valueMatched && p.config.DropRoutingResourceAttribute {
+			resSpans.Resource().Attributes().Remove(p.config.FromAttribute)
 		}
 
-		if rEntry, ok := groups[attrValue]; ok {
-			resSpans.MoveTo(rEntry.resSpans.AppendEmpty())
-		} else {
-			newResSpans := ptrace.NewResourceSpansSlice()
-			resSpans.MoveTo(newResSpans.AppendEmpty())
-
-			groups[attrValue] = struct {
-				exporters []component.TracesExporter
-				resSpans  ptrace.ResourceSpansSlice
-			}{
-				exporters: exp,
-				resSpans:  newResSpans,
+		for j, route := range routes {
+			rEntry, ok := groups[route.key]
+			if !ok {
+				rEntry.exporters = route.exporters
+				rEntry.resSpans = ptrace.NewResourceSpansSlice()
+				groups[route.key] = rEntry
+			}
+			// A resource matching several routes is copied to all of them but the last one.
+			if j == len(routes)-1 {
+				resSpans.MoveTo(rEntry.resSpans.AppendEmpty())
+			} else {
+				resSpans.CopyTo(rEntry.resSpans.AppendEmpty())
 			}
 		}
 	}
@@ -122,6 +123,90 @@ func (p *tracesProcessor) route(ctx context.Context, t ptrace.Traces) error {
 	return errs
 }
 
+// routeSpans routes every span on its own. The spans sent to the same route
+// are regrouped under copies of their original resource and scope.
+func (p *tracesProcessor) routeSpans(ctx context.Context, t ptrace.Traces) error {
+	groups := map[string]*spanGroup{}
+
+	var errs error
+	resSpansSlice := t.ResourceSpans()
+	for i := 0; i < resSpansSlice.Len(); i++ {
+		resSpans := resSpansSlice.At(i)
+		scopeSpansSlice := resSpans.ScopeSpans()
+		for j := 0; j < scopeSpansSlice.Len(); j++ {
+			scopeSpans := scopeSpansSlice.At(j)
+			spans := scopeSpans.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+
+				var attrValue string
+				if p.config.FromAttribute != "" {
+					attrValue = p.extractor.extractAttrFromRecord(span.Attributes(), resSpans.Resource())
+				}
+				tCtx := tqltraces.NewTransformContext(span, scopeSpans.Scope(), resSpans.Resource())
+				routes, _ := p.router.routesFor(attrValue, tCtx)
+				for _, route := range routes {
+					g, ok := groups[route.key]
+					if !ok {
+						g = newSpanGroup(route.exporters)
+						groups[route.key] = g
+					}
+					span.CopyTo(g.scopeSpansFor(i, resSpans, j, scopeSpans).Spans().AppendEmpty())
+				}
+			}
+		}
+	}
+
+	for _, g := range groups {
+		for _, e := range g.exporters {
+			errs = multierr.Append(errs, e.ConsumeTraces(ctx, g.traces))
+		}
+	}
+	return errs
+}
+
+// spanGroup holds the spans routed to the same exporters.
+type spanGroup struct {
+	exporters []component.TracesExporter
+	traces    ptrace.Traces
+
+	// resIdx and scopeIdx are the indexes, in the incoming traces, of the
+	// resource and scope of the last span added to the group.
+	resIdx     int
+	scopeIdx   int
+	scopeSpans ptrace.ScopeSpans
+}
+
+func newSpanGroup(exporters []component.TracesExporter) *spanGroup {
+	return &spanGroup{
+		exporters: exporters,
+		traces:    ptrace.NewTraces(),
+		resIdx:    -1,
+		scopeIdx:  -1,
+	}
+}
+
+// scopeSpansFor returns the scope spans of the group the spans of the given
+// resource and scope are added to. Spans are visited in order, so the resource
+// and scope only have to be copied when they differ from the last ones.
+func (g *spanGroup) scopeSpansFor(resIdx int, resSpans ptrace.ResourceSpans, scopeIdx int, scopeSpans ptrace.ScopeSpans) ptrace.ScopeSpans {
+	if g.resIdx != resIdx {
+		rs := g.traces.ResourceSpans().AppendEmpty()
+		resSpans.Resource().CopyTo(rs.Resource())
+		rs.SetSchemaUrl(resSpans.SchemaUrl())
+		g.resIdx = resIdx
+		g.scopeIdx = -1
+	}
+	if g.scopeIdx != scopeIdx {
+		rss := g.traces.ResourceSpans()
+		g.scopeSpans = rss.At(rss.Len() - 1).ScopeSpans().AppendEmpty()
+		scopeSpans.Scope().CopyTo(g.scopeSpans.Scope())
+		g.scopeSpans.SetSchemaUrl(scopeSpans.SchemaUrl())
+		g.scopeIdx = scopeIdx
+	}
+	return g.scopeSpans
+}
+
 func (p *tracesProcessor) routeForContext(ctx context.Context, t ptrace.Traces) error {
 	value := p.extractor.extractFromContext(ctx)
 	exporters, ok := p.router.exporters[value]
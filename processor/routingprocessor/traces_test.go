@@ -334,6 +334,174 @@ func TestTraces_RoutingWorks_ResourceAttribute_DropsRoutingAttribute(t *testing.
 	assert.Equal(t, "acme", v.StringVal())
 }
 
+func TestTraces_RoutingWorks_Condition(t *testing.T) {
+	defaultExp := &mockTracesExporter{}
+	prodExp := &mockTracesExporter{}
+	paymentsExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				config.TracesDataType: {
+					config.NewComponentID("otlp"):          defaultExp,
+					config.NewComponentID("otlp/prod"):     prodExp,
+					config.NewComponentID("otlp/payments"): paymentsExp,
+				},
+			}
+		},
+	}
+
+	exp := newTracesProcessor(zap.NewNop(), &Config{
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Condition: `resource.attributes["env"] == "prod"`,
+				Exporters: []string{"otlp/prod"},
+			},
+			{
+				Condition: `resource.attributes["env"] == "prod" and IsMatch(resource.attributes["k8s.namespace.name"], "^payments-") == true`,
+				Exporters: []string{"otlp/payments"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	tr := ptrace.NewTraces()
+	for _, attrs := range []map[string]string{
+		{"env": "prod", "k8s.namespace.name": "payments-eu"},
+		{"env": "prod", "k8s.namespace.name": "checkout"},
+		{"env": "dev", "k8s.namespace.name": "payments-eu"},
+	} {
+		rs := tr.ResourceSpans().AppendEmpty()
+		for k, v := range attrs {
+			rs.Resource().Attributes().InsertString(k, v)
+		}
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(attrs["k8s.namespace.name"])
+	}
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), tr))
+
+	require.Len(t, prodExp.AllTraces(), 1)
+	assert.Equal(t, 2, prodExp.AllTraces()[0].ResourceSpans().Len())
+	require.Len(t, paymentsExp.AllTraces(), 1)
+	require.Equal(t, 1, paymentsExp.AllTraces()[0].ResourceSpans().Len())
+	env, _ := paymentsExp.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get("env")
+	assert.Equal(t, "prod", env.StringVal())
+	require.Len(t, defaultExp.AllTraces(), 1)
+	require.Equal(t, 1, defaultExp.AllTraces()[0].ResourceSpans().Len())
+	env, _ = defaultExp.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get("env")
+	assert.Equal(t, "dev", env.StringVal())
+}
+
+func TestParseResourceConditionFunctions(t *testing.T) {
+	for _, condition := range []string{
+		`IsMatch(resource.attributes["k8s.namespace.name"], "^payments-") == true`,
+		`Concat("-", resource.attributes["env"], resource.attributes["region"]) == "prod-eu"`,
+		`Join("-", resource.attributes["env"], resource.attributes["region"]) == "prod-eu"`,
+	} {
+		_, err := parseResourceCondition(condition)
+		assert.NoError(t, err, condition)
+	}
+}
+
+func TestTraces_InvalidConditionFailsOnStart(t *testing.T) {
+	exp := newTracesProcessor(zap.NewNop(), &Config{
+		Table: []RoutingTableItem{
+			{
+				Condition: `name == "foo"`,
+				Exporters: []string{"otlp"},
+			},
+		},
+	})
+	assert.Error(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestTraces_RoutingWorks_RecordGranularity(t *testing.T) {
+	defaultExp := &mockTracesExporter{}
+	acmeExp := &mockTracesExporter{}
+	errorsExp := &mockTracesExporter{}
+
+	host := &mockHost{
+		Host: componenttest.NewNopHost(),
+		GetExportersFunc: func() map[config.DataType]map[config.ComponentID]component.Exporter {
+			return map[config.DataType]map[config.ComponentID]component.Exporter{
+				config.TracesDataType: {
+					config.NewComponentID("otlp"):        defaultExp,
+					config.NewComponentID("otlp/acme"):   acmeExp,
+					config.NewComponentID("otlp/errors"): errorsExp,
+				},
+			}
+		},
+	}
+
+	exp := newTracesProcessor(zap.NewNop(), &Config{
+		FromAttribute:    "X-Tenant",
+		AttributeSource:  resourceAttributeSource,
+		Granularity:      recordGranularity,
+		DefaultExporters: []string{"otlp"},
+		Table: []RoutingTableItem{
+			{
+				Value:     "acme",
+				Exporters: []string{"otlp/acme"},
+			},
+			{
+				Condition: `status.code == STATUS_CODE_ERROR`,
+				Exporters: []string{"otlp/errors"},
+			},
+		},
+	})
+	require.NoError(t, exp.Start(context.Background(), host))
+
+	tr := ptrace.NewTraces()
+	rs := tr.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl("https://opentelemetry.io/schemas/1.9.0")
+	rs.Resource().Attributes().InsertString("service.name", "frontend")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope-a")
+	span := ss.Spans().AppendEmpty()
+	span.SetName("acme-1")
+	span.Attributes().InsertString("X-Tenant", "acme")
+	span = ss.Spans().AppendEmpty()
+	span.SetName("other-1")
+	span.Attributes().InsertString("X-Tenant", "other")
+	ss = rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope-b")
+	span = ss.Spans().AppendEmpty()
+	span.SetName("acme-2")
+	span.Attributes().InsertString("X-Tenant", "acme")
+	span.Status().SetCode(ptrace.StatusCodeError)
+
+	rs = tr.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("X-Tenant", "acme")
+	rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("acme-3")
+
+	require.NoError(t, exp.ConsumeTraces(context.Background(), tr))
+
+	// The spans routed to acme keep their resources and scopes.
+	require.Len(t, acmeExp.AllTraces(), 1)
+	acme := acmeExp.AllTraces()[0]
+	assert.Equal(t, 3, acme.SpanCount())
+	require.Equal(t, 2, acme.ResourceSpans().Len())
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.9.0", acme.ResourceSpans().At(0).SchemaUrl())
+	serviceName, _ := acme.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "frontend", serviceName.StringVal())
+	require.Equal(t, 2, acme.ResourceSpans().At(0).ScopeSpans().Len())
+	assert.Equal(t, "scope-a", acme.ResourceSpans().At(0).ScopeSpans().At(0).Scope().Name())
+	assert.Equal(t, "acme-1", acme.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "scope-b", acme.ResourceSpans().At(0).ScopeSpans().At(1).Scope().Name())
+	assert.Equal(t, "acme-2", acme.ResourceSpans().At(0).ScopeSpans().At(1).Spans().At(0).Name())
+	assert.Equal(t, "acme-3", acme.ResourceSpans().At(1).ScopeSpans().At(0).Spans().At(0).Name())
+
+	require.Len(t, errorsExp.AllTraces(), 1)
+	require.Equal(t, 1, errorsExp.AllTraces()[0].SpanCount())
+	assert.Equal(t, "scope-b", errorsExp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Scope().Name())
+
+	require.Len(t, defaultExp.AllTraces(), 1)
+	require.Equal(t, 1, defaultExp.AllTraces()[0].SpanCount())
+	assert.Equal(t, "other-1", defaultExp.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+}
+
 func TestTraceProcessorCapabilities(t *testing.T) {
 	// prepare
 	config := &Config{
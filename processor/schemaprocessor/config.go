@@ -44,6 +44,12 @@ type Config struct {
 	// translated to, allowing older and newer formats
 	// to conform to the target schema identifier.
 	Targets []string `mapstructure:"targets"`
+
+	// FallbackURL is a file:// URL of a directory holding the schema
+	// files, read when they can't be fetched from their schema URL.
+	// The schema file of https://example.com/schemas/1.0.0 is read from
+	// <directory>/example.com/schemas/1.0.0. (Optional field)
+	FallbackURL string `mapstructure:"fallback_url"`
 }
 
 func (c *Config) Validate() error {
@@ -60,6 +66,12 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("no schema targets defined: %w", errRequiresTargets)
 	}
 
+	if c.FallbackURL != "" {
+		if _, err := translation.NewFileProvider(c.FallbackURL); err != nil {
+			return err
+		}
+	}
+
 	families := make(map[string]struct{})
 	for _, target := range c.Targets {
 		family, _, err := translation.GetFamilyAndVersion(target)
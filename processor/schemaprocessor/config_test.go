@@ -97,3 +97,16 @@ func TestConfigurationValidation(t *testing.T) {
 		assert.ErrorIs(t, cfg.Validate(), tc.expectError, tc.scenario)
 	}
 }
+
+func TestConfigurationValidationFallbackURL(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{
+		Targets:     []string{"https://opentelemetry.io/schemas/1.9.0"},
+		FallbackURL: "file:///var/lib/otelcol/schemas",
+	}
+	assert.NoError(t, cfg.Validate(), "Must accept an absolute file url")
+
+	cfg.FallbackURL = "https://example.com/schemas"
+	assert.ErrorIs(t, cfg.Validate(), translation.ErrInvalidFileURL, "Must only accept file urls")
+}
@@ -73,6 +73,7 @@ func (f factory) createLogsProcessor(
 		transformer.processLogs,
 		processorhelper.WithCapabilities(processorCapabilities),
 		processorhelper.WithStart(transformer.start),
+		processorhelper.WithShutdown(transformer.shutdown),
 	)
 }
 
@@ -94,6 +95,7 @@ func (f factory) createMetricsProcessor(
 		transformer.processMetrics,
 		processorhelper.WithCapabilities(processorCapabilities),
 		processorhelper.WithStart(transformer.start),
+		processorhelper.WithShutdown(transformer.shutdown),
 	)
 }
 
@@ -115,5 +117,6 @@ func (f factory) createTracesProcessor(
 		transformer.processTraces,
 		processorhelper.WithCapabilities(processorCapabilities),
 		processorhelper.WithStart(transformer.start),
+		processorhelper.WithShutdown(transformer.shutdown),
 	)
 }
@@ -0,0 +1,263 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+var ErrUnsupportedVersion = errors.New("version not defined by the schema")
+
+const (
+	// minRetryDelay and maxRetryDelay bound the delay before a schema file
+	// that couldn't be fetched is requested again. The delay doubles after
+	// every consecutive failure.
+	minRetryDelay = 5 * time.Second
+	maxRetryDelay = 5 * time.Minute
+)
+
+// failure records why a schema file couldn't be fetched and until when
+// it must not be requested again.
+type failure struct {
+	err     error
+	delay   time.Duration
+	retryAt time.Time
+}
+
+// target is the version signals of a schema family are converted to.
+type target struct {
+	schemaURL string
+	version   *Version
+}
+
+// Manager converts signals to the target version of their schema family,
+// fetching and caching the schema files needed by the conversions.
+// It is safe for concurrent use.
+type Manager struct {
+	log      *zap.Logger
+	provider Provider
+	targets  map[string]target
+
+	rw           sync.RWMutex
+	translations map[string]*Translation
+	failures     map[string]failure
+	// pending holds the schema URLs being fetched, with a channel closed once the fetch is done
+	pending map[string]chan struct{}
+
+	now func() time.Time
+}
+
+// NewManager creates a Manager converting signals to the given target schema URLs.
+func NewManager(targets []string, provider Provider, log *zap.Logger) (*Manager, error) {
+	m := &Manager{
+		log:          log,
+		provider:     provider,
+		targets:      make(map[string]target, len(targets)),
+		translations: make(map[string]*Translation),
+		failures:     make(map[string]failure),
+		pending:      make(map[string]chan struct{}),
+		now:          time.Now,
+	}
+	for _, schemaURL := range targets {
+		family, version, err := GetFamilyAndVersion(schemaURL)
+		if err != nil {
+			return nil, err
+		}
+		m.targets[family] = target{schemaURL: schemaURL, version: version}
+	}
+	return m, nil
+}
+
+// Prefetch retrieves the schema files published at the given schema URLs, so
+// that they are already cached when needed to process signals.
+// It stops early once the context is done.
+func (m *Manager) Prefetch(ctx context.Context, schemaURLs ...string) {
+	for _, schemaURL := range schemaURLs {
+		if ctx.Err() != nil {
+			return
+		}
+		m.log.Info("Fetching schema url", zap.String("schema-url", schemaURL))
+		if _, err := m.translation(ctx, schemaURL); err != nil {
+			m.log.Warn("Failed to prefetch schema url", zap.String("schema-url", schemaURL), zap.Error(err))
+		}
+	}
+}
+
+// ApplyTraces converts the spans to the target version of their schema family.
+func (m *Manager) ApplyTraces(ctx context.Context, rs ptrace.ResourceSpans) error {
+	tr, from, to, err := m.resolve(ctx, rs.SchemaUrl())
+	if tr == nil {
+		return err
+	}
+	tr.ApplyTraces(rs, from, to.version, to.schemaURL)
+	return nil
+}
+
+// ApplyMetrics converts the metrics to the target version of their schema family.
+func (m *Manager) ApplyMetrics(ctx context.Context, rm pmetric.ResourceMetrics) error {
+	tr, from, to, err := m.resolve(ctx, rm.SchemaUrl())
+	if tr == nil {
+		return err
+	}
+	tr.ApplyMetrics(rm, from, to.version, to.schemaURL)
+	return nil
+}
+
+// ApplyLogs converts the log records to the target version of their schema family.
+func (m *Manager) ApplyLogs(ctx context.Context, rl plog.ResourceLogs) error {
+	tr, from, to, err := m.resolve(ctx, rl.SchemaUrl())
+	if tr == nil {
+		return err
+	}
+	tr.ApplyLogs(rl, from, to.version, to.schemaURL)
+	return nil
+}
+
+// resolve returns the translation converting signals published with the schema URL to the
+// target version of its family. No translation is returned if the signals are left unchanged.
+func (m *Manager) resolve(ctx context.Context, schemaURL string) (*Translation, *Version, target, error) {
+	if schemaURL == "" {
+		return nil, nil, target{}, nil
+	}
+	family, version, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		m.log.Debug("Ignoring invalid schema url", zap.String("schema-url", schemaURL), zap.Error(err))
+		return nil, nil, target{}, nil
+	}
+	to, ok := m.targets[family]
+	if !ok || version.Equal(to.version) {
+		return nil, nil, target{}, nil
+	}
+
+	// The schema file of a version defines all the versions preceding it,
+	// so the file of the most recent of both versions is needed.
+	fileURL := to.schemaURL
+	if version.GreaterThan(to.version) {
+		fileURL = schemaURL
+	}
+	tr, err := m.translation(ctx, fileURL)
+	if err != nil {
+		return nil, nil, target{}, err
+	}
+	for _, v := range []*Version{version, to.version} {
+		if !tr.SupportsVersion(v) {
+			return nil, nil, target{}, fmt.Errorf("version %s of %s: %w", v, fileURL, ErrUnsupportedVersion)
+		}
+	}
+	return tr, version, to, nil
+}
+
+// translation returns the translation defined by the schema file published at the
+// schema URL, fetching it if it isn't already cached. Concurrent callers share a single
+// fetch of the file. Once a fetch fails, the error is returned without fetching the file
+// again until its retry delay has passed.
+func (m *Manager) translation(ctx context.Context, schemaURL string) (*Translation, error) {
+	m.rw.RLock()
+	tr, ok := m.translations[schemaURL]
+	m.rw.RUnlock()
+	if ok {
+		return tr, nil
+	}
+
+	for {
+		m.rw.Lock()
+		if tr, ok = m.translations[schemaURL]; ok {
+			m.rw.Unlock()
+			return tr, nil
+		}
+		if failed, ok := m.failures[schemaURL]; ok && m.now().Before(failed.retryAt) {
+			m.rw.Unlock()
+			return nil, failed.err
+		}
+		done, inFlight := m.pending[schemaURL]
+		if !inFlight {
+			done = make(chan struct{})
+			m.pending[schemaURL] = done
+		}
+		m.rw.Unlock()
+
+		if !inFlight {
+			return m.fetchAndCache(ctx, schemaURL, done)
+		}
+
+		// Once the other fetch is done, its result is cached, unless it was
+		// cancelled by its caller, in which case the file is fetched again.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+		}
+	}
+}
+
+// fetchAndCache fetches the schema file, caches the translation or the failure,
+// and closes done to release the callers waiting for the fetch.
+func (m *Manager) fetchAndCache(ctx context.Context, schemaURL string, done chan struct{}) (*Translation, error) {
+	tr, err := m.fetch(ctx, schemaURL)
+
+	m.rw.Lock()
+	defer m.rw.Unlock()
+	delete(m.pending, schemaURL)
+	close(done)
+	if err != nil {
+		// A fetch cancelled by the caller says nothing about the schema file.
+		if ctx.Err() == nil {
+			m.recordFailure(schemaURL, err)
+		}
+		return nil, err
+	}
+	m.translations[schemaURL] = tr
+	delete(m.failures, schemaURL)
+	return tr, nil
+}
+
+func (m *Manager) fetch(ctx context.Context, schemaURL string) (*Translation, error) {
+	content, err := m.provider.Lookup(ctx, schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't fetch schema %s: %w", schemaURL, err)
+	}
+	schema, err := ParseSchema(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse schema %s: %w", schemaURL, err)
+	}
+	return NewTranslation(schema)
+}
+
+// recordFailure caches the error of a failed fetch and doubles the delay
+// before the schema file is requested again. Must be called with the lock held.
+func (m *Manager) recordFailure(schemaURL string, err error) {
+	delay := minRetryDelay
+	if previous, ok := m.failures[schemaURL]; ok {
+		delay = previous.delay * 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+	m.failures[schemaURL] = failure{
+		err:     err,
+		delay:   delay,
+		retryAt: m.now().Add(delay),
+	}
+}
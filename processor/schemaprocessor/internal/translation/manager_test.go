@@ -0,0 +1,301 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/fixture"
+)
+
+// newTestProvider serves the test schema at any schema URL and counts the lookups.
+func newTestProvider(t *testing.T, lookups *int64) Provider {
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to read test schema")
+	return providerFunc(func(_ context.Context, schemaURL string) ([]byte, error) {
+		atomic.AddInt64(lookups, 1)
+		return content, nil
+	})
+}
+
+func TestManagerInvalidTarget(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewManager([]string{"https://example.com/schemas/1"}, nil, zaptest.NewLogger(t))
+	assert.ErrorIs(t, err, ErrInvalidVersion)
+}
+
+func TestManagerApplyTraces(t *testing.T) {
+	t.Parallel()
+
+	var lookups int64
+	m, err := NewManager([]string{testSchemaURL}, newTestProvider(t, &lookups), zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	td := oldTraces()
+	require.NoError(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)))
+	assertEqualTraces(t, newTraces(), td)
+
+	// signals already at the target version, of another family, or without a
+	// valid schema URL are left unchanged.
+	for _, schemaURL := range []string{
+		testSchemaURL,
+		"https://opentelemetry.io/schemas/1.0.0",
+		"",
+		"https://example.com/schemas/latest",
+	} {
+		td = oldTraces()
+		td.ResourceSpans().At(0).SetSchemaUrl(schemaURL)
+		expected := oldTraces()
+		expected.ResourceSpans().At(0).SetSchemaUrl(schemaURL)
+
+		require.NoError(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)))
+		assert.Equal(t, expected, td, schemaURL)
+	}
+
+	assert.EqualValues(t, 1, atomic.LoadInt64(&lookups), "Must cache the schema")
+}
+
+func TestManagerRevertsUsingIncomingSchema(t *testing.T) {
+	t.Parallel()
+
+	var requested []string
+	content, err := os.ReadFile(filepath.Join("testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to read test schema")
+	provider := providerFunc(func(_ context.Context, schemaURL string) ([]byte, error) {
+		requested = append(requested, schemaURL)
+		return content, nil
+	})
+
+	m, err := NewManager([]string{testSchemaURLOldest}, provider, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	md := newMetrics()
+	require.NoError(t, m.ApplyMetrics(context.Background(), md.ResourceMetrics().At(0)))
+	assertEqualMetrics(t, oldMetrics(), md)
+
+	ld := newLogs()
+	require.NoError(t, m.ApplyLogs(context.Background(), ld.ResourceLogs().At(0)))
+	assert.Equal(t, oldLogs(), ld)
+
+	assert.Equal(t, []string{testSchemaURL}, requested, "Must fetch the schema of the most recent version once")
+}
+
+func TestManagerErrors(t *testing.T) {
+	t.Parallel()
+
+	failing := providerFunc(func(context.Context, string) ([]byte, error) {
+		return nil, assert.AnError
+	})
+	m, err := NewManager([]string{testSchemaURL}, failing, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	td := oldTraces()
+	assert.ErrorIs(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)), assert.AnError)
+	assert.Equal(t, oldTraces(), td, "Must leave the signal unchanged on error")
+
+	var lookups int64
+	m, err = NewManager([]string{"https://example.com/schemas/1.3.0"}, newTestProvider(t, &lookups), zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+	assert.ErrorIs(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)), ErrUnsupportedVersion)
+}
+
+func TestManagerCachesFailures(t *testing.T) {
+	t.Parallel()
+
+	var lookups int64
+	failing := providerFunc(func(context.Context, string) ([]byte, error) {
+		atomic.AddInt64(&lookups, 1)
+		return nil, assert.AnError
+	})
+	m, err := NewManager([]string{testSchemaURL}, failing, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+
+	apply := func() error {
+		td := oldTraces()
+		return m.ApplyTraces(context.Background(), td.ResourceSpans().At(0))
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, apply(), assert.AnError)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt64(&lookups), "Must not fetch again before the retry delay")
+
+	now = now.Add(minRetryDelay)
+	assert.ErrorIs(t, apply(), assert.AnError)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&lookups), "Must fetch again once the retry delay passed")
+
+	now = now.Add(minRetryDelay)
+	assert.ErrorIs(t, apply(), assert.AnError)
+	assert.EqualValues(t, 2, atomic.LoadInt64(&lookups), "Must double the retry delay after consecutive failures")
+
+	now = now.Add(minRetryDelay)
+	assert.ErrorIs(t, apply(), assert.AnError)
+	assert.EqualValues(t, 3, atomic.LoadInt64(&lookups))
+
+	for i := 0; i < 10; i++ {
+		now = now.Add(maxRetryDelay)
+		assert.ErrorIs(t, apply(), assert.AnError)
+	}
+	assert.EqualValues(t, 13, atomic.LoadInt64(&lookups), "Must not wait longer than the maximum retry delay")
+}
+
+func TestManagerRecoversAfterFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lookups int64
+		fail    int32 = 1
+	)
+	succeeding := newTestProvider(t, &lookups)
+	provider := providerFunc(func(ctx context.Context, schemaURL string) ([]byte, error) {
+		if atomic.LoadInt32(&fail) == 1 {
+			return nil, assert.AnError
+		}
+		return succeeding.Lookup(ctx, schemaURL)
+	})
+	m, err := NewManager([]string{testSchemaURL}, provider, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+	now := time.Unix(1000, 0)
+	m.now = func() time.Time { return now }
+
+	td := oldTraces()
+	assert.ErrorIs(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)), assert.AnError)
+
+	atomic.StoreInt32(&fail, 0)
+	now = now.Add(minRetryDelay)
+	require.NoError(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)))
+	assert.Equal(t, testSchemaURL, td.ResourceSpans().At(0).SchemaUrl())
+	assert.Empty(t, m.failures, "Must forget the failure once the schema is fetched")
+}
+
+func TestManagerPrefetchCancelled(t *testing.T) {
+	t.Parallel()
+
+	var lookups int64
+	m, err := NewManager([]string{testSchemaURL}, newTestProvider(t, &lookups), zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.Prefetch(ctx, testSchemaURL)
+	assert.EqualValues(t, 0, atomic.LoadInt64(&lookups), "Must not fetch once the context is done")
+	assert.Empty(t, m.failures, "Must not cache a cancelled fetch as a failure")
+}
+
+func TestManagerPrefetch(t *testing.T) {
+	t.Parallel()
+
+	var lookups int64
+	m, err := NewManager([]string{testSchemaURL}, newTestProvider(t, &lookups), zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	m.Prefetch(context.Background(), testSchemaURL)
+	require.EqualValues(t, 1, atomic.LoadInt64(&lookups))
+
+	td := oldTraces()
+	require.NoError(t, m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)))
+	assert.EqualValues(t, 1, atomic.LoadInt64(&lookups), "Must use the prefetched schema")
+}
+
+func TestManagerConcurrentApply(t *testing.T) {
+	t.Parallel()
+
+	var lookups int64
+	m, err := NewManager([]string{testSchemaURL}, newTestProvider(t, &lookups), zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	fixture.ParallelRaceCompute(t, 10, func() error {
+		td := oldTraces()
+		if err := m.ApplyTraces(context.Background(), td.ResourceSpans().At(0)); err != nil {
+			return err
+		}
+		if url := td.ResourceSpans().At(0).SchemaUrl(); url != testSchemaURL {
+			return fmt.Errorf("unexpected schema url %q", url)
+		}
+		return nil
+	})
+}
+
+func TestManagerSharesConcurrentFetches(t *testing.T) {
+	t.Parallel()
+
+	var lookups, calls int64
+	started := make(chan struct{})
+	release := make(chan struct{})
+	succeeding := newTestProvider(t, &lookups)
+	provider := providerFunc(func(ctx context.Context, schemaURL string) ([]byte, error) {
+		if atomic.AddInt64(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return succeeding.Lookup(ctx, schemaURL)
+	})
+	m, err := NewManager([]string{testSchemaURL}, provider, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	errs := make(chan error, 10)
+	for i := 0; i < cap(errs); i++ {
+		go func() {
+			td := oldTraces()
+			errs <- m.ApplyTraces(context.Background(), td.ResourceSpans().At(0))
+		}()
+	}
+	<-started
+	close(release)
+	for i := 0; i < cap(errs); i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt64(&lookups), "Must fetch the schema once for concurrent callers")
+	assert.Empty(t, m.pending)
+}
+
+func TestManagerWaitingCallerCancelled(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	provider := providerFunc(func(context.Context, string) ([]byte, error) {
+		close(started)
+		<-release
+		return nil, assert.AnError
+	})
+	m, err := NewManager([]string{testSchemaURL}, provider, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must not error when creating manager")
+
+	go func() {
+		td := oldTraces()
+		_ = m.ApplyTraces(context.Background(), td.ResourceSpans().At(0))
+	}()
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	td := oldTraces()
+	assert.ErrorIs(t, m.ApplyTraces(ctx, td.ResourceSpans().At(0)), context.Canceled, "Must stop waiting once the context is done")
+}
@@ -0,0 +1,118 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/multierr"
+)
+
+var ErrInvalidFileURL = errors.New("invalid file url")
+
+// Provider retrieves the content of the schema file published at a schema URL.
+type Provider interface {
+	Lookup(ctx context.Context, schemaURL string) ([]byte, error)
+}
+
+type httpProvider struct {
+	client *http.Client
+}
+
+var _ Provider = (*httpProvider)(nil)
+
+// NewHTTPProvider creates a Provider downloading the schema files using the given client.
+func NewHTTPProvider(client *http.Client) Provider {
+	return &httpProvider{client: client}
+}
+
+func (hp *httpProvider) Lookup(ctx context.Context, schemaURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q fetching %s", resp.Status, schemaURL)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+type fileProvider struct {
+	dir string
+}
+
+var _ Provider = (*fileProvider)(nil)
+
+// NewFileProvider creates a Provider reading the schema files from the directory
+// referenced by the given file:// URL. The schema file published at
+// https://example.com/schemas/1.0.0 is read from <directory>/example.com/schemas/1.0.0.
+func NewFileProvider(fileURL string) (Provider, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "file" {
+		return nil, fmt.Errorf("must use file: %w", ErrInvalidFileURL)
+	}
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("must not have a host name: %w", ErrInvalidFileURL)
+	}
+	return &fileProvider{dir: filepath.FromSlash(u.Path)}, nil
+}
+
+func (fp *fileProvider) Lookup(_ context.Context, schemaURL string) ([]byte, error) {
+	u, err := url.Parse(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(fp.dir, u.Host, filepath.FromSlash(u.Path)))
+}
+
+type fallbackProvider struct {
+	providers []Provider
+}
+
+var _ Provider = (*fallbackProvider)(nil)
+
+// NewFallbackProvider creates a Provider looking up the schema files with
+// each of the given providers in order, until one of them succeeds.
+func NewFallbackProvider(providers ...Provider) Provider {
+	return &fallbackProvider{providers: providers}
+}
+
+func (fp *fallbackProvider) Lookup(ctx context.Context, schemaURL string) ([]byte, error) {
+	var errs error
+	for _, p := range fp.providers {
+		content, err := p.Lookup(ctx, schemaURL)
+		if err == nil {
+			return content, nil
+		}
+		errs = multierr.Append(errs, err)
+	}
+	return nil, errs
+}
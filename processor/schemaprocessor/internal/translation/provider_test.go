@@ -0,0 +1,105 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(wr http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/1.0.0" {
+			wr.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := wr.Write([]byte("file_format: 1.0.0"))
+		assert.NoError(t, err, "Must not have issues writing schema content")
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewHTTPProvider(server.Client())
+
+	content, err := p.Lookup(context.Background(), server.URL+"/schemas/1.0.0")
+	require.NoError(t, err, "Must not error when fetching an existing schema")
+	assert.Equal(t, []byte("file_format: 1.0.0"), content)
+
+	_, err = p.Lookup(context.Background(), server.URL+"/schemas/2.0.0")
+	assert.Error(t, err, "Must error when the schema doesn't exist")
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "example.com", "schemas"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "example.com", "schemas", "1.0.0"), []byte("file_format: 1.0.0"), 0600))
+
+	p, err := NewFileProvider("file://" + filepath.ToSlash(dir))
+	require.NoError(t, err, "Must not error when creating a file provider")
+
+	content, err := p.Lookup(context.Background(), "https://example.com/schemas/1.0.0")
+	require.NoError(t, err, "Must not error when reading an existing schema")
+	assert.Equal(t, []byte("file_format: 1.0.0"), content)
+
+	_, err = p.Lookup(context.Background(), "https://example.com/schemas/2.0.0")
+	assert.Error(t, err, "Must error when the schema doesn't exist")
+}
+
+func TestNewFileProviderInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	for _, fileURL := range []string{
+		"https://example.com/schemas",
+		"file://example.com/schemas",
+		"/var/lib/schemas",
+	} {
+		_, err := NewFileProvider(fileURL)
+		assert.ErrorIs(t, err, ErrInvalidFileURL, fileURL)
+	}
+}
+
+func TestFallbackProvider(t *testing.T) {
+	t.Parallel()
+
+	failing := providerFunc(func(context.Context, string) ([]byte, error) {
+		return nil, assert.AnError
+	})
+	succeeding := providerFunc(func(context.Context, string) ([]byte, error) {
+		return []byte("file_format: 1.0.0"), nil
+	})
+
+	content, err := NewFallbackProvider(failing, succeeding).Lookup(context.Background(), testSchemaURL)
+	require.NoError(t, err, "Must not error when one of the providers succeeds")
+	assert.Equal(t, []byte("file_format: 1.0.0"), content)
+
+	_, err = NewFallbackProvider(failing, failing).Lookup(context.Background(), testSchemaURL)
+	assert.ErrorIs(t, err, assert.AnError, "Must error when all the providers fail")
+}
+
+type providerFunc func(ctx context.Context, schemaURL string) ([]byte, error)
+
+func (f providerFunc) Lookup(ctx context.Context, schemaURL string) ([]byte, error) {
+	return f(ctx, schemaURL)
+}
@@ -0,0 +1,140 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// supportedFileFormat is the only schema file format that can be read.
+const supportedFileFormat = "1.0.0"
+
+var ErrUnsupportedFileFormat = errors.New("unsupported schema file format")
+
+// Schema is the content of a schema file, as described by
+// https://opentelemetry.io/docs/reference/specification/schemas/file_format_v1.0.0/
+type Schema struct {
+	FileFormat string `yaml:"file_format"`
+	SchemaURL  string `yaml:"schema_url"`
+	// Versions holds the changes made by each version of the
+	// schema family, indexed by the version identifier.
+	Versions map[string]VersionDef `yaml:"versions"`
+}
+
+// VersionDef is the set of changes introduced by a schema version,
+// when converting from the previous version of the family.
+type VersionDef struct {
+	All        AttributeChanges `yaml:"all"`
+	Resources  AttributeChanges `yaml:"resources"`
+	Spans      SpanChanges      `yaml:"spans"`
+	SpanEvents SpanEventChanges `yaml:"span_events"`
+	Metrics    MetricChanges    `yaml:"metrics"`
+	Logs       LogRecordChanges `yaml:"logs"`
+}
+
+// AttributeChanges are the changes applied to attributes regardless of the
+// signal they belong to.
+type AttributeChanges struct {
+	Changes []struct {
+		RenameAttributes AttributeMap `yaml:"rename_attributes"`
+	} `yaml:"changes"`
+}
+
+// SpanChanges are the changes applied to spans.
+type SpanChanges struct {
+	Changes []struct {
+		RenameAttributes *struct {
+			AttributeMap AttributeMap `yaml:"attribute_map"`
+			ApplyToSpans []string     `yaml:"apply_to_spans"`
+		} `yaml:"rename_attributes"`
+	} `yaml:"changes"`
+}
+
+// SpanEventChanges are the changes applied to span events.
+type SpanEventChanges struct {
+	Changes []struct {
+		RenameEvents *struct {
+			NameMap map[string]string `yaml:"name_map"`
+		} `yaml:"rename_events"`
+		RenameAttributes *struct {
+			AttributeMap  AttributeMap `yaml:"attribute_map"`
+			ApplyToSpans  []string     `yaml:"apply_to_spans"`
+			ApplyToEvents []string     `yaml:"apply_to_events"`
+		} `yaml:"rename_attributes"`
+	} `yaml:"changes"`
+}
+
+// MetricChanges are the changes applied to metrics.
+type MetricChanges struct {
+	Changes []struct {
+		RenameMetrics    map[string]string `yaml:"rename_metrics"`
+		RenameAttributes *struct {
+			AttributeMap   AttributeMap `yaml:"attribute_map"`
+			ApplyToMetrics []string     `yaml:"apply_to_metrics"`
+		} `yaml:"rename_attributes"`
+	} `yaml:"changes"`
+}
+
+// LogRecordChanges are the changes applied to log records.
+type LogRecordChanges struct {
+	Changes []struct {
+		RenameAttributes *struct {
+			AttributeMap AttributeMap `yaml:"attribute_map"`
+		} `yaml:"rename_attributes"`
+	} `yaml:"changes"`
+}
+
+// AttributeMap maps the attribute names used by the previous version
+// to the attribute names used starting from the version defining it.
+type AttributeMap map[string]string
+
+// UnmarshalYAML accepts both the attribute names mapped directly, and
+// nested under an `attribute_map` key.
+func (am *AttributeMap) UnmarshalYAML(value *yaml.Node) error {
+	var direct map[string]string
+	if err := value.Decode(&direct); err == nil {
+		*am = direct
+		return nil
+	}
+	var nested struct {
+		AttributeMap map[string]string `yaml:"attribute_map"`
+	}
+	if err := value.Decode(&nested); err != nil {
+		return err
+	}
+	*am = nested.AttributeMap
+	return nil
+}
+
+// ParseSchema reads the content of a schema file.
+func ParseSchema(content io.Reader) (*Schema, error) {
+	schema := &Schema{}
+	if err := yaml.NewDecoder(content).Decode(schema); err != nil {
+		return nil, err
+	}
+	if schema.FileFormat != supportedFileFormat {
+		return nil, fmt.Errorf("file format %q: %w", schema.FileFormat, ErrUnsupportedFileFormat)
+	}
+	for v := range schema.Versions {
+		if _, err := NewVersion(v); err != nil {
+			return nil, fmt.Errorf("version %q: %w", v, err)
+		}
+	}
+	return schema, nil
+}
@@ -0,0 +1,85 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchema(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to open test schema")
+	defer f.Close()
+
+	schema, err := ParseSchema(f)
+	require.NoError(t, err, "Must not error when parsing a valid schema")
+	assert.Equal(t, "https://example.com/schemas/1.2.0", schema.SchemaURL)
+	assert.Len(t, schema.Versions, 3)
+	assert.Equal(t, AttributeMap{"k8s.pod.name": "kubernetes.pod.name"}, schema.Versions["1.1.0"].All.Changes[0].RenameAttributes)
+	assert.Equal(t, map[string]string{"cpu.usage": "system.cpu.usage"}, schema.Versions["1.2.0"].Metrics.Changes[0].RenameMetrics)
+}
+
+func TestParseSchemaWithDirectAttributeMap(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("..", "..", "testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to open test schema")
+	defer f.Close()
+
+	schema, err := ParseSchema(f)
+	require.NoError(t, err, "Must not error when parsing a valid schema")
+	assert.Equal(t, "kubernetes.pod.name", schema.Versions["1.1.0"].All.Changes[0].RenameAttributes["k8s.pod.name"])
+	assert.Equal(t, AttributeMap{"telemetry.auto.version": "telemetry.auto_instr.version"}, schema.Versions["1.1.0"].Resources.Changes[0].RenameAttributes)
+}
+
+func TestParseInvalidSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		scenario string
+		content  string
+		err      error
+	}{
+		{
+			scenario: "unsupported file format",
+			content:  "file_format: 2.0.0\nversions:\n  1.0.0:\n",
+			err:      ErrUnsupportedFileFormat,
+		},
+		{
+			scenario: "invalid version",
+			content:  "file_format: 1.0.0\nversions:\n  1.0:\n",
+			err:      ErrInvalidVersion,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.scenario, func(t *testing.T) {
+			schema, err := ParseSchema(strings.NewReader(tc.content))
+			assert.ErrorIs(t, err, tc.err, "MUST have the expected error")
+			assert.Nil(t, schema)
+		})
+	}
+
+	_, err := ParseSchema(strings.NewReader("versions: [not, a, map]"))
+	assert.Error(t, err, "MUST error on malformed schemas")
+}
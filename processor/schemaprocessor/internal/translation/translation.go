@@ -0,0 +1,318 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/alias"
+)
+
+// Translation applies the changes defined by a schema file to convert
+// signals from one version of the schema family to another.
+// It is safe for concurrent use, since it only reads the schema.
+type Translation struct {
+	// versions holds the versions defined by the schema, in ascending order.
+	versions []*Version
+	defs     map[Version]VersionDef
+}
+
+// step is the set of changes of a single version, applied in either direction.
+type step struct {
+	def    VersionDef
+	revert bool
+}
+
+// NewTranslation creates a Translation using the changes defined by the given schema.
+func NewTranslation(schema *Schema) (*Translation, error) {
+	t := &Translation{
+		defs: make(map[Version]VersionDef, len(schema.Versions)),
+	}
+	for id, def := range schema.Versions {
+		v, err := NewVersion(id)
+		if err != nil {
+			return nil, err
+		}
+		t.versions = append(t.versions, v)
+		t.defs[*v] = def
+	}
+	sort.Slice(t.versions, func(i, j int) bool {
+		return t.versions[i].LessThan(t.versions[j])
+	})
+	return t, nil
+}
+
+// SupportsVersion reports whether the version is defined by the schema.
+func (t *Translation) SupportsVersion(v *Version) bool {
+	_, ok := t.defs[*v]
+	return ok
+}
+
+// steps returns the changes to apply, in order, to convert signals
+// from one version to another.
+func (t *Translation) steps(from, to *Version) []step {
+	var steps []step
+	switch from.Compare(to) {
+	case Update:
+		for _, v := range t.versions {
+			if v.GreaterThan(from) && !v.GreaterThan(to) {
+				steps = append(steps, step{def: t.defs[*v]})
+			}
+		}
+	case Revert:
+		for i := len(t.versions) - 1; i >= 0; i-- {
+			v := t.versions[i]
+			if v.GreaterThan(to) && !v.GreaterThan(from) {
+				steps = append(steps, step{def: t.defs[*v], revert: true})
+			}
+		}
+	}
+	return steps
+}
+
+// ApplyTraces converts the spans from one version to another, updating the schema URL.
+func (t *Translation) ApplyTraces(rs ptrace.ResourceSpans, from, to *Version, schemaURL string) {
+	for _, s := range t.steps(from, to) {
+		s.applyResource(rs)
+		for i := 0; i < rs.ScopeSpans().Len(); i++ {
+			spans := rs.ScopeSpans().At(i).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				s.applySpan(spans.At(j))
+			}
+		}
+	}
+	rs.SetSchemaUrl(schemaURL)
+}
+
+// ApplyMetrics converts the metrics from one version to another, updating the schema URL.
+func (t *Translation) ApplyMetrics(rm pmetric.ResourceMetrics, from, to *Version, schemaURL string) {
+	for _, s := range t.steps(from, to) {
+		s.applyResource(rm)
+		for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+			metrics := rm.ScopeMetrics().At(i).Metrics()
+			for j := 0; j < metrics.Len(); j++ {
+				s.applyMetric(metrics.At(j))
+			}
+		}
+	}
+	rm.SetSchemaUrl(schemaURL)
+}
+
+// ApplyLogs converts the log records from one version to another, updating the schema URL.
+func (t *Translation) ApplyLogs(rl plog.ResourceLogs, from, to *Version, schemaURL string) {
+	for _, s := range t.steps(from, to) {
+		s.applyResource(rl)
+		for i := 0; i < rl.ScopeLogs().Len(); i++ {
+			logs := rl.ScopeLogs().At(i).LogRecords()
+			for j := 0; j < logs.Len(); j++ {
+				s.applyLogRecord(logs.At(j))
+			}
+		}
+	}
+	rl.SetSchemaUrl(schemaURL)
+}
+
+func (s step) applyResource(r alias.Resource) {
+	attrs := r.Resource().Attributes()
+	s.sections(func() {
+		s.each(len(s.def.All.Changes), func(i int) {
+			s.rename(attrs, s.def.All.Changes[i].RenameAttributes)
+		})
+	}, func() {
+		s.each(len(s.def.Resources.Changes), func(i int) {
+			s.rename(attrs, s.def.Resources.Changes[i].RenameAttributes)
+		})
+	})
+}
+
+func (s step) applySpan(span ptrace.Span) {
+	s.sections(func() {
+		s.each(len(s.def.All.Changes), func(i int) {
+			s.rename(span.Attributes(), s.def.All.Changes[i].RenameAttributes)
+		})
+	}, func() {
+		s.each(len(s.def.Spans.Changes), func(i int) {
+			change := s.def.Spans.Changes[i].RenameAttributes
+			if change != nil && appliesTo(span.Name(), change.ApplyToSpans) {
+				s.rename(span.Attributes(), change.AttributeMap)
+			}
+		})
+	})
+
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		s.sections(func() {
+			s.each(len(s.def.All.Changes), func(j int) {
+				s.rename(event.Attributes(), s.def.All.Changes[j].RenameAttributes)
+			})
+		}, func() {
+			s.each(len(s.def.SpanEvents.Changes), func(j int) {
+				change := s.def.SpanEvents.Changes[j]
+				if change.RenameEvents != nil {
+					s.renameSignal(event, change.RenameEvents.NameMap)
+				}
+				if attrs := change.RenameAttributes; attrs != nil &&
+					appliesTo(span.Name(), attrs.ApplyToSpans) && appliesTo(event.Name(), attrs.ApplyToEvents) {
+					s.rename(event.Attributes(), attrs.AttributeMap)
+				}
+			})
+		})
+	}
+}
+
+func (s step) applyMetric(metric pmetric.Metric) {
+	s.sections(func() {
+		s.each(len(s.def.All.Changes), func(i int) {
+			forEachDataPointAttributes(metric, func(attrs pcommon.Map) {
+				s.rename(attrs, s.def.All.Changes[i].RenameAttributes)
+			})
+		})
+	}, func() {
+		s.each(len(s.def.Metrics.Changes), func(i int) {
+			change := s.def.Metrics.Changes[i]
+			if change.RenameMetrics != nil {
+				s.renameSignal(metric, change.RenameMetrics)
+			}
+			if attrs := change.RenameAttributes; attrs != nil && appliesTo(metric.Name(), attrs.ApplyToMetrics) {
+				forEachDataPointAttributes(metric, func(m pcommon.Map) {
+					s.rename(m, attrs.AttributeMap)
+				})
+			}
+		})
+	})
+}
+
+func (s step) applyLogRecord(log plog.LogRecord) {
+	s.sections(func() {
+		s.each(len(s.def.All.Changes), func(i int) {
+			s.rename(log.Attributes(), s.def.All.Changes[i].RenameAttributes)
+		})
+	}, func() {
+		s.each(len(s.def.Logs.Changes), func(i int) {
+			if change := s.def.Logs.Changes[i].RenameAttributes; change != nil {
+				s.rename(log.Attributes(), change.AttributeMap)
+			}
+		})
+	})
+}
+
+// sections calls the functions applying each section of the version, in the
+// order they need to be applied.
+func (s step) sections(fns ...func()) {
+	s.each(len(fns), func(i int) {
+		fns[i]()
+	})
+}
+
+// each calls fn with the index of each of the n changes of a section, in the
+// order they need to be applied: reverted changes are undone from the last one.
+func (s step) each(n int, fn func(i int)) {
+	if s.revert {
+		for i := n - 1; i >= 0; i-- {
+			fn(i)
+		}
+		return
+	}
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+}
+
+// rename renames the attributes using the names mapping, in the direction of the step.
+func (s step) rename(attrs pcommon.Map, names map[string]string) {
+	if len(names) == 0 {
+		return
+	}
+	renamed := make(map[string]pcommon.Value, len(names))
+	for from, to := range names {
+		if s.revert {
+			from, to = to, from
+		}
+		v, ok := attrs.Get(from)
+		if !ok {
+			continue
+		}
+		value := pcommon.NewValueEmpty()
+		v.CopyTo(value)
+		renamed[to] = value
+		attrs.Remove(from)
+	}
+	for name, value := range renamed {
+		attrs.Upsert(name, value)
+	}
+}
+
+// renameSignal renames the signal using the names mapping, in the direction of the step.
+func (s step) renameSignal(signal alias.Signal, names map[string]string) {
+	for from, to := range names {
+		if s.revert {
+			from, to = to, from
+		}
+		if signal.Name() == from {
+			signal.SetName(to)
+			return
+		}
+	}
+}
+
+// appliesTo reports whether a change restricted to the given names applies
+// to the signal, changes without any restriction apply to all signals.
+func appliesTo(name string, names []string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func forEachDataPointAttributes(metric pmetric.Metric, fn func(attrs pcommon.Map)) {
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	}
+}
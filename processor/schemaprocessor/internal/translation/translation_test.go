@@ -0,0 +1,264 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	testSchemaURL       = "https://example.com/schemas/1.2.0"
+	testSchemaURLOldest = "https://example.com/schemas/1.0.0"
+)
+
+func newTestTranslation(t *testing.T) *Translation {
+	f, err := os.Open(filepath.Join("testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to open test schema")
+	defer f.Close()
+
+	schema, err := ParseSchema(f)
+	require.NoError(t, err, "Must not error when parsing test schema")
+	tr, err := NewTranslation(schema)
+	require.NoError(t, err, "Must not error when creating translation")
+	return tr
+}
+
+func mustVersion(t *testing.T, s string) *Version {
+	v, err := NewVersion(s)
+	require.NoError(t, err)
+	return v
+}
+
+// oldTraces returns spans using the names of version 1.0.0
+func oldTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl(testSchemaURLOldest)
+	rs.Resource().Attributes().InsertString("telemetry.auto.version", "1.0")
+	rs.Resource().Attributes().InsertString("k8s.pod.name", "pod")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	get := spans.AppendEmpty()
+	get.SetName("HTTP GET")
+	get.Attributes().InsertInt("http.status", 200)
+	get.Attributes().InsertString("k8s.pod.name", "pod")
+	event := get.Events().AppendEmpty()
+	event.SetName("stacktrace")
+	event.Attributes().InsertString("peer.service", "db")
+
+	post := spans.AppendEmpty()
+	post.SetName("HTTP POST")
+	post.Attributes().InsertInt("http.status", 500)
+	return td
+}
+
+// newTraces returns the spans of oldTraces using the names of version 1.2.0
+func newTraces() ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.SetSchemaUrl(testSchemaURL)
+	rs.Resource().Attributes().InsertString("kubernetes.pod.name", "pod")
+	rs.Resource().Attributes().InsertString("telemetry.auto_instr.version", "1.0")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	get := spans.AppendEmpty()
+	get.SetName("HTTP GET")
+	get.Attributes().InsertString("kubernetes.pod.name", "pod")
+	get.Attributes().InsertInt("http.status_code", 200)
+	event := get.Events().AppendEmpty()
+	event.SetName("stack_trace")
+	event.Attributes().InsertString("peer.service.name", "db")
+
+	post := spans.AppendEmpty()
+	post.SetName("HTTP POST")
+	post.Attributes().InsertInt("http.status", 500)
+	return td
+}
+
+func oldMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl(testSchemaURLOldest)
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	cpu := metrics.AppendEmpty()
+	cpu.SetName("cpu.usage")
+	cpu.SetDataType(pmetric.MetricDataTypeSum)
+	dp := cpu.Sum().DataPoints().AppendEmpty()
+	dp.Attributes().InsertString("status", "idle")
+	dp.Attributes().InsertString("k8s.pod.name", "pod")
+
+	memory := metrics.AppendEmpty()
+	memory.SetName("memory.usage")
+	memory.SetDataType(pmetric.MetricDataTypeGauge)
+	memory.Gauge().DataPoints().AppendEmpty().Attributes().InsertString("status", "free")
+	return md
+}
+
+func newMetrics() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl(testSchemaURL)
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	cpu := metrics.AppendEmpty()
+	cpu.SetName("system.cpu.usage")
+	cpu.SetDataType(pmetric.MetricDataTypeSum)
+	dp := cpu.Sum().DataPoints().AppendEmpty()
+	dp.Attributes().InsertString("kubernetes.pod.name", "pod")
+	dp.Attributes().InsertString("state", "idle")
+
+	memory := metrics.AppendEmpty()
+	memory.SetName("memory.usage")
+	memory.SetDataType(pmetric.MetricDataTypeGauge)
+	memory.Gauge().DataPoints().AppendEmpty().Attributes().InsertString("status", "free")
+	return md
+}
+
+func oldLogs() plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.SetSchemaUrl(testSchemaURLOldest)
+	log := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	log.Attributes().InsertString("process.executable_name", "otelcol")
+	return ld
+}
+
+func newLogs() plog.Logs {
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.SetSchemaUrl(testSchemaURL)
+	log := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	log.Attributes().InsertString("process.executable.name", "otelcol")
+	return ld
+}
+
+func TestTranslationSupportsVersion(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTranslation(t)
+	assert.True(t, tr.SupportsVersion(mustVersion(t, "1.0.0")))
+	assert.True(t, tr.SupportsVersion(mustVersion(t, "1.2.0")))
+	assert.False(t, tr.SupportsVersion(mustVersion(t, "1.3.0")))
+}
+
+func TestTranslationTraces(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTranslation(t)
+	oldest, latest := mustVersion(t, "1.0.0"), mustVersion(t, "1.2.0")
+
+	t.Run("update", func(t *testing.T) {
+		td := oldTraces()
+		tr.ApplyTraces(td.ResourceSpans().At(0), oldest, latest, testSchemaURL)
+		assertEqualTraces(t, newTraces(), td)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		td := newTraces()
+		tr.ApplyTraces(td.ResourceSpans().At(0), latest, oldest, testSchemaURLOldest)
+		assertEqualTraces(t, oldTraces(), td)
+	})
+}
+
+func TestTranslationMetrics(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTranslation(t)
+	oldest, latest := mustVersion(t, "1.0.0"), mustVersion(t, "1.2.0")
+
+	t.Run("update", func(t *testing.T) {
+		md := oldMetrics()
+		tr.ApplyMetrics(md.ResourceMetrics().At(0), oldest, latest, testSchemaURL)
+		assertEqualMetrics(t, newMetrics(), md)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		md := newMetrics()
+		tr.ApplyMetrics(md.ResourceMetrics().At(0), latest, oldest, testSchemaURLOldest)
+		assertEqualMetrics(t, oldMetrics(), md)
+	})
+
+	t.Run("partial update", func(t *testing.T) {
+		md := oldMetrics()
+		tr.ApplyMetrics(md.ResourceMetrics().At(0), oldest, mustVersion(t, "1.1.0"), "https://example.com/schemas/1.1.0")
+		rm := md.ResourceMetrics().At(0)
+		assert.Equal(t, "https://example.com/schemas/1.1.0", rm.SchemaUrl())
+		cpu := rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "cpu.usage", cpu.Name(), "Must not apply the changes of later versions")
+		_, ok := cpu.Sum().DataPoints().At(0).Attributes().Get("kubernetes.pod.name")
+		assert.True(t, ok, "Must apply the changes of version 1.1.0")
+	})
+}
+
+func TestTranslationLogs(t *testing.T) {
+	t.Parallel()
+
+	tr := newTestTranslation(t)
+	oldest, latest := mustVersion(t, "1.0.0"), mustVersion(t, "1.2.0")
+
+	t.Run("update", func(t *testing.T) {
+		ld := oldLogs()
+		tr.ApplyLogs(ld.ResourceLogs().At(0), oldest, latest, testSchemaURL)
+		assert.Equal(t, newLogs(), ld)
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		ld := newLogs()
+		tr.ApplyLogs(ld.ResourceLogs().At(0), latest, oldest, testSchemaURLOldest)
+		assert.Equal(t, oldLogs(), ld)
+	})
+}
+
+// assertEqualTraces compares the traces ignoring the order of the attributes
+func assertEqualTraces(t *testing.T, expected, actual ptrace.Traces) {
+	sortTracesAttributes(expected)
+	sortTracesAttributes(actual)
+	assert.Equal(t, expected, actual)
+}
+
+func sortTracesAttributes(td ptrace.Traces) {
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().Sort()
+	spans := rs.ScopeSpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		spans.At(i).Attributes().Sort()
+		for j := 0; j < spans.At(i).Events().Len(); j++ {
+			spans.At(i).Events().At(j).Attributes().Sort()
+		}
+	}
+}
+
+// assertEqualMetrics compares the metrics ignoring the order of the attributes
+func assertEqualMetrics(t *testing.T, expected, actual pmetric.Metrics) {
+	for _, md := range []pmetric.Metrics{expected, actual} {
+		metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			forEachDataPointAttributes(metrics.At(i), func(attrs pcommon.Map) {
+				attrs.Sort()
+			})
+		}
+	}
+	assert.Equal(t, expected, actual)
+}
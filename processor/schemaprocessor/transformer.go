@@ -17,6 +17,7 @@ package schemaprocessor // import "github.com/open-telemetry/opentelemetry-colle
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -24,11 +25,23 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
 )
 
 type transformer struct {
-	targets []string
-	log     *zap.Logger
+	config   *Config
+	targets  []string
+	log      *zap.Logger
+	settings component.TelemetrySettings
+
+	// manager is only set once the transformer is started,
+	// signals are left unchanged until then.
+	manager *translation.Manager
+
+	// cancel stops the schema files being prefetched in the background.
+	cancel   context.CancelFunc
+	prefetch sync.WaitGroup
 }
 
 func newTransformer(
@@ -41,28 +54,90 @@ func newTransformer(
 		return nil, errors.New("invalid configuration provided")
 	}
 	return &transformer{
-		log:     set.Logger,
-		targets: cfg.Targets,
+		config:   cfg,
+		log:      set.Logger,
+		settings: set.TelemetrySettings,
+		targets:  cfg.Targets,
 	}, nil
 }
 
-func (t transformer) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+func (t *transformer) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	if t.manager == nil {
+		return ld, nil
+	}
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		if err := t.manager.ApplyLogs(ctx, rl); err != nil {
+			t.log.Warn("Failed to translate logs", zap.String("schema-url", rl.SchemaUrl()), zap.Error(err))
+		}
+	}
 	return ld, nil
 }
 
-func (t transformer) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+func (t *transformer) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	if t.manager == nil {
+		return md, nil
+	}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		if err := t.manager.ApplyMetrics(ctx, rm); err != nil {
+			t.log.Warn("Failed to translate metrics", zap.String("schema-url", rm.SchemaUrl()), zap.Error(err))
+		}
+	}
 	return md, nil
 }
 
-func (t transformer) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+func (t *transformer) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	if t.manager == nil {
+		return td, nil
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		if err := t.manager.ApplyTraces(ctx, rs); err != nil {
+			t.log.Warn("Failed to translate traces", zap.String("schema-url", rs.SchemaUrl()), zap.Error(err))
+		}
+	}
 	return td, nil
 }
 
-// start will load the remote file definition if it isn't already cached
-// and resolve the schema translation file
-func (t *transformer) start(ctx context.Context, host component.Host) error {
-	for _, target := range t.targets {
-		t.log.Info("Fetching remote schema url", zap.String("schema-url", target))
+// start creates the translation manager and starts fetching the schema
+// files to prefetch in the background, so that the collector start
+// isn't held up by the network.
+func (t *transformer) start(_ context.Context, host component.Host) error {
+	client, err := t.config.HTTPClientSettings.ToClient(host, t.settings)
+	if err != nil {
+		return err
+	}
+	provider := translation.NewHTTPProvider(client)
+	if t.config.FallbackURL != "" {
+		fallback, err := translation.NewFileProvider(t.config.FallbackURL)
+		if err != nil {
+			return err
+		}
+		provider = translation.NewFallbackProvider(provider, fallback)
+	}
+
+	manager, err := translation.NewManager(t.targets, provider, t.log)
+	if err != nil {
+		return err
+	}
+	schemaURLs := append(append([]string{}, t.config.Prefetch...), t.targets...)
+	var prefetchCtx context.Context
+	prefetchCtx, t.cancel = context.WithCancel(context.Background())
+	t.prefetch.Add(1)
+	go func() {
+		defer t.prefetch.Done()
+		manager.Prefetch(prefetchCtx, schemaURLs...)
+	}()
+	t.manager = manager
+	return nil
+}
+
+// shutdown stops the schema files still being prefetched.
+func (t *transformer) shutdown(context.Context) error {
+	if t.cancel != nil {
+		t.cancel()
 	}
+	t.prefetch.Wait()
 	return nil
 }
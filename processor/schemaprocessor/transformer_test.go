@@ -18,11 +18,17 @@ import (
 	"context"
 	_ "embed"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -110,3 +116,141 @@ func TestTransformerProcessing(t *testing.T) {
 		assert.Equal(t, in, out, "Must return the same data (subject to change)")
 	})
 }
+
+func newStartedTransformer(t *testing.T, cfg *Config) *transformer {
+	trans, err := newTransformer(context.Background(), cfg, component.ProcessorCreateSettings{
+		TelemetrySettings: component.TelemetrySettings{
+			Logger: zaptest.NewLogger(t),
+		},
+	})
+	require.NoError(t, err, "Must not error when creating transformer")
+	require.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()), "Must not error when starting transformer")
+	t.Cleanup(func() {
+		assert.NoError(t, trans.shutdown(context.Background()), "Must not error when shutting down transformer")
+	})
+	return trans
+}
+
+func TestTransformerStartDoesNotWaitForPrefetch(t *testing.T) {
+	t.Parallel()
+
+	requested := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(wr http.ResponseWriter, r *http.Request) {
+		select {
+		case requested <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Targets = []string{server.URL + "/schemas/1.0.0"}
+	trans, err := newTransformer(context.Background(), cfg, component.ProcessorCreateSettings{
+		TelemetrySettings: component.TelemetrySettings{
+			Logger: zaptest.NewLogger(t),
+		},
+	})
+	require.NoError(t, err, "Must not error when creating transformer")
+	require.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()), "Must not wait for the schema files")
+
+	select {
+	case <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Must prefetch the schema files in the background")
+	}
+	assert.NoError(t, trans.shutdown(context.Background()), "Must stop the pending prefetch on shutdown")
+}
+
+func TestTransformerTranslatesSignals(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(SchemaHandler(t)))
+	t.Cleanup(server.Close)
+
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Targets = []string{server.URL + "/schemas/1.0.0"}
+	trans := newStartedTransformer(t, cfg)
+
+	t.Run("metrics", func(t *testing.T) {
+		in := pmetric.NewMetrics()
+		rm := in.ResourceMetrics().AppendEmpty()
+		rm.SetSchemaUrl(server.URL + "/schemas/1.1.0")
+		rm.Resource().Attributes().InsertString("kubernetes.pod.name", "pod-0")
+
+		out, err := trans.processMetrics(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing metrics")
+		assert.Equal(t, server.URL+"/schemas/1.0.0", out.ResourceMetrics().At(0).SchemaUrl())
+		assert.Equal(t, map[string]interface{}{"k8s.pod.name": "pod-0"}, out.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		in := ptrace.NewTraces()
+		rs := in.ResourceSpans().AppendEmpty()
+		rs.SetSchemaUrl(server.URL + "/schemas/1.1.0")
+		s := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		s.SetName("http.request")
+		s.Attributes().InsertString("kubernetes.pod.name", "pod-0")
+
+		out, err := trans.processTraces(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing traces")
+		assert.Equal(t, server.URL+"/schemas/1.0.0", out.ResourceSpans().At(0).SchemaUrl())
+		span := out.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+		assert.Equal(t, map[string]interface{}{"k8s.pod.name": "pod-0"}, span.Attributes().AsRaw())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl(server.URL + "/schemas/1.1.0")
+		l := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		l.Attributes().InsertString("kubernetes.pod.name", "pod-0")
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		assert.Equal(t, server.URL+"/schemas/1.0.0", out.ResourceLogs().At(0).SchemaUrl())
+		record := out.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		assert.Equal(t, map[string]interface{}{"k8s.pod.name": "pod-0"}, record.Attributes().AsRaw())
+	})
+
+	t.Run("other family", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl("https://example.com/schemas/1.1.0")
+		rl.Resource().Attributes().InsertString("kubernetes.pod.name", "pod-0")
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		assert.Equal(t, "https://example.com/schemas/1.1.0", out.ResourceLogs().At(0).SchemaUrl())
+		assert.Equal(t, map[string]interface{}{"kubernetes.pod.name": "pod-0"}, out.ResourceLogs().At(0).Resource().Attributes().AsRaw())
+	})
+}
+
+func TestTransformerFallbackURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err, "Must be able to parse server url")
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, u.Host, "schemas"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, u.Host, "schemas", "1.1.0"), schemaContent, 0600))
+
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Targets = []string{server.URL + "/schemas/1.1.0"}
+	cfg.FallbackURL = "file://" + filepath.ToSlash(dir)
+	trans := newStartedTransformer(t, cfg)
+
+	in := pmetric.NewMetrics()
+	rm := in.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl(server.URL + "/schemas/1.0.0")
+	rm.Resource().Attributes().InsertString("k8s.pod.name", "pod-0")
+
+	out, err := trans.processMetrics(context.Background(), in)
+	require.NoError(t, err, "Must not error when processing metrics")
+	assert.Equal(t, server.URL+"/schemas/1.1.0", out.ResourceMetrics().At(0).SchemaUrl())
+	assert.Equal(t, map[string]interface{}{"kubernetes.pod.name": "pod-0"}, out.ResourceMetrics().At(0).Resource().Attributes().AsRaw())
+}
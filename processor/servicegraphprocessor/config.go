@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor"
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+// Config defines the configuration options for servicegraphprocessor.
+type Config struct {
+	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// MetricsExporter is the name of the metrics exporter to use to ship metrics.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets is the list of durations representing latency histogram buckets.
+	// See defaultLatencyHistogramBucketsSec in processor.go for the default value.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Dimensions defines the list of additional dimensions on top of the client and server services.
+	// They are fetched from the attributes of the client and server spans, or of their resources,
+	// and added as the client_<name> and server_<name> dimensions respectively.
+	Dimensions []string `mapstructure:"dimensions"`
+
+	// DimensionsCacheSize is the maximum number of series, i.e. of combinations of client, server and dimensions.
+	// Once it's reached, the least recently updated series is dropped from the metrics to make room for a new one.
+	DimensionsCacheSize int `mapstructure:"dimensions_cache_size"`
+
+	// Store configures the in-memory store of the edges waiting for their client or server span.
+	Store StoreConfig `mapstructure:"store"`
+}
+
+// StoreConfig defines the bounds of the edges store.
+type StoreConfig struct {
+	// MaxItems is the maximum number of pending edges; the spans of new edges are dropped while it's reached.
+	MaxItems int `mapstructure:"max_items"`
+
+	// TTL is how long an edge waits for its client or server span before being expired.
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+var _ config.Processor = (*Config)(nil)
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.MetricsExporter == "" {
+		return errors.New("metrics_exporter must be configured")
+	}
+	if cfg.DimensionsCacheSize <= 0 {
+		return errors.New("dimensions_cache_size must be positive")
+	}
+	if cfg.Store.MaxItems <= 0 {
+		return errors.New("store max_items must be positive")
+	}
+	if cfg.Store.TTL <= 0 {
+		return errors.New("store ttl must be positive")
+	}
+	return nil
+}
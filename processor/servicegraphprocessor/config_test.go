@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id       config.ComponentID
+		expected config.Processor
+	}{
+		{
+			id: config.NewComponentIDWithName(typeStr, ""),
+			expected: &Config{
+				ProcessorSettings:       config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				MetricsExporter:         "prometheus",
+				LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+				Dimensions:              []string{"k8s.cluster.name"},
+				DimensionsCacheSize:     100,
+				Store: StoreConfig{
+					MaxItems: 500,
+					TTL:      5 * time.Second,
+				},
+			},
+		},
+		{
+			id: config.NewComponentIDWithName(typeStr, "defaults"),
+			expected: &Config{
+				ProcessorSettings:   config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				MetricsExporter:     "prometheus",
+				DimensionsCacheSize: defaultDimensionsCacheSize,
+				Store: StoreConfig{
+					MaxItems: defaultStoreMaxItems,
+					TTL:      defaultStoreTTL,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, config.UnmarshalProcessor(sub, cfg))
+
+			assert.NoError(t, cfg.Validate())
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:    "missing metrics exporter",
+			modify:  func(cfg *Config) { cfg.MetricsExporter = "" },
+			wantErr: "metrics_exporter must be configured",
+		},
+		{
+			name:    "no dimensions cache",
+			modify:  func(cfg *Config) { cfg.DimensionsCacheSize = 0 },
+			wantErr: "dimensions_cache_size must be positive",
+		},
+		{
+			name:    "no store items",
+			modify:  func(cfg *Config) { cfg.Store.MaxItems = 0 },
+			wantErr: "store max_items must be positive",
+		},
+		{
+			name:    "no store ttl",
+			modify:  func(cfg *Config) { cfg.Store.TTL = 0 },
+			wantErr: "store ttl must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.MetricsExporter = "prometheus"
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor"
+
+import (
+	"context"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "servicegraph"
+	// The stability level of the processor.
+	stability = component.StabilityLevelInDevelopment
+
+	defaultDimensionsCacheSize = 1000
+	defaultStoreMaxItems       = 1000
+	defaultStoreTTL            = 2 * time.Second
+)
+
+// NewFactory creates a factory for the servicegraph processor.
+func NewFactory() component.ProcessorFactory {
+	// TODO: find a more appropriate way to get this done, as we are swallowing the error here
+	_ = view.Register(MetricViews()...)
+
+	return component.NewProcessorFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithTracesProcessor(createTracesProcessor, stability),
+	)
+}
+
+func createDefaultConfig() config.Processor {
+	return &Config{
+		ProcessorSettings:   config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		DimensionsCacheSize: defaultDimensionsCacheSize,
+		Store: StoreConfig{
+			MaxItems: defaultStoreMaxItems,
+			TTL:      defaultStoreTTL,
+		},
+	}
+}
+
+func createTracesProcessor(_ context.Context, params component.ProcessorCreateSettings, cfg config.Processor, nextConsumer consumer.Traces) (component.TracesProcessor, error) {
+	return newProcessor(params.Logger, cfg, nextConsumer)
+}
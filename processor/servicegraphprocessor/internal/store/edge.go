@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor/internal/store"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// Key identifies an edge: the trace ID and the span ID of the client span,
+// which is also the parent span ID of the server span.
+type Key struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+}
+
+// NewKey builds the key of the edge of the given trace and span IDs.
+func NewKey(traceID pcommon.TraceID, spanID pcommon.SpanID) Key {
+	return Key{traceID: traceID, spanID: spanID}
+}
+
+// Edge is a request between a client and a server service, completed when both of its spans were received.
+type Edge struct {
+	key Key
+
+	TraceID pcommon.TraceID
+
+	ClientService, ServerService       string
+	ClientLatencySec, ServerLatencySec float64
+
+	// Failed is set when either of the spans has an error status.
+	Failed bool
+
+	// Dimensions holds the additional dimensions of the edge, fetched from either span.
+	Dimensions map[string]string
+
+	expiration time.Time
+}
+
+func newEdge(key Key, ttl time.Duration) *Edge {
+	return &Edge{
+		key:        key,
+		TraceID:    key.traceID,
+		Dimensions: make(map[string]string),
+		expiration: time.Now().Add(ttl),
+	}
+}
+
+// isComplete returns true if the client and the server spans of the edge were both received.
+func (e *Edge) isComplete() bool {
+	return e.ClientService != "" && e.ServerService != ""
+}
+
+func (e *Edge) isExpired() bool {
+	return time.Now().After(e.expiration)
+}
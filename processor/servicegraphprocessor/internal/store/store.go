@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor/internal/store"
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTooManyItems is returned when an edge can't be added because the store is full.
+var ErrTooManyItems = errors.New("too many items")
+
+// Callback is called with the edges of the store.
+type Callback func(e *Edge)
+
+// Store holds the pending edges, until both of their spans are received or they expire.
+// Edges are kept in insertion order, so that the oldest ones expire first.
+type Store struct {
+	l   *list.List
+	mtx sync.Mutex
+	m   map[Key]*list.Element
+
+	onComplete Callback
+	onExpire   Callback
+
+	ttl      time.Duration
+	maxItems int
+}
+
+// NewStore creates a Store holding up to maxItems pending edges for the ttl duration.
+// onComplete is called when an edge is completed, and onExpire when it expires before being completed.
+func NewStore(ttl time.Duration, maxItems int, onComplete, onExpire Callback) *Store {
+	return &Store{
+		l:          list.New(),
+		m:          make(map[Key]*list.Element),
+		onComplete: onComplete,
+		onExpire:   onExpire,
+		ttl:        ttl,
+		maxItems:   maxItems,
+	}
+}
+
+// Len returns the number of pending edges.
+func (s *Store) Len() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.l.Len()
+}
+
+// UpsertEdge fetches the edge of the key, creating it if it doesn't exist, and updates it with the
+// given callback. The edge is removed from the store and passed to onComplete once completed.
+// It returns whether the edge was created, and ErrTooManyItems if the store was full.
+func (s *Store) UpsertEdge(key Key, update Callback) (isNew bool, err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if storedEdge, ok := s.m[key]; ok {
+		edge := storedEdge.Value.(*Edge)
+		update(edge)
+
+		if edge.isComplete() {
+			s.onComplete(edge)
+			delete(s.m, key)
+			s.l.Remove(storedEdge)
+		}
+
+		return false, nil
+	}
+
+	edge := newEdge(key, s.ttl)
+	update(edge)
+
+	if edge.isComplete() {
+		s.onComplete(edge)
+		return true, nil
+	}
+
+	// Check we can add new edges
+	if s.l.Len() >= s.maxItems {
+		return false, ErrTooManyItems
+	}
+
+	ele := s.l.PushBack(edge)
+	s.m[key] = ele
+
+	return true, nil
+}
+
+// Expire evicts the expired edges, passing them to onExpire.
+func (s *Store) Expire() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// Edges are ordered by expiration, so stop at the first one which isn't expired.
+	for head := s.l.Front(); head != nil; head = s.l.Front() {
+		edge := head.Value.(*Edge)
+		if !edge.isExpired() {
+			break
+		}
+
+		s.onExpire(edge)
+		delete(s.m, edge.key)
+		s.l.Remove(head)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+var (
+	traceID = pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	spanID  = pcommon.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+)
+
+func TestStoreUpsertEdge(t *testing.T) {
+	var completed []*Edge
+	s := NewStore(time.Hour, 1, func(e *Edge) { completed = append(completed, e) }, noopCallback)
+	key := NewKey(traceID, spanID)
+
+	// Insert the client side of the edge
+	isNew, err := s.UpsertEdge(key, func(e *Edge) {
+		e.ClientService = "client"
+	})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.Equal(t, 1, s.Len())
+	assert.Empty(t, completed)
+
+	// The store is full
+	_, err = s.UpsertEdge(NewKey(traceID, pcommon.NewSpanID([8]byte{8})), func(e *Edge) {
+		e.ClientService = "client"
+	})
+	assert.ErrorIs(t, err, ErrTooManyItems)
+
+	// Complete the edge with its server side
+	isNew, err = s.UpsertEdge(key, func(e *Edge) {
+		e.ServerService = "server"
+	})
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, 0, s.Len())
+	require.Len(t, completed, 1)
+	assert.Equal(t, traceID, completed[0].TraceID)
+	assert.Equal(t, "client", completed[0].ClientService)
+	assert.Equal(t, "server", completed[0].ServerService)
+}
+
+func TestStoreUpsertCompleteEdge(t *testing.T) {
+	var completed int
+	s := NewStore(time.Hour, 0, func(e *Edge) { completed++ }, noopCallback)
+
+	isNew, err := s.UpsertEdge(NewKey(traceID, spanID), func(e *Edge) {
+		e.ClientService = "client"
+		e.ServerService = "server"
+	})
+	require.NoError(t, err, "A complete edge must not need room in the store")
+	assert.True(t, isNew)
+	assert.Equal(t, 1, completed)
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestStoreExpire(t *testing.T) {
+	var expired []*Edge
+	s := NewStore(100*time.Millisecond, 10, noopCallback, func(e *Edge) { expired = append(expired, e) })
+
+	for i := byte(0); i < 3; i++ {
+		_, err := s.UpsertEdge(NewKey(traceID, pcommon.NewSpanID([8]byte{i})), func(e *Edge) {
+			e.ServerService = "server"
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, s.Len())
+
+	s.Expire()
+	assert.Empty(t, expired, "Must not expire before the TTL")
+
+	time.Sleep(150 * time.Millisecond)
+	s.Expire()
+	assert.Len(t, expired, 3)
+	assert.Equal(t, 0, s.Len())
+
+	// An expired edge is started from scratch again
+	isNew, err := s.UpsertEdge(NewKey(traceID, pcommon.NewSpanID([8]byte{0})), func(e *Edge) {
+		e.ClientService = "client"
+	})
+	require.NoError(t, err)
+	assert.True(t, isNew)
+}
+
+func noopCallback(_ *Edge) {}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor"
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+var (
+	mTotalEdges   = stats.Int64("processor_servicegraph_total_edges", "Total number of unique edges", stats.UnitDimensionless)
+	mExpiredEdges = stats.Int64("processor_servicegraph_expired_edges", "Number of edges that expired before finding their client or server span", stats.UnitDimensionless)
+	mDroppedSpans = stats.Int64("processor_servicegraph_dropped_spans", "Number of spans dropped because the edges store was full", stats.UnitDimensionless)
+
+	mEvictedSeries = stats.Int64("processor_servicegraph_evicted_series", "Number of series evicted because the dimensions cache was full", stats.UnitDimensionless)
+)
+
+// MetricViews return the metrics views according to given telemetry level.
+func MetricViews() []*view.View {
+	return []*view.View{
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mTotalEdges.Name()),
+			Measure:     mTotalEdges,
+			Description: mTotalEdges.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mExpiredEdges.Name()),
+			Measure:     mExpiredEdges,
+			Description: mExpiredEdges.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mDroppedSpans.Name()),
+			Measure:     mDroppedSpans,
+			Description: mDroppedSpans.Description(),
+			Aggregation: view.Sum(),
+		},
+		{
+			Name:        obsreport.BuildProcessorCustomMetricName(typeStr, mEvictedSeries.Name()),
+			Measure:     mEvictedSeries,
+			Description: mEvictedSeries.Description(),
+			Aggregation: view.Sum(),
+		},
+	}
+}
@@ -0,0 +1,450 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/servicegraphprocessor/internal/store"
+)
+
+const (
+	clientKey          = "client"
+	serverKey          = "server"
+	metricKeySeparator = string(byte(0))
+
+	// The interval at which the edges store is checked for expired edges.
+	expireInterval = time.Second
+)
+
+var (
+	defaultLatencyHistogramBucketsSec = []float64{
+		0.002, 0.004, 0.006, 0.008, 0.01, 0.05, 0.1, 0.2, 0.4, 0.8, 1, 1.4, 2, 5, 10, 15,
+	}
+)
+
+type latencyHistogram struct {
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+}
+
+type processorImp struct {
+	lock   sync.Mutex
+	logger *zap.Logger
+	config Config
+
+	store *store.Store
+
+	metricsExporter component.MetricsExporter
+	nextConsumer    consumer.Traces
+
+	// The starting time of the data points.
+	startTime time.Time
+
+	// Request & failed request counts.
+	reqTotal       map[string]int64
+	reqFailedTotal map[string]int64
+
+	// Latency histograms of the client and server spans.
+	latencyBounds   []float64
+	clientLatencies map[string]*latencyHistogram
+	serverLatencies map[string]*latencyHistogram
+
+	// An LRU cache of the dimensions of each series, keyed by a concatenation of their values.
+	// Evicting a series from it removes its counts and latencies as well, which bounds the number of series.
+	keyToDimensions *lru.Cache
+
+	shutdownCh chan struct{}
+	wg         sync.WaitGroup
+}
+
+func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) (*processorImp, error) {
+	logger.Info("Building servicegraphprocessor")
+	pConfig := config.(*Config)
+
+	bounds := defaultLatencyHistogramBucketsSec
+	if pConfig.LatencyHistogramBuckets != nil {
+		bounds = mapDurationsToSeconds(pConfig.LatencyHistogramBuckets)
+	}
+
+	p := &processorImp{
+		logger:          logger,
+		config:          *pConfig,
+		nextConsumer:    nextConsumer,
+		startTime:       time.Now(),
+		reqTotal:        make(map[string]int64),
+		reqFailedTotal:  make(map[string]int64),
+		latencyBounds:   bounds,
+		clientLatencies: make(map[string]*latencyHistogram),
+		serverLatencies: make(map[string]*latencyHistogram),
+		shutdownCh:      make(chan struct{}),
+	}
+	keyToDimensions, err := lru.NewWithEvict(pConfig.DimensionsCacheSize, p.onEvictSeries)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dimensions cache size %d: %w", pConfig.DimensionsCacheSize, err)
+	}
+	p.keyToDimensions = keyToDimensions
+	p.store = store.NewStore(pConfig.Store.TTL, pConfig.Store.MaxItems, p.onComplete, p.onExpire)
+
+	return p, nil
+}
+
+func mapDurationsToSeconds(vs []time.Duration) []float64 {
+	vss := make([]float64, len(vs))
+	for i, v := range vs {
+		vss[i] = v.Seconds()
+	}
+	return vss
+}
+
+// Start implements the component.Component interface.
+func (p *processorImp) Start(ctx context.Context, host component.Host) error {
+	p.logger.Info("Starting servicegraphprocessor")
+	exporters := host.GetExporters()
+
+	var availableMetricsExporters []string
+
+	// The available list of exporters come from any configured metrics pipelines' exporters.
+	for k, exp := range exporters[config.MetricsDataType] {
+		metricsExp, ok := exp.(component.MetricsExporter)
+		if !ok {
+			return fmt.Errorf("the exporter %q isn't a metrics exporter", k.String())
+		}
+
+		availableMetricsExporters = append(availableMetricsExporters, k.String())
+
+		if k.String() == p.config.MetricsExporter {
+			p.metricsExporter = metricsExp
+			p.logger.Info("Found exporter", zap.String("servicegraph-exporter", p.config.MetricsExporter))
+			break
+		}
+	}
+	if p.metricsExporter == nil {
+		return fmt.Errorf("failed to find metrics exporter: '%s'; please configure metrics_exporter from one of: %+v",
+			p.config.MetricsExporter, availableMetricsExporters)
+	}
+
+	p.wg.Add(1)
+	go p.expireLoop()
+
+	p.logger.Info("Started servicegraphprocessor")
+	return nil
+}
+
+// Shutdown implements the component.Component interface.
+func (p *processorImp) Shutdown(ctx context.Context) error {
+	p.logger.Info("Shutting down servicegraphprocessor")
+	if p.metricsExporter != nil {
+		close(p.shutdownCh)
+		p.wg.Wait()
+	}
+	return nil
+}
+
+// Capabilities implements the consumer interface.
+func (p *processorImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements the consumer.Traces interface.
+// It pairs the client and server spans into edges, forwarding the metrics of the completed edges to the
+// discovered metrics exporter. The original input trace data will be forwarded to the next consumer, unmodified.
+func (p *processorImp) ConsumeTraces(ctx context.Context, traces ptrace.Traces) error {
+	// Forward trace data unmodified and propagate both metrics and trace pipeline errors, if any.
+	return multierr.Combine(p.tracesToMetrics(ctx, traces), p.nextConsumer.ConsumeTraces(ctx, traces))
+}
+
+func (p *processorImp) tracesToMetrics(ctx context.Context, traces ptrace.Traces) error {
+	p.aggregateEdges(ctx, traces)
+
+	p.lock.Lock()
+	m := p.buildMetrics()
+	p.lock.Unlock()
+
+	return p.metricsExporter.ConsumeMetrics(ctx, m)
+}
+
+// aggregateEdges upserts the edges of the client and server spans into the store, which calls
+// onComplete with each edge once both of its spans were received.
+func (p *processorImp) aggregateEdges(ctx context.Context, traces ptrace.Traces) {
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttr := rs.Resource().Attributes()
+		serviceAttr, ok := resourceAttr.Get(conventions.AttributeServiceName)
+		if !ok {
+			continue
+		}
+		serviceName := serviceAttr.StringVal()
+
+		ilsSlice := rs.ScopeSpans()
+		for j := 0; j < ilsSlice.Len(); j++ {
+			spans := ilsSlice.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+
+				var (
+					isNew bool
+					err   error
+				)
+				switch span.Kind() {
+				case ptrace.SpanKindClient:
+					key := store.NewKey(span.TraceID(), span.SpanID())
+					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+						e.ClientService = serviceName
+						e.ClientLatencySec = spanDurationSec(span)
+						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+						p.upsertDimensions(clientKey, e.Dimensions, resourceAttr, span.Attributes())
+					})
+				case ptrace.SpanKindServer:
+					// A server span without parent has no client to pair with.
+					if span.ParentSpanID().IsEmpty() {
+						continue
+					}
+					key := store.NewKey(span.TraceID(), span.ParentSpanID())
+					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+						e.ServerService = serviceName
+						e.ServerLatencySec = spanDurationSec(span)
+						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+						p.upsertDimensions(serverKey, e.Dimensions, resourceAttr, span.Attributes())
+					})
+				default:
+					continue
+				}
+
+				if errors.Is(err, store.ErrTooManyItems) {
+					stats.Record(ctx, mDroppedSpans.M(1))
+					continue
+				}
+				if isNew {
+					stats.Record(ctx, mTotalEdges.M(1))
+				}
+			}
+		}
+	}
+}
+
+// upsertDimensions adds the configured dimensions found in the span's attributes, or else in its resource's,
+// prefixed by the side of the edge the span belongs to.
+func (p *processorImp) upsertDimensions(kind string, dimensions map[string]string, resourceAttr, spanAttr pcommon.Map) {
+	for _, dim := range p.config.Dimensions {
+		if v, ok := spanAttr.Get(dim); ok {
+			dimensions[kind+"_"+dim] = v.AsString()
+		} else if v, ok := resourceAttr.Get(dim); ok {
+			dimensions[kind+"_"+dim] = v.AsString()
+		}
+	}
+}
+
+func spanDurationSec(span ptrace.Span) float64 {
+	return span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()).Seconds()
+}
+
+// onComplete aggregates the metrics of an edge once both of its spans were received.
+func (p *processorImp) onComplete(e *store.Edge) {
+	p.logger.Debug(
+		"edge completed",
+		zap.String("client_service", e.ClientService),
+		zap.String("server_service", e.ServerService),
+		zap.String("trace_id", e.TraceID.HexString()),
+	)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	key := p.buildMetricKey(e)
+	// Use Get to ensure any existing key has its recent-ness updated.
+	if _, ok := p.keyToDimensions.Get(key); !ok {
+		p.keyToDimensions.Add(key, p.buildDimensionKVs(e))
+	}
+
+	p.reqTotal[key]++
+	if e.Failed {
+		p.reqFailedTotal[key]++
+	}
+	p.updateLatency(p.clientLatencies, key, e.ClientLatencySec)
+	p.updateLatency(p.serverLatencies, key, e.ServerLatencySec)
+}
+
+// onEvictSeries removes the counts and latencies of a series evicted from keyToDimensions,
+// which is only updated while holding the lock.
+func (p *processorImp) onEvictSeries(key, _ interface{}) {
+	k := key.(string)
+	delete(p.reqTotal, k)
+	delete(p.reqFailedTotal, k)
+	delete(p.clientLatencies, k)
+	delete(p.serverLatencies, k)
+	stats.Record(context.Background(), mEvictedSeries.M(1))
+}
+
+// dimensions returns the dimensions of a series.
+func (p *processorImp) dimensions(key string) pcommon.Map {
+	dims, _ := p.keyToDimensions.Peek(key)
+	return dims.(pcommon.Map)
+}
+
+// onExpire is called with the edges whose client or server span wasn't received in time.
+func (p *processorImp) onExpire(e *store.Edge) {
+	p.logger.Debug(
+		"edge expired",
+		zap.String("client_service", e.ClientService),
+		zap.String("server_service", e.ServerService),
+		zap.String("trace_id", e.TraceID.HexString()),
+	)
+
+	stats.Record(context.Background(), mExpiredEdges.M(1))
+}
+
+func (p *processorImp) expireLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.store.Expire()
+		case <-p.shutdownCh:
+			return
+		}
+	}
+}
+
+func (p *processorImp) updateLatency(latencies map[string]*latencyHistogram, key string, latency float64) {
+	h, ok := latencies[key]
+	if !ok {
+		h = &latencyHistogram{bucketCounts: make([]uint64, len(p.latencyBounds)+1)}
+		latencies[key] = h
+	}
+
+	h.count++
+	h.sum += latency
+	// Binary search to find the latency bucket index.
+	index := sort.SearchFloat64s(p.latencyBounds, latency)
+	h.bucketCounts[index]++
+}
+
+// buildMetricKey builds the key of the series of an edge: its client and server services,
+// followed by the values of its dimensions.
+func (p *processorImp) buildMetricKey(e *store.Edge) string {
+	var metricKey strings.Builder
+	metricKey.WriteString(e.ClientService)
+	metricKey.WriteString(metricKeySeparator)
+	metricKey.WriteString(e.ServerService)
+	for _, dim := range p.config.Dimensions {
+		for _, kind := range []string{clientKey, serverKey} {
+			metricKey.WriteString(metricKeySeparator)
+			metricKey.WriteString(e.Dimensions[kind+"_"+dim])
+		}
+	}
+	return metricKey.String()
+}
+
+func (p *processorImp) buildDimensionKVs(e *store.Edge) pcommon.Map {
+	dims := pcommon.NewMap()
+	dims.UpsertString(clientKey, e.ClientService)
+	dims.UpsertString(serverKey, e.ServerService)
+	for _, dim := range p.config.Dimensions {
+		for _, kind := range []string{clientKey, serverKey} {
+			if v, ok := e.Dimensions[kind+"_"+dim]; ok {
+				dims.UpsertString(kind+"_"+dim, v)
+			}
+		}
+	}
+	dims.Sort()
+	return dims
+}
+
+// buildMetrics builds the cumulative metrics of the edges completed so far.
+func (p *processorImp) buildMetrics() pmetric.Metrics {
+	m := pmetric.NewMetrics()
+	ilm := m.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	ilm.Scope().SetName("servicegraphprocessor")
+
+	p.collectCountMetrics(ilm, "traces_service_graph_request_total", p.reqTotal)
+	p.collectCountMetrics(ilm, "traces_service_graph_request_failed_total", p.reqFailedTotal)
+	p.collectLatencyMetrics(ilm, "traces_service_graph_request_client_seconds", p.clientLatencies)
+	p.collectLatencyMetrics(ilm, "traces_service_graph_request_server_seconds", p.serverLatencies)
+
+	return m
+}
+
+// collectCountMetrics writes the counts of each series into a metric of the given name.
+func (p *processorImp) collectCountMetrics(ilm pmetric.ScopeMetrics, name string, counts map[string]int64) {
+	if len(counts) == 0 {
+		return
+	}
+
+	mCount := ilm.Metrics().AppendEmpty()
+	mCount.SetDataType(pmetric.MetricDataTypeSum)
+	mCount.SetName(name)
+	mCount.Sum().SetIsMonotonic(true)
+	mCount.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+	for key, count := range counts {
+		dpCount := mCount.Sum().DataPoints().AppendEmpty()
+		dpCount.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+		dpCount.SetTimestamp(timestamp)
+		dpCount.SetIntVal(count)
+
+		p.dimensions(key).CopyTo(dpCount.Attributes())
+	}
+}
+
+// collectLatencyMetrics writes the latency histograms of each series into a metric of the given name.
+func (p *processorImp) collectLatencyMetrics(ilm pmetric.ScopeMetrics, name string, latencies map[string]*latencyHistogram) {
+	if len(latencies) == 0 {
+		return
+	}
+
+	mLatency := ilm.Metrics().AppendEmpty()
+	mLatency.SetDataType(pmetric.MetricDataTypeHistogram)
+	mLatency.SetName(name)
+	mLatency.SetUnit("s")
+	mLatency.Histogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+
+	timestamp := pcommon.NewTimestampFromTime(time.Now())
+	for key, h := range latencies {
+		dpLatency := mLatency.Histogram().DataPoints().AppendEmpty()
+		dpLatency.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+		dpLatency.SetTimestamp(timestamp)
+		dpLatency.SetExplicitBounds(pcommon.NewImmutableFloat64Slice(p.latencyBounds))
+		dpLatency.SetBucketCounts(pcommon.NewImmutableUInt64Slice(h.bucketCounts))
+		dpLatency.SetCount(h.count)
+		dpLatency.SetSum(h.sum)
+
+		p.dimensions(key).CopyTo(dpLatency.Attributes())
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicegraphprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"go.uber.org/zap/zaptest"
+)
+
+const (
+	metricsExporterName = "mock"
+	clientService       = "frontend"
+	serverService       = "checkout"
+)
+
+var (
+	traceID      = pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	clientSpanID = pcommon.NewSpanID([8]byte{1, 1, 1, 1, 1, 1, 1, 1})
+	serverSpanID = pcommon.NewSpanID([8]byte{2, 2, 2, 2, 2, 2, 2, 2})
+)
+
+type mockMetricsExporter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	*consumertest.MetricsSink
+}
+
+type mockHost struct {
+	component.Host
+	exporters map[config.DataType]map[config.ComponentID]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[config.DataType]map[config.ComponentID]component.Exporter {
+	return h.exporters
+}
+
+func newMockHost(sink *consumertest.MetricsSink) component.Host {
+	return &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[config.DataType]map[config.ComponentID]component.Exporter{
+			config.MetricsDataType: {
+				config.NewComponentID(metricsExporterName): &mockMetricsExporter{MetricsSink: sink},
+			},
+		},
+	}
+}
+
+func newTestProcessor(t *testing.T, cfg *Config, sink *consumertest.MetricsSink) (*processorImp, *consumertest.TracesSink) {
+	cfg.MetricsExporter = metricsExporterName
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), newMockHost(sink)))
+	t.Cleanup(func() { assert.NoError(t, p.Shutdown(context.Background())) })
+	return p, next
+}
+
+// buildSampleTrace builds a trace of a client span and the server span it called,
+// each in the resource of their service.
+func buildSampleTrace(serverStatus ptrace.StatusCode) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	start := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	client := traces.ResourceSpans().AppendEmpty()
+	client.Resource().Attributes().InsertString(conventions.AttributeServiceName, clientService)
+	client.Resource().Attributes().InsertString("region", "eu")
+	clientSpan := client.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	clientSpan.SetName("GET /checkout")
+	clientSpan.SetKind(ptrace.SpanKindClient)
+	clientSpan.SetTraceID(traceID)
+	clientSpan.SetSpanID(clientSpanID)
+	clientSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	clientSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(30 * time.Millisecond)))
+
+	server := traces.ResourceSpans().AppendEmpty()
+	server.Resource().Attributes().InsertString(conventions.AttributeServiceName, serverService)
+	serverSpan := server.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	serverSpan.SetName("/checkout")
+	serverSpan.SetKind(ptrace.SpanKindServer)
+	serverSpan.SetTraceID(traceID)
+	serverSpan.SetSpanID(serverSpanID)
+	serverSpan.SetParentSpanID(clientSpanID)
+	serverSpan.Attributes().InsertString("region", "us")
+	serverSpan.Status().SetCode(serverStatus)
+	serverSpan.SetStartTimestamp(pcommon.NewTimestampFromTime(start.Add(5 * time.Millisecond)))
+	serverSpan.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(25 * time.Millisecond)))
+
+	return traces
+}
+
+// splitTrace moves the resource spans at the given index into traces of their own.
+func splitTrace(traces ptrace.Traces, index int) ptrace.Traces {
+	split := ptrace.NewTraces()
+	traces.ResourceSpans().At(index).CopyTo(split.ResourceSpans().AppendEmpty())
+	traces.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		index--
+		return index == -1
+	})
+	return split
+}
+
+func findMetric(t *testing.T, md pmetric.Metrics, name string) pmetric.Metric {
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Name() == name {
+			return metrics.At(i)
+		}
+	}
+	require.Failf(t, "metric not found", "no %s metric", name)
+	return pmetric.Metric{}
+}
+
+func TestProcessorStart(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsExporter = "unknown"
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), newMockHost(new(consumertest.MetricsSink)))
+	assert.EqualError(t, err, "failed to find metrics exporter: 'unknown'; please configure metrics_exporter from one of: [mock]")
+	assert.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestProcessorConsumeTraces(t *testing.T) {
+	// Prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Dimensions = []string{"region"}
+	sink := new(consumertest.MetricsSink)
+	p, next := newTestProcessor(t, cfg, sink)
+
+	// Test
+	traces := buildSampleTrace(ptrace.StatusCodeError)
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	// Verify
+	assert.Equal(t, 2, next.SpanCount(), "Must forward the traces unmodified")
+	assert.Equal(t, 0, p.store.Len())
+
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	assert.Equal(t, 4, md.MetricCount())
+
+	wantAttrs := map[string]interface{}{
+		"client":        clientService,
+		"server":        serverService,
+		"client_region": "eu",
+		"server_region": "us",
+	}
+	for _, name := range []string{"traces_service_graph_request_total", "traces_service_graph_request_failed_total"} {
+		m := findMetric(t, md, name)
+		assert.Equal(t, pmetric.MetricDataTypeSum, m.DataType())
+		assert.True(t, m.Sum().IsMonotonic())
+		require.Equal(t, 1, m.Sum().DataPoints().Len())
+		dp := m.Sum().DataPoints().At(0)
+		assert.Equal(t, int64(1), dp.IntVal())
+		assert.Equal(t, wantAttrs, dp.Attributes().AsRaw())
+	}
+
+	for name, wantSum := range map[string]float64{
+		"traces_service_graph_request_client_seconds": 0.03,
+		"traces_service_graph_request_server_seconds": 0.02,
+	} {
+		m := findMetric(t, md, name)
+		assert.Equal(t, pmetric.MetricDataTypeHistogram, m.DataType())
+		assert.Equal(t, "s", m.Unit())
+		require.Equal(t, 1, m.Histogram().DataPoints().Len())
+		dp := m.Histogram().DataPoints().At(0)
+		assert.Equal(t, uint64(1), dp.Count())
+		assert.InDelta(t, wantSum, dp.Sum(), 1e-9)
+		assert.Equal(t, defaultLatencyHistogramBucketsSec, dp.ExplicitBounds().AsRaw())
+		assert.Equal(t, uint64(1), dp.BucketCounts().At(5), "Must be counted in the (0.01, 0.05] bucket")
+		assert.Equal(t, wantAttrs, dp.Attributes().AsRaw())
+	}
+}
+
+func TestProcessorPairsSpansAcrossBatches(t *testing.T) {
+	// Prepare
+	sink := new(consumertest.MetricsSink)
+	p, _ := newTestProcessor(t, createDefaultConfig().(*Config), sink)
+	traces := buildSampleTrace(ptrace.StatusCodeUnset)
+	server := splitTrace(traces, 1)
+
+	// Test
+	require.NoError(t, p.ConsumeTraces(context.Background(), server))
+	assert.Equal(t, 1, p.store.Len(), "Must wait for the client span")
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+	// Verify
+	assert.Equal(t, 0, p.store.Len())
+	require.Len(t, sink.AllMetrics(), 2)
+	assert.Equal(t, 0, sink.AllMetrics()[0].MetricCount())
+
+	md := sink.AllMetrics()[1]
+	assert.Equal(t, 3, md.MetricCount(), "Must not report failed requests")
+	dp := findMetric(t, md, "traces_service_graph_request_total").Sum().DataPoints().At(0)
+	assert.Equal(t, int64(1), dp.IntVal())
+	assert.Equal(t, map[string]interface{}{"client": clientService, "server": serverService}, dp.Attributes().AsRaw())
+}
+
+func TestProcessorStoreBounds(t *testing.T) {
+	// Prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Store.MaxItems = 1
+	cfg.Store.TTL = 50 * time.Millisecond
+	sink := new(consumertest.MetricsSink)
+	p, _ := newTestProcessor(t, cfg, sink)
+
+	traces := buildSampleTrace(ptrace.StatusCodeUnset)
+	client := splitTrace(traces, 0)
+	other := client.Clone()
+	other.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetSpanID(pcommon.NewSpanID([8]byte{3}))
+
+	// Test
+	require.NoError(t, p.ConsumeTraces(context.Background(), client))
+	require.NoError(t, p.ConsumeTraces(context.Background(), other))
+
+	// Verify
+	assert.Equal(t, 1, p.store.Len(), "Must drop the spans of new edges when full")
+	assert.Eventually(t, func() bool {
+		return p.store.Len() == 0
+	}, 5*time.Second, 10*time.Millisecond, "Must expire the pending edge")
+
+	// The server span of the expired edge starts a new one.
+	require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	assert.Equal(t, 1, p.store.Len())
+}
+
+func TestProcessorDimensionsCacheBounds(t *testing.T) {
+	// Prepare
+	cfg := createDefaultConfig().(*Config)
+	cfg.Dimensions = []string{"region"}
+	cfg.DimensionsCacheSize = 2
+	sink := new(consumertest.MetricsSink)
+	p, _ := newTestProcessor(t, cfg, sink)
+
+	// Test
+	for _, region := range []string{"eu", "us", "ap"} {
+		traces := buildSampleTrace(ptrace.StatusCodeError)
+		traces.ResourceSpans().At(0).Resource().Attributes().UpsertString("region", region)
+		require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+	}
+
+	// Verify
+	assert.Equal(t, 2, p.keyToDimensions.Len())
+	assert.Len(t, p.reqTotal, 2)
+	assert.Len(t, p.reqFailedTotal, 2)
+	assert.Len(t, p.clientLatencies, 2)
+	assert.Len(t, p.serverLatencies, 2)
+
+	md := sink.AllMetrics()[len(sink.AllMetrics())-1]
+	dps := findMetric(t, md, "traces_service_graph_request_total").Sum().DataPoints()
+	var regions []string
+	for i := 0; i < dps.Len(); i++ {
+		region, _ := dps.At(i).Attributes().Get("client_region")
+		regions = append(regions, region.StringVal())
+	}
+	assert.ElementsMatch(t, []string{"us", "ap"}, regions, "Must drop the least recently updated series")
+}
@@ -15,6 +15,8 @@
 package spanmetricsprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -33,6 +35,16 @@ type Dimension struct {
 	Default *string `mapstructure:"default"`
 }
 
+// EventsConfig defines the counters of span events, emitted as the "events_total" metric.
+type EventsConfig struct {
+	// Enabled turns on the counters of span events, by the dimensions of their span and their name.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Dimensions defines the list of additional dimensions fetched from the event's attributes,
+	// e.g. exception.type for the exception events.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+}
+
 // Config defines the configuration options for spanmetricsprocessor.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -40,10 +52,25 @@ type Config struct {
 	// MetricsExporter is the name of the metrics exporter to use to ship metrics.
 	MetricsExporter string `mapstructure:"metrics_exporter"`
 
+	// MetricsReceiver is the name of the spanmetrics receiver feeding the metrics into its metrics pipeline,
+	// so that they are processed like any other metrics. It replaces MetricsExporter when set.
+	MetricsReceiver string `mapstructure:"metrics_receiver"`
+
 	// LatencyHistogramBuckets is the list of durations representing latency histogram buckets.
 	// See defaultLatencyHistogramBucketsMs in processor.go for the default value.
 	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
 
+	// ExponentialHistogramMaxSize, when greater than zero, makes the processor emit the latency as an
+	// exponential histogram with at most this many buckets, instead of the explicit bucket histogram.
+	// It must be at least 3, for any latency to fit within the buckets at the lowest scale.
+	// The buckets are adjusted to the recorded latencies, so it can't be used with LatencyHistogramBuckets.
+	ExponentialHistogramMaxSize int32 `mapstructure:"exponential_histogram_max_size"`
+
+	// MaxExemplarsPerSeries is the maximum number of exemplars attached to each latency data point,
+	// sampled uniformly from the spans received since the previous export. Set to zero to disable exemplars.
+	// Optional. See defaultMaxExemplarsPerSeries in processor.go for the default value.
+	MaxExemplarsPerSeries int `mapstructure:"max_exemplars_per_series"`
+
 	// Dimensions defines the list of additional dimensions on top of the provided:
 	// - service.name
 	// - operation
@@ -60,6 +87,9 @@ type Config struct {
 
 	AggregationTemporality string `mapstructure:"aggregation_temporality"`
 
+	// Events configures the counters of span events, e.g. exceptions. Optional.
+	Events EventsConfig `mapstructure:"events"`
+
 	// skipSanitizeLabel if enabled, labels that start with _ are not sanitized
 	skipSanitizeLabel bool
 }
@@ -78,3 +108,36 @@ func (c Config) GetAggregationTemporality() pmetric.MetricAggregationTemporality
 	}
 	return pmetric.MetricAggregationTemporalityCumulative
 }
+
+// Validate checks that the metrics_receiver, if any, is a valid spanmetrics receiver ID.
+func (c *Config) Validate() error {
+	if _, err := c.metricsReceiverID(); err != nil {
+		return err
+	}
+	if c.ExponentialHistogramMaxSize < 0 || (c.ExponentialHistogramMaxSize > 0 && c.ExponentialHistogramMaxSize < exponentialHistogramMinSize) {
+		return fmt.Errorf("invalid exponential histogram max size: %v, it should be 0 to disable exponential histograms or at least %d",
+			c.ExponentialHistogramMaxSize, exponentialHistogramMinSize)
+	}
+	if c.ExponentialHistogramMaxSize > 0 && c.LatencyHistogramBuckets != nil {
+		return errors.New("latency histogram buckets can't be configured with exponential histograms")
+	}
+	return nil
+}
+
+// metricsReceiverID parses the ID of the spanmetrics receiver configured in metrics_receiver, if any.
+func (c *Config) metricsReceiverID() (*config.ComponentID, error) {
+	if c.MetricsReceiver == "" {
+		return nil, nil
+	}
+	if c.MetricsExporter != "" {
+		return nil, errors.New("metrics_exporter and metrics_receiver can't be configured together")
+	}
+	id, err := config.NewComponentIDFromString(c.MetricsReceiver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics receiver %q: %w", c.MetricsReceiver, err)
+	}
+	if id.Type() != typeStr {
+		return nil, fmt.Errorf("the metrics receiver %q isn't a %s receiver", c.MetricsReceiver, typeStr)
+	}
+	return &id, nil
+}
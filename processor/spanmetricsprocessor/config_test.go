@@ -32,6 +32,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/jaegerexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jaegerreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/spanmetricsreceiver"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -39,22 +40,29 @@ func TestLoadConfig(t *testing.T) {
 	testcases := []struct {
 		configFile                  string
 		wantMetricsExporter         string
+		wantMetricsReceiver         string
 		wantLatencyHistogramBuckets []time.Duration
 		wantDimensions              []Dimension
 		wantDimensionsCacheSize     int
 		wantAggregationTemporality  string
+		wantExponentialMaxSize      int32
+		wantMaxExemplarsPerSeries   int
+		wantEvents                  EventsConfig
 	}{
 		{
 			configFile:                 "config-2-pipelines.yaml",
 			wantMetricsExporter:        "prometheus",
 			wantAggregationTemporality: cumulative,
 			wantDimensionsCacheSize:    500,
+			wantMaxExemplarsPerSeries:  defaultMaxExemplarsPerSeries,
 		},
 		{
 			configFile:                 "config-3-pipelines.yaml",
 			wantMetricsExporter:        "otlp/spanmetrics",
 			wantAggregationTemporality: cumulative,
 			wantDimensionsCacheSize:    defaultDimensionsCacheSize,
+			wantExponentialMaxSize:     160,
+			wantMaxExemplarsPerSeries:  defaultMaxExemplarsPerSeries,
 		},
 		{
 			configFile:          "config-full.yaml",
@@ -74,6 +82,18 @@ func TestLoadConfig(t *testing.T) {
 			},
 			wantDimensionsCacheSize:    1500,
 			wantAggregationTemporality: delta,
+			wantMaxExemplarsPerSeries:  5,
+			wantEvents: EventsConfig{
+				Enabled:    true,
+				Dimensions: []Dimension{{"exception.type", nil}},
+			},
+		},
+		{
+			configFile:                 "config-metrics-receiver.yaml",
+			wantMetricsReceiver:        "spanmetrics",
+			wantAggregationTemporality: cumulative,
+			wantDimensionsCacheSize:    defaultDimensionsCacheSize,
+			wantMaxExemplarsPerSeries:  defaultMaxExemplarsPerSeries,
 		},
 	}
 	for _, tc := range testcases {
@@ -84,6 +104,7 @@ func TestLoadConfig(t *testing.T) {
 
 			factories.Receivers["otlp"] = otlpreceiver.NewFactory()
 			factories.Receivers["jaeger"] = jaegerreceiver.NewFactory()
+			factories.Receivers[typeStr] = spanmetricsreceiver.NewFactory()
 
 			factories.Processors[typeStr] = NewFactory()
 			factories.Processors["batch"] = batchprocessor.NewFactory()
@@ -100,12 +121,16 @@ func TestLoadConfig(t *testing.T) {
 			require.NotNil(t, cfg)
 			assert.Equal(t,
 				&Config{
-					ProcessorSettings:       config.NewProcessorSettings(config.NewComponentID(typeStr)),
-					MetricsExporter:         tc.wantMetricsExporter,
-					LatencyHistogramBuckets: tc.wantLatencyHistogramBuckets,
-					Dimensions:              tc.wantDimensions,
-					DimensionsCacheSize:     tc.wantDimensionsCacheSize,
-					AggregationTemporality:  tc.wantAggregationTemporality,
+					ProcessorSettings:           config.NewProcessorSettings(config.NewComponentID(typeStr)),
+					MetricsExporter:             tc.wantMetricsExporter,
+					MetricsReceiver:             tc.wantMetricsReceiver,
+					LatencyHistogramBuckets:     tc.wantLatencyHistogramBuckets,
+					ExponentialHistogramMaxSize: tc.wantExponentialMaxSize,
+					MaxExemplarsPerSeries:       tc.wantMaxExemplarsPerSeries,
+					Dimensions:                  tc.wantDimensions,
+					DimensionsCacheSize:         tc.wantDimensionsCacheSize,
+					AggregationTemporality:      tc.wantAggregationTemporality,
+					Events:                      tc.wantEvents,
 				},
 				cfg.Processors[config.NewComponentID(typeStr)],
 			)
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor"
+
+import (
+	"math"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// exponentialHistogramMaxScale is the scale the histograms start at, before being
+	// downscaled to fit the recorded values within their maximum number of buckets.
+	exponentialHistogramMaxScale = 20
+	// exponentialHistogramMinScale is the lowest scale of the histograms, which covers
+	// all the positive float64 values, including the subnormal ones, within three buckets.
+	exponentialHistogramMinScale = -10
+	// exponentialHistogramMinSize is the lowest maximum number of buckets, for which
+	// any recorded value fits within the buckets at exponentialHistogramMinScale.
+	exponentialHistogramMinSize = 3
+)
+
+// exponentialHistogram accumulates positive values, such as latencies, into the base-2
+// exponential buckets of the OpenTelemetry data model:
+// https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/metrics/data-model.md#exponentialhistogram
+// The histogram starts at the highest scale and is downscaled whenever the recorded values
+// don't fit within maxSize buckets anymore.
+type exponentialHistogram struct {
+	maxSize int32
+
+	scale     int32
+	offset    int32
+	counts    []uint64
+	zeroCount uint64
+	count     uint64
+	sum       float64
+}
+
+func newExponentialHistogram(maxSize int32) *exponentialHistogram {
+	return &exponentialHistogram{
+		maxSize: maxSize,
+		scale:   exponentialHistogramMaxScale,
+	}
+}
+
+// record adds the value to the histogram, values lower or equal to zero are counted in the zero bucket.
+func (h *exponentialHistogram) record(value float64) {
+	h.count++
+	h.sum += value
+	if value <= 0 {
+		h.zeroCount++
+		return
+	}
+
+	index := mapToIndex(value, h.scale)
+	if len(h.counts) == 0 {
+		h.offset = index
+		h.counts = []uint64{1}
+		return
+	}
+
+	low, high := h.offset, h.offset+int32(len(h.counts))-1
+	if index < low {
+		low = index
+	}
+	if index > high {
+		high = index
+	}
+	change := int32(0)
+	for (high>>change)-(low>>change)+1 > h.maxSize && h.scale-change > exponentialHistogramMinScale {
+		change++
+	}
+	if change > 0 {
+		h.downscale(change)
+		index >>= change
+	}
+	h.increment(index)
+}
+
+// downscale lowers the scale of the histogram by change, merging the buckets that
+// fall within the same bucket at the new scale.
+func (h *exponentialHistogram) downscale(change int32) {
+	offset := h.offset >> change
+	last := (h.offset + int32(len(h.counts)) - 1) >> change
+	counts := make([]uint64, last-offset+1)
+	for i, count := range h.counts {
+		counts[((h.offset+int32(i))>>change)-offset] += count
+	}
+	h.scale -= change
+	h.offset = offset
+	h.counts = counts
+}
+
+// increment adds one to the bucket at index, growing the buckets as needed.
+func (h *exponentialHistogram) increment(index int32) {
+	if index < h.offset {
+		h.counts = append(make([]uint64, h.offset-index), h.counts...)
+		h.offset = index
+	}
+	if last := h.offset + int32(len(h.counts)) - 1; index > last {
+		h.counts = append(h.counts, make([]uint64, index-last)...)
+	}
+	h.counts[index-h.offset]++
+}
+
+// copyTo writes the histogram into the data point.
+func (h *exponentialHistogram) copyTo(dp pmetric.ExponentialHistogramDataPoint) {
+	dp.SetScale(h.scale)
+	dp.SetCount(h.count)
+	dp.SetSum(h.sum)
+	dp.SetZeroCount(h.zeroCount)
+	dp.Positive().SetOffset(h.offset)
+	counts := make([]uint64, len(h.counts))
+	copy(counts, h.counts)
+	dp.Positive().SetBucketCounts(pcommon.NewImmutableUInt64Slice(counts))
+}
+
+// mapToIndex returns the index of the bucket holding the positive value at the given scale,
+// where the bucket at index i covers the values in (base^i, base^(i+1)] with base = 2^(2^-scale).
+func mapToIndex(value float64, scale int32) int32 {
+	frac, exp := math.Frexp(value)
+	// Exact powers of two are the upper boundary of their bucket.
+	powerOfTwo := frac == 0.5
+	if scale <= 0 {
+		if powerOfTwo {
+			exp--
+		}
+		return int32(exp-1) >> -scale
+	}
+	if powerOfTwo {
+		return int32(exp-1)<<scale - 1
+	}
+	return int32(math.Ceil(math.Log(value)*math.Ldexp(math.Log2E, int(scale)))) - 1
+}
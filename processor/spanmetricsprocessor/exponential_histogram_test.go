@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestMapToIndex(t *testing.T) {
+	for _, tc := range []struct {
+		value float64
+		scale int32
+		want  int32
+	}{
+		// At scale 0 the buckets are (1, 2], (2, 4], (4, 8]...
+		{value: 1, scale: 0, want: -1},
+		{value: 1.5, scale: 0, want: 0},
+		{value: 2, scale: 0, want: 0},
+		{value: 3, scale: 0, want: 1},
+		{value: 0.5, scale: 0, want: -2},
+		// At scale -1 the buckets are (1, 4], (4, 16]...
+		{value: 4, scale: -1, want: 0},
+		{value: 5, scale: -1, want: 1},
+		{value: 0.75, scale: -1, want: -1},
+		// At scale 1 the buckets are (1, sqrt(2)], (sqrt(2), 2]...
+		{value: 1.4, scale: 1, want: 0},
+		{value: 1.5, scale: 1, want: 1},
+		{value: 2, scale: 1, want: 1},
+		{value: 4, scale: 1, want: 3},
+	} {
+		assert.Equal(t, tc.want, mapToIndex(tc.value, tc.scale), "value %v at scale %v", tc.value, tc.scale)
+	}
+}
+
+func TestExponentialHistogramRecord(t *testing.T) {
+	h := newExponentialHistogram(4)
+	for _, v := range []float64{0, 1.5, 3, 7, 7, 1000} {
+		h.record(v)
+	}
+
+	assert.Equal(t, uint64(6), h.count)
+	assert.Equal(t, 1018.5, h.sum)
+	assert.Equal(t, uint64(1), h.zeroCount)
+	assert.LessOrEqual(t, len(h.counts), 4, "Must not exceed the max size")
+
+	// Every value must be in the bucket it maps to at the final scale.
+	var total uint64
+	for _, c := range h.counts {
+		total += c
+	}
+	assert.Equal(t, uint64(5), total)
+	for _, v := range []float64{1.5, 3, 7, 1000} {
+		index := mapToIndex(v, h.scale)
+		assert.GreaterOrEqual(t, index, h.offset)
+		assert.Less(t, index, h.offset+int32(len(h.counts)))
+	}
+	want := make([]uint64, len(h.counts))
+	for _, v := range []float64{1.5, 3, 7, 7, 1000} {
+		want[mapToIndex(v, h.scale)-h.offset]++
+	}
+	assert.Equal(t, want, h.counts)
+}
+
+func TestExponentialHistogramDownscale(t *testing.T) {
+	h := newExponentialHistogram(160)
+	h.record(1)
+	assert.Equal(t, int32(exponentialHistogramMaxScale), h.scale, "Must keep the max scale for a single value")
+
+	h.record(2)
+	h.record(math.MaxFloat64)
+	assert.Less(t, h.scale, int32(exponentialHistogramMaxScale))
+	assert.LessOrEqual(t, len(h.counts), 160)
+
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	h.copyTo(dp)
+	assert.Equal(t, h.scale, dp.Scale())
+	assert.Equal(t, uint64(3), dp.Count())
+	assert.Equal(t, h.offset, dp.Positive().Offset())
+	assert.Equal(t, h.counts, dp.Positive().BucketCounts().AsRaw())
+}
+
+func TestExponentialHistogramMinSize(t *testing.T) {
+	h := newExponentialHistogram(exponentialHistogramMinSize)
+	for _, value := range []float64{math.SmallestNonzeroFloat64, 1, math.MaxFloat64} {
+		h.record(value)
+	}
+	assert.Equal(t, int32(exponentialHistogramMinScale), h.scale)
+	assert.Equal(t, int32(-2), h.offset)
+	assert.Equal(t, []uint64{1, 1, 1}, h.counts)
+}
@@ -17,6 +17,7 @@ package spanmetricsprocessor // import "github.com/open-telemetry/opentelemetry-
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"sort"
 	"strings"
 	"sync"
@@ -43,8 +44,11 @@ const (
 	statusCodeKey      = "status.code" // OpenTelemetry non-standard constant.
 	metricKeySeparator = string(byte(0))
 	traceIDKey         = "trace_id"
+	spanIDKey          = "span_id"
+	eventNameKey       = "event.name" // OpenTelemetry non-standard constant.
 
-	defaultDimensionsCacheSize = 1000
+	defaultDimensionsCacheSize   = 1000
+	defaultMaxExemplarsPerSeries = 10
 )
 
 var (
@@ -55,6 +59,7 @@ var (
 
 type exemplarData struct {
 	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
 	value   float64
 }
 
@@ -68,9 +73,17 @@ type processorImp struct {
 	metricsExporter component.MetricsExporter
 	nextConsumer    consumer.Traces
 
+	// The ID of the spanmetrics receiver to feed the metrics to, instead of the metrics exporter,
+	// and the consumer of its metrics pipelines found at start.
+	metricsReceiverID *config.ComponentID
+	metricsReceiver   consumer.Metrics
+
 	// Additional dimensions to add to metrics.
 	dimensions []Dimension
 
+	// Additional dimensions to add to the span event counters, fetched from the event's attributes.
+	eventDimensions []Dimension
+
 	// The starting time of the data points.
 	startTime time.Time
 
@@ -83,10 +96,20 @@ type processorImp struct {
 	latencyBucketCounts  map[metricKey][]uint64
 	latencyBounds        []float64
 	latencyExemplarsData map[metricKey][]exemplarData
+	// The number of spans seen by the exemplar reservoir of each latency series since the last export.
+	latencyExemplarsSeen map[metricKey]int
+
+	// Latency exponential histograms, replacing the explicit bucket histograms when enabled.
+	latencyExpHistograms map[metricKey]*exponentialHistogram
+
+	// Span event counts.
+	eventSum map[metricKey]int64
 
 	// An LRU cache of dimension key-value maps keyed by a unique identifier formed by a concatenation of its values:
 	// e.g. { "foo/barOK": { "serviceName": "foo", "operation": "/bar", "status_code": "OK" }}
 	metricKeyToDimensions *cache.Cache
+	// The dimensions of the span event counters, kept apart so that the events don't evict the span dimensions.
+	eventKeyToDimensions *cache.Cache
 }
 
 func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer consumer.Traces) (*processorImp, error) {
@@ -98,9 +121,26 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 		bounds = mapDurationsToMillis(pConfig.LatencyHistogramBuckets)
 	}
 
+	metricsReceiverID, err := pConfig.metricsReceiverID()
+	if err != nil {
+		return nil, err
+	}
+
+	if pConfig.MaxExemplarsPerSeries < 0 {
+		return nil, fmt.Errorf("invalid max exemplars per series: %v, it should not be negative", pConfig.MaxExemplarsPerSeries)
+	}
+
 	if err := validateDimensions(pConfig.Dimensions, pConfig.skipSanitizeLabel); err != nil {
 		return nil, err
 	}
+	if pConfig.Events.Enabled {
+		// The event counters have the span dimensions as well as their own.
+		eventDimensions := append([]Dimension{{Name: eventNameKey}}, pConfig.Dimensions...)
+		eventDimensions = append(eventDimensions, pConfig.Events.Dimensions...)
+		if err := validateDimensions(eventDimensions, pConfig.skipSanitizeLabel); err != nil {
+			return nil, fmt.Errorf("invalid events dimensions: %w", err)
+		}
+	}
 
 	if pConfig.DimensionsCacheSize <= 0 {
 		return nil, fmt.Errorf(
@@ -112,6 +152,10 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 	if err != nil {
 		return nil, err
 	}
+	eventKeyToDimensionsCache, err := cache.NewCache(pConfig.DimensionsCacheSize)
+	if err != nil {
+		return nil, err
+	}
 
 	return &processorImp{
 		logger:                logger,
@@ -123,9 +167,15 @@ func newProcessor(logger *zap.Logger, config config.Processor, nextConsumer cons
 		latencyCount:          make(map[metricKey]uint64),
 		latencyBucketCounts:   make(map[metricKey][]uint64),
 		latencyExemplarsData:  make(map[metricKey][]exemplarData),
+		latencyExemplarsSeen:  make(map[metricKey]int),
+		latencyExpHistograms:  make(map[metricKey]*exponentialHistogram),
+		eventSum:              make(map[metricKey]int64),
 		nextConsumer:          nextConsumer,
+		metricsReceiverID:     metricsReceiverID,
 		dimensions:            pConfig.Dimensions,
+		eventDimensions:       pConfig.Events.Dimensions,
 		metricKeyToDimensions: metricKeyToDimensionsCache,
+		eventKeyToDimensions:  eventKeyToDimensionsCache,
 	}, nil
 }
 
@@ -175,6 +225,13 @@ func validateDimensions(dimensions []Dimension, skipSanitizeLabel bool) error {
 // Start implements the component.Component interface.
 func (p *processorImp) Start(ctx context.Context, host component.Host) error {
 	p.logger.Info("Starting spanmetricsprocessor")
+	if p.metricsReceiverID != nil {
+		if err := p.findMetricsReceiver(host); err != nil {
+			return err
+		}
+		p.logger.Info("Started spanmetricsprocessor", zap.String("spanmetrics-receiver", p.metricsReceiverID.String()))
+		return nil
+	}
 	exporters := host.GetExporters()
 
 	var availableMetricsExporters []string
@@ -206,6 +263,28 @@ func (p *processorImp) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// metricsConsumerProvider is implemented by the factory of the spanmetrics receiver, which keeps
+// track of the receivers it created.
+type metricsConsumerProvider interface {
+	MetricsConsumer(id config.ComponentID) (consumer.Metrics, error)
+}
+
+// findMetricsReceiver finds the consumer of the metrics pipelines of the configured spanmetrics receiver,
+// through the receiver factory of the host. The receivers are all created before the processors are
+// started, so it is found even though the receivers are started after the processors.
+func (p *processorImp) findMetricsReceiver(host component.Host) error {
+	provider, ok := host.GetFactory(component.KindReceiver, typeStr).(metricsConsumerProvider)
+	if !ok {
+		return fmt.Errorf("failed to find metrics receiver: the %s receiver isn't available in this collector", typeStr)
+	}
+	metricsReceiver, err := provider.MetricsConsumer(*p.metricsReceiverID)
+	if err != nil {
+		return fmt.Errorf("failed to find metrics receiver: %w", err)
+	}
+	p.metricsReceiver = metricsReceiver
+	return nil
+}
+
 // Shutdown implements the component.Component interface.
 func (p *processorImp) Shutdown(ctx context.Context) error {
 	p.logger.Info("Shutting down spanmetricsprocessor")
@@ -242,13 +321,22 @@ func (p *processorImp) tracesToMetrics(ctx context.Context, traces ptrace.Traces
 		return err
 	}
 
-	if err = p.metricsExporter.ConsumeMetrics(ctx, *m); err != nil {
+	if err = p.metricsConsumer().ConsumeMetrics(ctx, *m); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// metricsConsumer returns the consumer of the generated metrics: the configured spanmetrics receiver
+// if any, otherwise the metrics exporter found at start.
+func (p *processorImp) metricsConsumer() consumer.Metrics {
+	if p.metricsReceiver != nil {
+		return p.metricsReceiver
+	}
+	return p.metricsExporter
+}
+
 // buildMetrics collects the computed raw metrics data, builds the metrics object and
 // writes the raw metrics data into the metrics object.
 func (p *processorImp) buildMetrics() (*pmetric.Metrics, error) {
@@ -260,11 +348,20 @@ func (p *processorImp) buildMetrics() (*pmetric.Metrics, error) {
 		return nil, err
 	}
 
-	if err := p.collectLatencyMetrics(ilm); err != nil {
+	if p.config.ExponentialHistogramMaxSize > 0 {
+		if err := p.collectLatencyExponentialMetrics(ilm); err != nil {
+			return nil, err
+		}
+	} else if err := p.collectLatencyMetrics(ilm); err != nil {
+		return nil, err
+	}
+
+	if err := p.collectEventMetrics(ilm); err != nil {
 		return nil, err
 	}
 
 	p.metricKeyToDimensions.RemoveEvictedItems()
+	p.eventKeyToDimensions.RemoveEvictedItems()
 
 	// If delta metrics, reset accumulated data
 	if p.config.GetAggregationTemporality() == pmetric.MetricAggregationTemporalityDelta {
@@ -308,6 +405,61 @@ func (p *processorImp) collectLatencyMetrics(ilm pmetric.ScopeMetrics) error {
 	return nil
 }
 
+// collectLatencyExponentialMetrics collects the raw latency metrics as exponential histograms,
+// writing the data into the given instrumentation library metrics.
+func (p *processorImp) collectLatencyExponentialMetrics(ilm pmetric.ScopeMetrics) error {
+	for key, histogram := range p.latencyExpHistograms {
+		mLatency := ilm.Metrics().AppendEmpty()
+		mLatency.SetDataType(pmetric.MetricDataTypeExponentialHistogram)
+		mLatency.SetName("latency")
+		mLatency.SetUnit("ms")
+		mLatency.ExponentialHistogram().SetAggregationTemporality(p.config.GetAggregationTemporality())
+
+		timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+		dpLatency := mLatency.ExponentialHistogram().DataPoints().AppendEmpty()
+		dpLatency.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+		dpLatency.SetTimestamp(timestamp)
+		histogram.copyTo(dpLatency)
+
+		setLatencyExemplars(p.latencyExemplarsData[key], timestamp, dpLatency.Exemplars())
+
+		dimensions, err := p.getDimensionsByMetricKey(key)
+		if err != nil {
+			p.logger.Error(err.Error())
+			return err
+		}
+
+		dimensions.CopyTo(dpLatency.Attributes())
+	}
+	return nil
+}
+
+// collectEventMetrics collects the raw span event count metrics, writing the data
+// into the given instrumentation library metrics.
+func (p *processorImp) collectEventMetrics(ilm pmetric.ScopeMetrics) error {
+	for key := range p.eventSum {
+		mEvents := ilm.Metrics().AppendEmpty()
+		mEvents.SetDataType(pmetric.MetricDataTypeSum)
+		mEvents.SetName("events_total")
+		mEvents.Sum().SetIsMonotonic(true)
+		mEvents.Sum().SetAggregationTemporality(p.config.GetAggregationTemporality())
+
+		dpEvents := mEvents.Sum().DataPoints().AppendEmpty()
+		dpEvents.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+		dpEvents.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+		dpEvents.SetIntVal(p.eventSum[key])
+
+		dimensions, err := getDimensionsByKey(p.eventKeyToDimensions, key)
+		if err != nil {
+			return err
+		}
+
+		dimensions.CopyTo(dpEvents.Attributes())
+	}
+	return nil
+}
+
 // collectCallMetrics collects the raw call count metrics, writing the data
 // into the given instrumentation library metrics.
 func (p *processorImp) collectCallMetrics(ilm pmetric.ScopeMetrics) error {
@@ -335,7 +487,12 @@ func (p *processorImp) collectCallMetrics(ilm pmetric.ScopeMetrics) error {
 
 // getDimensionsByMetricKey gets dimensions from `metricKeyToDimensions` cache.
 func (p *processorImp) getDimensionsByMetricKey(k metricKey) (*pcommon.Map, error) {
-	if item, ok := p.metricKeyToDimensions.Get(k); ok {
+	return getDimensionsByKey(p.metricKeyToDimensions, k)
+}
+
+// getDimensionsByKey gets dimensions from the given dimensions cache.
+func getDimensionsByKey(c *cache.Cache, k metricKey) (*pcommon.Map, error) {
+	if item, ok := c.Get(k); ok {
 		if attributeMap, ok := item.(pcommon.Map); ok {
 			return &attributeMap, nil
 		}
@@ -391,8 +548,39 @@ func (p *processorImp) aggregateMetricsForSpan(serviceName string, span ptrace.S
 
 	p.cache(serviceName, span, key, resourceAttr)
 	p.updateCallMetrics(key)
-	p.updateLatencyMetrics(key, latencyInMilliseconds, index)
-	p.updateLatencyExemplars(key, latencyInMilliseconds, span.TraceID())
+	if p.config.ExponentialHistogramMaxSize > 0 {
+		p.updateLatencyExponentialMetrics(key, latencyInMilliseconds)
+	} else {
+		p.updateLatencyMetrics(key, latencyInMilliseconds, index)
+	}
+	p.updateLatencyExemplars(key, latencyInMilliseconds, span.TraceID(), span.SpanID())
+
+	if p.config.Events.Enabled {
+		p.aggregateEventMetrics(serviceName, span, key, resourceAttr)
+	}
+}
+
+// aggregateEventMetrics counts the events of the span, by the span's metric key, the event name and
+// the additional event dimensions.
+func (p *processorImp) aggregateEventMetrics(serviceName string, span ptrace.Span, spanKey metricKey, resourceAttr pcommon.Map) {
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		key := buildEventKey(spanKey, event, p.eventDimensions)
+
+		// Use Get to ensure any existing key has its recent-ness updated.
+		if _, has := p.eventKeyToDimensions.Get(key); !has {
+			dims := p.buildDimensionKVs(serviceName, span, p.dimensions, resourceAttr)
+			dims.UpsertString(eventNameKey, event.Name())
+			for _, d := range p.eventDimensions {
+				if v, ok := getDimensionValue(d, event.Attributes(), pcommon.NewMap()); ok {
+					v.CopyTo(dims.UpsertEmpty(d.Name))
+				}
+			}
+			p.eventKeyToDimensions.Add(key, dims)
+		}
+		p.eventSum[key]++
+	}
 }
 
 // updateCallMetrics increments the call count for the given metric key.
@@ -400,27 +588,40 @@ func (p *processorImp) updateCallMetrics(key metricKey) {
 	p.callSum[key]++
 }
 
-// resetAccumulatedMetrics resets the internal maps used to store created metric data. Also purge the cache for
-// metricKeyToDimensions.
+// resetAccumulatedMetrics resets the internal maps used to store created metric data. Also purge the caches for
+// metricKeyToDimensions and eventKeyToDimensions.
 func (p *processorImp) resetAccumulatedMetrics() {
 	p.callSum = make(map[metricKey]int64)
 	p.latencyCount = make(map[metricKey]uint64)
 	p.latencySum = make(map[metricKey]float64)
 	p.latencyBucketCounts = make(map[metricKey][]uint64)
+	p.latencyExpHistograms = make(map[metricKey]*exponentialHistogram)
+	p.eventSum = make(map[metricKey]int64)
 	p.metricKeyToDimensions.Purge()
+	p.eventKeyToDimensions.Purge()
 }
 
-// updateLatencyExemplars sets the histogram exemplars for the given metric key and append the exemplar data.
-func (p *processorImp) updateLatencyExemplars(key metricKey, value float64, traceID pcommon.TraceID) {
-	if _, ok := p.latencyExemplarsData[key]; !ok {
-		p.latencyExemplarsData[key] = []exemplarData{}
+// updateLatencyExemplars adds the exemplar data to the reservoir of the given metric key. Once the reservoir
+// is full, the exemplar replaces a random one with a probability that keeps a uniform sample of all the spans:
+// https://en.wikipedia.org/wiki/Reservoir_sampling
+func (p *processorImp) updateLatencyExemplars(key metricKey, value float64, traceID pcommon.TraceID, spanID pcommon.SpanID) {
+	if p.config.MaxExemplarsPerSeries <= 0 {
+		return
 	}
 
 	e := exemplarData{
 		traceID: traceID,
+		spanID:  spanID,
 		value:   value,
 	}
-	p.latencyExemplarsData[key] = append(p.latencyExemplarsData[key], e)
+	p.latencyExemplarsSeen[key]++
+	if len(p.latencyExemplarsData[key]) < p.config.MaxExemplarsPerSeries {
+		p.latencyExemplarsData[key] = append(p.latencyExemplarsData[key], e)
+		return
+	}
+	if i := rand.Intn(p.latencyExemplarsSeen[key]); i < p.config.MaxExemplarsPerSeries {
+		p.latencyExemplarsData[key][i] = e
+	}
 }
 
 // resetExemplarData resets the entire exemplars map so the next trace will recreate all
@@ -428,6 +629,17 @@ func (p *processorImp) updateLatencyExemplars(key metricKey, value float64, trac
 // and should be not considered like a metrics that persist over time.
 func (p *processorImp) resetExemplarData() {
 	p.latencyExemplarsData = make(map[metricKey][]exemplarData)
+	p.latencyExemplarsSeen = make(map[metricKey]int)
+}
+
+// updateLatencyExponentialMetrics records the latency in the exponential histogram of the given metric key.
+func (p *processorImp) updateLatencyExponentialMetrics(key metricKey, latency float64) {
+	histogram, ok := p.latencyExpHistograms[key]
+	if !ok {
+		histogram = newExponentialHistogram(p.config.ExponentialHistogramMaxSize)
+		p.latencyExpHistograms[key] = histogram
+	}
+	histogram.record(latency)
 }
 
 // updateLatencyMetrics increments the histogram counts for the given metric key and bucket index.
@@ -485,6 +697,21 @@ func buildKey(serviceName string, span ptrace.Span, optionalDims []Dimension, re
 	return k
 }
 
+// buildEventKey builds the metric key of a span event counter from the metric key of its span, the event
+// name and the values of the additional event dimensions found in the event's attributes.
+func buildEventKey(spanKey metricKey, event ptrace.SpanEvent, eventDims []Dimension) metricKey {
+	var metricKeyBuilder strings.Builder
+	concatDimensionValue(&metricKeyBuilder, string(spanKey), false)
+	concatDimensionValue(&metricKeyBuilder, event.Name(), true)
+
+	for _, d := range eventDims {
+		if v, ok := getDimensionValue(d, event.Attributes(), pcommon.NewMap()); ok {
+			concatDimensionValue(&metricKeyBuilder, v.AsString(), true)
+		}
+	}
+	return metricKey(metricKeyBuilder.String())
+}
+
 // getDimensionValue gets the dimension value for the given configured dimension.
 // It searches through the span's attributes first, being the more specific;
 // falling back to searching in resource attributes if it can't be found in the span.
@@ -570,7 +797,12 @@ func setLatencyExemplars(exemplarsData []exemplarData, timestamp pcommon.Timesta
 
 		exemplar.SetDoubleVal(value)
 		exemplar.SetTimestamp(timestamp)
+		exemplar.SetTraceID(traceID)
 		exemplar.FilteredAttributes().Insert(traceIDKey, pcommon.NewValueString(traceID.HexString()))
+		if !ed.spanID.IsEmpty() {
+			exemplar.SetSpanID(ed.spanID)
+			exemplar.FilteredAttributes().Insert(spanIDKey, pcommon.NewValueString(ed.spanID.HexString()))
+		}
 	}
 
 	es.CopyTo(exemplars)
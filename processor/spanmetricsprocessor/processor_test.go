@@ -39,6 +39,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/spanmetricsprocessor/mocks"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/spanmetricsreceiver"
 )
 
 const (
@@ -129,6 +130,84 @@ func TestProcessorStart(t *testing.T) {
 	}
 }
 
+func TestProcessorMetricsReceiver(t *testing.T) {
+	// Prepare
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsReceiver = "spanmetrics/pipeline"
+
+	tcon := &mocks.TracesConsumer{}
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, tcon)
+	require.NoError(t, err)
+	assert.Error(t, p.Start(context.Background(), componenttest.NewNopHost()), "Must fail to start without the receiver factory")
+
+	rcvrFactory := spanmetricsreceiver.NewFactory()
+	mhost := &mocks.Host{}
+	mhost.On("GetFactory", component.KindReceiver, config.Type(typeStr)).Return(rcvrFactory)
+	assert.Error(t, p.Start(context.Background(), mhost), "Must fail to start without the receiver")
+
+	// The receivers are created before the processors are started, and started after them.
+	rcvrCfg := rcvrFactory.CreateDefaultConfig()
+	rcvrCfg.SetIDName("pipeline")
+	sink := new(consumertest.MetricsSink)
+	rcvr, err := rcvrFactory.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), rcvrCfg, sink)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, rcvr.Shutdown(context.Background())) }()
+	require.NoError(t, p.Start(context.Background(), mhost), "Must not look up the exporters")
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	// Test
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+
+	// Verify
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 6, sink.AllMetrics()[0].MetricCount())
+}
+
+func TestProcessorMetricsReceiverOfAnotherCollector(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsReceiver = "spanmetrics"
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	// The receiver is created by the factory of another collector.
+	otherFactory := spanmetricsreceiver.NewFactory()
+	rcvr, err := otherFactory.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), otherFactory.CreateDefaultConfig(), consumertest.NewNop())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+	mhost := &mocks.Host{}
+	mhost.On("GetFactory", component.KindReceiver, config.Type(typeStr)).Return(spanmetricsreceiver.NewFactory())
+	assert.Error(t, p.Start(context.Background(), mhost))
+}
+
+func TestProcessorInvalidMetricsReceiver(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		metricsExporter string
+		metricsReceiver string
+	}{
+		{name: "with metrics exporter", metricsExporter: "otlp", metricsReceiver: "spanmetrics"},
+		{name: "not a spanmetrics receiver", metricsReceiver: "otlp"},
+		{name: "invalid id", metricsReceiver: "spanmetrics/"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.MetricsExporter = tc.metricsExporter
+			cfg.MetricsReceiver = tc.metricsReceiver
+
+			assert.Error(t, cfg.Validate())
+			p, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+			assert.Error(t, err)
+			assert.Nil(t, p)
+		})
+	}
+}
+
 func TestProcessorShutdown(t *testing.T) {
 	// Prepare
 	factory := NewFactory()
@@ -373,6 +452,10 @@ func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, de
 	if err != nil {
 		panic(err)
 	}
+	eventKeyToDimensions, err := cache.NewCache(DimensionsCacheSize)
+	if err != nil {
+		panic(err)
+	}
 	return &processorImp{
 		logger:          logger,
 		config:          Config{AggregationTemporality: temporality},
@@ -386,6 +469,9 @@ func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, de
 		latencyBucketCounts:  make(map[metricKey][]uint64),
 		latencyBounds:        defaultLatencyHistogramBucketsMs,
 		latencyExemplarsData: make(map[metricKey][]exemplarData),
+		latencyExemplarsSeen: make(map[metricKey]int),
+		latencyExpHistograms: make(map[metricKey]*exponentialHistogram),
+		eventSum:             make(map[metricKey]int64),
 		dimensions: []Dimension{
 			// Set nil defaults to force a lookup for the attribute in the span.
 			{stringAttrName, nil},
@@ -403,6 +489,7 @@ func newProcessorImp(mexp *mocks.MetricsExporter, tcon *mocks.TracesConsumer, de
 			{regionResourceAttrName, nil},
 		},
 		metricKeyToDimensions: metricKeyToDimensions,
+		eventKeyToDimensions:  eventKeyToDimensions,
 	}
 }
 
@@ -752,6 +839,180 @@ func TestProcessorDuplicateDimensions(t *testing.T) {
 	assert.Nil(t, p)
 }
 
+func TestProcessorInvalidHistogramConfig(t *testing.T) {
+	factory := NewFactory()
+
+	for _, size := range []int32{-1, 1, 2} {
+		cfg := factory.CreateDefaultConfig().(*Config)
+		cfg.ExponentialHistogramMaxSize = size
+		assert.Error(t, cfg.Validate(), size)
+	}
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.ExponentialHistogramMaxSize = exponentialHistogramMinSize
+	assert.NoError(t, cfg.Validate())
+
+	cfg = factory.CreateDefaultConfig().(*Config)
+	cfg.ExponentialHistogramMaxSize = 160
+	cfg.LatencyHistogramBuckets = []time.Duration{time.Millisecond}
+	assert.EqualError(t, cfg.Validate(), "latency histogram buckets can't be configured with exponential histograms")
+
+	cfg = factory.CreateDefaultConfig().(*Config)
+	cfg.MaxExemplarsPerSeries = -1
+	_, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+	assert.Error(t, err)
+}
+
+func TestProcessorDuplicateEventDimensions(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Dimensions = []Dimension{{Name: "exception.type"}}
+	cfg.Events = EventsConfig{
+		Enabled:    true,
+		Dimensions: []Dimension{{Name: "exception.type"}},
+	}
+
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestProcessorExponentialHistogram(t *testing.T) {
+	// Prepare
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+
+	var metrics pmetric.Metrics
+	mexp.On("ConsumeMetrics", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		metrics = args.Get(1).(pmetric.Metrics)
+	}).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	p := newProcessorImp(mexp, tcon, nil, cumulative, zaptest.NewLogger(t))
+	p.config.ExponentialHistogramMaxSize = 160
+	p.config.MaxExemplarsPerSeries = 1
+
+	// Test
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	require.NoError(t, p.ConsumeTraces(ctx, buildSampleTrace()))
+
+	// Verify
+	var latencies int
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "latency" {
+			continue
+		}
+		latencies++
+		require.Equal(t, pmetric.MetricDataTypeExponentialHistogram, m.DataType())
+		assert.Equal(t, "ms", m.Unit())
+		assert.Equal(t, pmetric.MetricAggregationTemporalityCumulative, m.ExponentialHistogram().AggregationTemporality())
+
+		dp := m.ExponentialHistogram().DataPoints().At(0)
+		assert.Equal(t, uint64(1), dp.Count())
+		assert.Equal(t, sampleLatency, dp.Sum())
+		assert.Equal(t, mapToIndex(sampleLatency, dp.Scale()), dp.Positive().Offset())
+		assert.Equal(t, []uint64{1}, dp.Positive().BucketCounts().AsRaw())
+		assert.Equal(t, 1, dp.Exemplars().Len())
+	}
+	assert.Equal(t, 3, latencies)
+}
+
+func TestProcessorEventMetrics(t *testing.T) {
+	// Prepare
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+
+	var metrics pmetric.Metrics
+	mexp.On("ConsumeMetrics", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		metrics = args.Get(1).(pmetric.Metrics)
+	}).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	unknown := "unknown"
+	p := newProcessorImp(mexp, tcon, nil, cumulative, zaptest.NewLogger(t))
+	p.dimensions = nil
+	p.config.Events = EventsConfig{Enabled: true}
+	p.eventDimensions = []Dimension{{Name: "exception.type", Default: &unknown}}
+
+	traces := buildSampleTrace()
+	span := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	for _, exceptionType := range []string{"java.io.IOException", "java.io.IOException", "java.lang.NullPointerException"} {
+		event := span.Events().AppendEmpty()
+		event.SetName("exception")
+		event.Attributes().InsertString("exception.type", exceptionType)
+	}
+	span.Events().AppendEmpty().SetName("exception")
+
+	// Test
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	require.NoError(t, p.ConsumeTraces(ctx, traces))
+
+	// Verify
+	counts := make(map[string]int64)
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		if m.Name() != "events_total" {
+			continue
+		}
+		require.Equal(t, pmetric.MetricDataTypeSum, m.DataType())
+		assert.True(t, m.Sum().IsMonotonic())
+
+		dp := m.Sum().DataPoints().At(0)
+		attrs := dp.Attributes().AsRaw()
+		assert.Equal(t, "service-a", attrs[serviceNameKey])
+		assert.Equal(t, "/ping", attrs[operationKey])
+		assert.Equal(t, "SPAN_KIND_SERVER", attrs[spanKindKey])
+		assert.Equal(t, "exception", attrs[eventNameKey])
+		counts[attrs["exception.type"].(string)] = dp.IntVal()
+	}
+	assert.Equal(t, map[string]int64{
+		"java.io.IOException":            2,
+		"java.lang.NullPointerException": 1,
+		"unknown":                        1,
+	}, counts)
+}
+
+func TestProcessorEventsDoNotEvictSpanDimensions(t *testing.T) {
+	// Prepare
+	mexp := &mocks.MetricsExporter{}
+	tcon := &mocks.TracesConsumer{}
+
+	mexp.On("ConsumeMetrics", mock.Anything, mock.Anything).Return(nil)
+	tcon.On("ConsumeTraces", mock.Anything, mock.Anything).Return(nil)
+
+	p := newProcessorImp(mexp, tcon, nil, cumulative, zaptest.NewLogger(t))
+	p.dimensions = nil
+	p.config.Events = EventsConfig{Enabled: true}
+
+	newTraces := func(operations ...string) ptrace.Traces {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, "service-a")
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		for _, operation := range operations {
+			span := spans.AppendEmpty()
+			span.SetName(operation)
+			span.Events().AppendEmpty().SetName("exception")
+		}
+		return traces
+	}
+
+	// Test
+	ctx := metadata.NewIncomingContext(context.Background(), nil)
+	// Fill the dimensions cache with the span keys, each span adding an event key as well.
+	require.NoError(t, p.ConsumeTraces(ctx, newTraces("/a", "/b")))
+	assert.Len(t, p.metricKeyToDimensions.Keys(), DimensionsCacheSize)
+	assert.Len(t, p.eventKeyToDimensions.Keys(), DimensionsCacheSize)
+
+	// The cumulative metrics of "/b" are built from the cached dimensions even though it isn't seen again.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, p.ConsumeTraces(ctx, newTraces("/a")))
+	}
+}
+
 func TestValidateDimensions(t *testing.T) {
 	for _, tc := range []struct {
 		name              string
@@ -845,7 +1106,8 @@ func TestSetLatencyExemplars(t *testing.T) {
 	timestamp := pcommon.NewTimestampFromTime(time.Now())
 	value := float64(42)
 
-	ed := []exemplarData{{traceID: traceID, value: value}}
+	spanID := pcommon.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	ed := []exemplarData{{traceID: traceID, spanID: spanID, value: value}}
 
 	// ----- call -------------------------------------------------------------
 	setLatencyExemplars(ed, timestamp, exemplarSlice)
@@ -858,6 +1120,11 @@ func TestSetLatencyExemplars(t *testing.T) {
 	assert.Equal(t, traceIDValue.AsString(), traceID.HexString())
 	assert.Equal(t, exemplarSlice.At(0).Timestamp(), timestamp)
 	assert.Equal(t, exemplarSlice.At(0).DoubleVal(), value)
+	assert.Equal(t, traceID, exemplarSlice.At(0).TraceID())
+	assert.Equal(t, spanID, exemplarSlice.At(0).SpanID())
+	spanIDValue, exist := exemplarSlice.At(0).FilteredAttributes().Get(spanIDKey)
+	assert.True(t, exist)
+	assert.Equal(t, spanID.HexString(), spanIDValue.AsString())
 }
 
 func TestProcessorUpdateLatencyExemplars(t *testing.T) {
@@ -866,18 +1133,60 @@ func TestProcessorUpdateLatencyExemplars(t *testing.T) {
 	cfg := factory.CreateDefaultConfig().(*Config)
 	traces := buildSampleTrace()
 	traceID := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+	spanID := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SpanID()
 	key := metricKey("metricKey")
 	next := new(consumertest.TracesSink)
 	p, err := newProcessor(zaptest.NewLogger(t), cfg, next)
 	value := float64(42)
 
 	// ----- call -------------------------------------------------------------
-	p.updateLatencyExemplars(key, value, traceID)
+	p.updateLatencyExemplars(key, value, traceID, spanID)
 
 	// ----- verify -----------------------------------------------------------
 	assert.NoError(t, err)
 	assert.NotEmpty(t, p.latencyExemplarsData[key])
-	assert.Equal(t, p.latencyExemplarsData[key][0], exemplarData{traceID: traceID, value: value})
+	assert.Equal(t, p.latencyExemplarsData[key][0], exemplarData{traceID: traceID, spanID: spanID, value: value})
+}
+
+func TestProcessorLatencyExemplarsReservoir(t *testing.T) {
+	// ----- conditions -------------------------------------------------------
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MaxExemplarsPerSeries = 3
+	key := metricKey("metricKey")
+	next := new(consumertest.TracesSink)
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, next)
+	require.NoError(t, err)
+
+	// ----- call -------------------------------------------------------------
+	for i := 0; i < 100; i++ {
+		p.updateLatencyExemplars(key, float64(i), pcommon.NewTraceID([16]byte{byte(i + 1)}), pcommon.NewSpanID([8]byte{byte(i + 1)}))
+	}
+
+	// ----- verify -----------------------------------------------------------
+	assert.Len(t, p.latencyExemplarsData[key], 3)
+	assert.Equal(t, 100, p.latencyExemplarsSeen[key])
+	for _, e := range p.latencyExemplarsData[key] {
+		assert.Equal(t, byte(e.value)+1, e.traceID.Bytes()[0], "Must keep the trace ID of the exemplar's span")
+		assert.Equal(t, byte(e.value)+1, e.spanID.Bytes()[0], "Must keep the span ID of the exemplar's span")
+	}
+
+	p.resetExemplarData()
+	assert.Empty(t, p.latencyExemplarsData[key])
+	assert.Zero(t, p.latencyExemplarsSeen[key])
+}
+
+func TestProcessorLatencyExemplarsDisabled(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MaxExemplarsPerSeries = 0
+	key := metricKey("metricKey")
+	p, err := newProcessor(zaptest.NewLogger(t), cfg, new(consumertest.TracesSink))
+	require.NoError(t, err)
+
+	p.updateLatencyExemplars(key, 42, pcommon.NewTraceID([16]byte{1}), pcommon.NewSpanID([8]byte{1}))
+
+	assert.Empty(t, p.latencyExemplarsData[key])
 }
 
 func TestProcessorResetExemplarData(t *testing.T) {
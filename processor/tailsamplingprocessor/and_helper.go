@@ -54,6 +54,9 @@ func getAndSubPolicyEvaluator(logger *zap.Logger, cfg *AndSubPolicyCfg) (samplin
 	case TraceState:
 		tsfCfg := cfg.TraceStateCfg
 		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values), nil
+	case Condition:
+		cfCfg := cfg.ConditionCfg
+		return sampling.NewConditionFilter(logger, cfCfg.Conditions, cfCfg.MatchAllSpans)
 	case SpanCount:
 		scfCfg := cfg.SpanCountCfg
 		return sampling.NewSpanCount(logger, scfCfg.MinSpans), nil
@@ -63,6 +63,11 @@ func TestAndHelper(t *testing.T) {
 				Type:         SpanCount,
 				SpanCountCfg: SpanCountCfg{MinSpans: 2},
 			},
+			{
+				Name:         "test-and-policy-7",
+				Type:         Condition,
+				ConditionCfg: ConditionCfg{Conditions: []string{`attributes["http.status_code"] == 500`}},
+			},
 		},
 	}
 
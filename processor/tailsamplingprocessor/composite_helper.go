@@ -85,6 +85,9 @@ func getSubPolicyEvaluator(logger *zap.Logger, cfg *SubPolicyCfg) (sampling.Poli
 	case TraceState:
 		tsfCfg := cfg.TraceStateCfg
 		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values), nil
+	case Condition:
+		cfCfg := cfg.ConditionCfg
+		return sampling.NewConditionFilter(logger, cfCfg.Conditions, cfCfg.MatchAllSpans)
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
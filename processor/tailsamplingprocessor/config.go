@@ -48,6 +48,8 @@ const (
 	SpanCount PolicyType = "span_count"
 	// TraceState sample traces with specified values by the given key
 	TraceState PolicyType = "trace_state"
+	// Condition sample traces that have spans matching TQL conditions.
+	Condition PolicyType = "condition"
 )
 
 // SubPolicyCfg holds the common configuration to all policies under composite policy.
@@ -74,6 +76,8 @@ type SubPolicyCfg struct {
 	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
 	// Configs for trace_state policy evaluator.
 	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for condition policy evaluator.
+	ConditionCfg ConditionCfg `mapstructure:"condition"`
 }
 
 type AndSubPolicyCfg struct {
@@ -97,6 +101,8 @@ type AndSubPolicyCfg struct {
 	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
 	// Configs for trace_state filter sampling policy evaluator
 	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for condition filter sampling policy evaluator.
+	ConditionCfg ConditionCfg `mapstructure:"condition"`
 }
 
 type TraceStateCfg struct {
@@ -151,6 +157,8 @@ type PolicyCfg struct {
 	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
 	// Configs for defining trace_state policy
 	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for defining condition policy
+	ConditionCfg ConditionCfg `mapstructure:"condition"`
 }
 
 // LatencyCfg holds the configurable settings to create a latency filter sampling policy
@@ -223,6 +231,28 @@ type SpanCountCfg struct {
 	MinSpans int32 `mapstructure:"min_spans"`
 }
 
+// ConditionCfg holds the configurable settings to create a TQL condition filter
+// sampling policy evaluator.
+type ConditionCfg struct {
+	// Conditions are TQL conditions evaluated against every span of the trace, using
+	// the traces context. A span matches when any of the conditions is true.
+	Conditions []string `mapstructure:"conditions"`
+	// MatchAllSpans determines whether all the spans of the trace must match for the
+	// trace to be sampled, instead of any span.
+	MatchAllSpans bool `mapstructure:"match_all_spans"`
+}
+
+// DecisionCacheCfg holds the configurable settings of the cache keeping the sampling decisions
+// of the traces, used to handle spans arriving after the trace has been removed from memory.
+type DecisionCacheCfg struct {
+	// Size is the maximum number of decisions kept in the cache. Defaults to zero, i.e.: decisions
+	// are not cached.
+	Size int `mapstructure:"size"`
+	// StorageID is the ID of the storage extension used to persist the cached decisions across
+	// restarts. When not set, the decisions are only kept in memory.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -238,4 +268,7 @@ type Config struct {
 	// PolicyCfgs sets the tail-based sampling policy which makes a sampling decision
 	// for a given trace when requested.
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
+	// DecisionCache sets the cache of the sampling decisions, which are applied to the spans
+	// arriving after their trace has been removed from memory.
+	DecisionCache DecisionCacheCfg `mapstructure:"decision_cache"`
 }
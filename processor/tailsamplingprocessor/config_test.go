@@ -45,6 +45,7 @@ func TestLoadConfig(t *testing.T) {
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
+			DecisionCache:           DecisionCacheCfg{Size: 1000},
 			PolicyCfgs: []PolicyCfg{
 				{
 					Name: "test-policy-1",
@@ -90,6 +91,14 @@ func TestLoadConfig(t *testing.T) {
 					Type:          TraceState,
 					TraceStateCfg: TraceStateCfg{Key: "key3", Values: []string{"value1", "value2"}},
 				},
+				{
+					Name: "test-policy-10",
+					Type: Condition,
+					ConditionCfg: ConditionCfg{
+						Conditions:    []string{`attributes["http.status_code"] == 500`, `resource.attributes["service.name"] == "checkout"`},
+						MatchAllSpans: true,
+					},
+				},
 				{
 					Name: "and-policy-1",
 					Type: And,
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache defines a bounded cache of the sampling decisions taken for
+// traces, so spans arriving after a decision can be handled right away.
+package cache // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+// The decisions are persisted incrementally: every slot of the ring buffer is stored under its
+// own key, and headKey holds the position of the next slot and the size of the ring buffer.
+const (
+	headKey       = "decisions_head"
+	slotKeyPrefix = "decision_"
+)
+
+// entrySize is the size of a persisted entry: the trace ID followed by the decision.
+const entrySize = 17
+
+// headSize is the size of the persisted head: the next slot and the size of the ring buffer.
+const headSize = 8
+
+var (
+	// ErrInvalidSize occurs when an invalid cache size is specified.
+	ErrInvalidSize = errors.New("invalid decision cache size, it must be greater than zero")
+)
+
+type entry struct {
+	id       pcommon.TraceID
+	decision sampling.Decision
+}
+
+// DecisionCache keeps the sampling decisions of the most recently decided traces. Once
+// the cache is full, the oldest decision is evicted to make room for the new one.
+// It is safe for concurrent use.
+type DecisionCache struct {
+	sync.Mutex
+	// slots maps the cached trace IDs to their slot in entries.
+	slots map[pcommon.TraceID]int
+	// entries is a ring buffer holding the cached decisions in insertion order.
+	entries []entry
+	next    int
+	full    bool
+	// dirty holds the slots changed since the last Flush.
+	dirty map[int]struct{}
+}
+
+// New creates a DecisionCache holding up to size decisions.
+func New(size int) (*DecisionCache, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	return &DecisionCache{
+		slots:   make(map[pcommon.TraceID]int, size),
+		entries: make([]entry, size),
+		dirty:   make(map[int]struct{}),
+	}, nil
+}
+
+// Get returns the decision cached for the given trace ID, if any.
+func (c *DecisionCache) Get(id pcommon.TraceID) (sampling.Decision, bool) {
+	c.Lock()
+	defer c.Unlock()
+	slot, ok := c.slots[id]
+	if !ok {
+		return sampling.Unspecified, false
+	}
+	return c.entries[slot].decision, true
+}
+
+// Put caches the decision taken for the given trace ID, evicting the oldest
+// decision if the cache is full.
+func (c *DecisionCache) Put(id pcommon.TraceID, decision sampling.Decision) {
+	c.Lock()
+	defer c.Unlock()
+	c.put(id, decision)
+}
+
+func (c *DecisionCache) put(id pcommon.TraceID, decision sampling.Decision) {
+	if slot, ok := c.slots[id]; ok {
+		c.entries[slot].decision = decision
+		c.dirty[slot] = struct{}{}
+		return
+	}
+	if c.full {
+		delete(c.slots, c.entries[c.next].id)
+	}
+	c.entries[c.next] = entry{id: id, decision: decision}
+	c.slots[id] = c.next
+	c.dirty[c.next] = struct{}{}
+	c.next++
+	if c.next == len(c.entries) {
+		c.next = 0
+		c.full = true
+	}
+}
+
+// Len returns the number of cached decisions.
+func (c *DecisionCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.slots)
+}
+
+// Flush persists the decisions cached since the previous Flush using the given storage client.
+func (c *DecisionCache) Flush(ctx context.Context, client storage.Client) error {
+	c.Lock()
+	if len(c.dirty) == 0 {
+		c.Unlock()
+		return nil
+	}
+	dirty := c.dirty
+	c.dirty = make(map[int]struct{})
+	ops := make([]storage.Operation, 0, len(dirty)+1)
+	for slot := range dirty {
+		e := c.entries[slot]
+		traceID := e.id.Bytes()
+		buf := make([]byte, 0, entrySize)
+		buf = append(buf, traceID[:]...)
+		buf = append(buf, byte(e.decision))
+		ops = append(ops, storage.SetOperation(slotKey(slot), buf))
+	}
+	head := make([]byte, headSize)
+	binary.BigEndian.PutUint32(head, uint32(c.next))
+	binary.BigEndian.PutUint32(head[4:], uint32(len(c.entries)))
+	ops = append(ops, storage.SetOperation(headKey, head))
+	c.Unlock()
+
+	if err := client.Batch(ctx, ops...); err != nil {
+		// Persist the slots again on the next Flush, with their latest content.
+		c.Lock()
+		for slot := range dirty {
+			c.dirty[slot] = struct{}{}
+		}
+		c.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Load restores the decisions previously persisted using the given storage client.
+// When more decisions were persisted than the cache can hold, only the most recent
+// ones are kept.
+func (c *DecisionCache) Load(ctx context.Context, client storage.Client) error {
+	head, err := client.Get(ctx, headKey)
+	if err != nil || head == nil {
+		return err
+	}
+	if len(head) != headSize {
+		return fmt.Errorf("invalid persisted decision cache head of %d bytes", len(head))
+	}
+	next := int(binary.BigEndian.Uint32(head))
+	size := int(binary.BigEndian.Uint32(head[4:]))
+	if next >= size {
+		return fmt.Errorf("invalid persisted decision cache head, slot %d out of %d", next, size)
+	}
+
+	// Read the slots from the oldest to the most recent one.
+	ops := make([]storage.Operation, size)
+	for i := range ops {
+		ops[i] = storage.GetOperation(slotKey((next + i) % size))
+	}
+	if err = client.Batch(ctx, ops...); err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for _, op := range ops {
+		// Slots are never written when the cache was not full yet.
+		if op.Value == nil {
+			continue
+		}
+		if len(op.Value) != entrySize {
+			return fmt.Errorf("invalid persisted decision of %d bytes", len(op.Value))
+		}
+		var traceID [16]byte
+		copy(traceID[:], op.Value[:16])
+		c.put(pcommon.NewTraceID(traceID), sampling.Decision(op.Value[16]))
+	}
+
+	// The cache is smaller than the persisted one, its last slots are not used anymore.
+	if size > len(c.entries) {
+		stale := make([]storage.Operation, 0, size-len(c.entries))
+		for slot := len(c.entries); slot < size; slot++ {
+			stale = append(stale, storage.DeleteOperation(slotKey(slot)))
+		}
+		return client.Batch(ctx, stale...)
+	}
+	return nil
+}
+
+func slotKey(slot int) string {
+	return slotKeyPrefix + strconv.Itoa(slot)
+}
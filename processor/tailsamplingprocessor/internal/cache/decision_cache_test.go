@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+func TestNewInvalidSize(t *testing.T) {
+	c, err := New(0)
+	assert.Nil(t, c)
+	assert.Equal(t, ErrInvalidSize, err)
+}
+
+func TestPutAndGet(t *testing.T) {
+	c, err := New(2)
+	require.NoError(t, err)
+
+	_, ok := c.Get(traceID(1))
+	assert.False(t, ok)
+
+	c.Put(traceID(1), sampling.Sampled)
+	c.Put(traceID(2), sampling.NotSampled)
+
+	decision, ok := c.Get(traceID(1))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.Sampled, decision)
+	decision, ok = c.Get(traceID(2))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.NotSampled, decision)
+
+	// overwriting an existing decision doesn't evict anything
+	c.Put(traceID(1), sampling.NotSampled)
+	decision, ok = c.Get(traceID(1))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.NotSampled, decision)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestEvictsOldest(t *testing.T) {
+	c, err := New(2)
+	require.NoError(t, err)
+
+	c.Put(traceID(1), sampling.Sampled)
+	c.Put(traceID(2), sampling.Sampled)
+	c.Put(traceID(3), sampling.NotSampled)
+
+	_, ok := c.Get(traceID(1))
+	assert.False(t, ok)
+	_, ok = c.Get(traceID(2))
+	assert.True(t, ok)
+	_, ok = c.Get(traceID(3))
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestFlushAndLoad(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, config.NewComponentID("tail_sampling"), "")
+
+	c, err := New(3)
+	require.NoError(t, err)
+	for i := byte(1); i <= 2; i++ {
+		c.Put(traceID(i), sampling.Sampled)
+	}
+	require.NoError(t, c.Flush(ctx, client))
+	// only the decisions cached since the previous flush are written
+	for i := byte(3); i <= 4; i++ {
+		c.Put(traceID(i), sampling.Sampled)
+	}
+	c.Put(traceID(4), sampling.NotSampled)
+	require.NoError(t, c.Flush(ctx, client))
+
+	// a cache of the same size restores all the decisions
+	restored, err := New(3)
+	require.NoError(t, err)
+	require.NoError(t, restored.Load(ctx, client))
+	assert.Equal(t, 3, restored.Len())
+	for i := byte(2); i <= 3; i++ {
+		decision, ok := restored.Get(traceID(i))
+		assert.True(t, ok)
+		assert.Equal(t, sampling.Sampled, decision)
+	}
+	decision, ok := restored.Get(traceID(4))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.NotSampled, decision)
+
+	// a smaller cache keeps the most recent decisions only
+	restored, err = New(2)
+	require.NoError(t, err)
+	require.NoError(t, restored.Load(ctx, client))
+	assert.Equal(t, 2, restored.Len())
+	_, ok = restored.Get(traceID(2))
+	assert.False(t, ok)
+	decision, ok = restored.Get(traceID(3))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.Sampled, decision)
+	decision, ok = restored.Get(traceID(4))
+	assert.True(t, ok)
+	assert.Equal(t, sampling.NotSampled, decision)
+
+	// the slots the smaller cache does not use anymore are deleted
+	require.NoError(t, restored.Flush(ctx, client))
+	stale, err := client.Get(ctx, slotKey(2))
+	require.NoError(t, err)
+	assert.Nil(t, stale)
+	reloaded, err := New(3)
+	require.NoError(t, err)
+	require.NoError(t, reloaded.Load(ctx, client))
+	assert.Equal(t, 2, reloaded.Len())
+}
+
+func TestFlushNothingCached(t *testing.T) {
+	ctx := context.Background()
+	client := storagetest.NewInMemoryClient(component.KindProcessor, config.NewComponentID("tail_sampling"), "")
+
+	c, err := New(2)
+	require.NoError(t, err)
+	require.NoError(t, c.Flush(ctx, client))
+	head, err := client.Get(ctx, headKey)
+	require.NoError(t, err)
+	assert.Nil(t, head)
+}
+
+func TestLoadNothingPersisted(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, config.NewComponentID("tail_sampling"), "")
+
+	c, err := New(2)
+	require.NoError(t, err)
+	require.NoError(t, c.Load(context.Background(), client))
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]map[string][]byte{
+		"head size":  {headKey: {1, 2, 3}},
+		"head slot":  {headKey: {0, 0, 0, 2, 0, 0, 0, 2}},
+		"entry size": {headKey: {0, 0, 0, 0, 0, 0, 0, 2}, slotKey(0): {1, 2, 3}},
+	}
+	for name, persisted := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			client := storagetest.NewInMemoryClient(component.KindProcessor, config.NewComponentID("tail_sampling"), "")
+			for k, v := range persisted {
+				require.NoError(t, client.Set(ctx, k, v))
+			}
+
+			c, err := New(2)
+			require.NoError(t, err)
+			assert.Error(t, c.Load(ctx, client))
+		})
+	}
+}
+
+func traceID(b byte) pcommon.TraceID {
+	return pcommon.NewTraceID([16]byte{b, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+}
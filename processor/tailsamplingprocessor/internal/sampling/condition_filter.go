@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqltraces"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlcommon"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlotel"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+)
+
+// conditionFunctions contains the TQL functions that can be used in conditions.
+var conditionFunctions = map[string]interface{}{
+	"TraceID": tqlotel.TraceID,
+	"SpanID":  tqlotel.SpanID,
+	"IsMatch": tqlcommon.IsMatch,
+	"Concat":  tqlcommon.Concat,
+	"Int":     tqlcommon.Int,
+	"Double":  tqlcommon.Double,
+	"String":  tqlcommon.String,
+	"Bool":    tqlcommon.Bool,
+}
+
+type conditionFilter struct {
+	logger     *zap.Logger
+	conditions []tql.BoolExpressionEvaluator
+	matchAll   bool
+}
+
+var _ PolicyEvaluator = (*conditionFilter)(nil)
+
+// NewConditionFilter creates a policy evaluator that samples traces based on TQL conditions
+// evaluated against every span, using the traces context. A span matches when any of the
+// conditions is true. Traces are sampled when any span matches, or when all the spans match
+// if matchAll is true.
+func NewConditionFilter(logger *zap.Logger, conditions []string, matchAll bool) (PolicyEvaluator, error) {
+	if len(conditions) == 0 {
+		return nil, errors.New("expected at least one condition to filter on")
+	}
+
+	evaluators, err := tql.ParseConditions(conditions, conditionFunctions, tqltraces.ParsePath, tqltraces.ParseEnum)
+	if err != nil {
+		return nil, err
+	}
+	return &conditionFilter{
+		logger:     logger,
+		conditions: evaluators,
+		matchAll:   matchAll,
+	}, nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (cf *conditionFilter) Evaluate(_ pcommon.TraceID, trace *TraceData) (Decision, error) {
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+
+	spanCount := 0
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			rs := rspans.At(i)
+			ilss := rs.ScopeSpans()
+			for j := 0; j < ilss.Len(); j++ {
+				ils := ilss.At(j)
+				spans := ils.Spans()
+				for k := 0; k < spans.Len(); k++ {
+					spanCount++
+					matched := cf.matches(spans.At(k), ils.Scope(), rs.Resource())
+					if matched && !cf.matchAll {
+						return Sampled, nil
+					}
+					if !matched && cf.matchAll {
+						return NotSampled, nil
+					}
+				}
+			}
+		}
+	}
+
+	if cf.matchAll && spanCount > 0 {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+func (cf *conditionFilter) matches(span ptrace.Span, scope pcommon.InstrumentationScope, resource pcommon.Resource) bool {
+	tCtx := tqltraces.NewTransformContext(span, scope, resource)
+	for _, condition := range cf.conditions {
+		if condition(tCtx) {
+			return true
+		}
+	}
+	return false
+}
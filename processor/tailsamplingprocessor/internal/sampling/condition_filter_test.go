@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func TestConditionFilter(t *testing.T) {
+	cases := []struct {
+		Desc       string
+		Trace      *TraceData
+		Conditions []string
+		MatchAll   bool
+		Decision   Decision
+	}{
+		{
+			Desc:       "matching span attribute",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}, map[string]interface{}{"http.status_code": 500}, map[string]interface{}{"http.status_code": 200}),
+			Conditions: []string{`attributes["http.status_code"] == 500`},
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "nonmatching span attribute",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}, map[string]interface{}{"http.status_code": 200}),
+			Conditions: []string{`attributes["http.status_code"] == 500`},
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "any of the conditions matching",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}, map[string]interface{}{"http.method": "POST"}),
+			Conditions: []string{`attributes["http.status_code"] == 500`, `attributes["http.method"] == "POST"`},
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching resource attribute",
+			Trace:      newConditionTrace(map[string]interface{}{"service.name": "checkout"}, map[string]interface{}{}),
+			Conditions: []string{`resource.attributes["service.name"] == "checkout"`},
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching boolean expression",
+			Trace:      newConditionTrace(map[string]interface{}{"service.name": "checkout"}, map[string]interface{}{"http.status_code": 500}),
+			Conditions: []string{`status.code == STATUS_CODE_UNSET and resource.attributes["service.name"] == "checkout"`},
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "matching all spans",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}, map[string]interface{}{"http.status_code": 500}, map[string]interface{}{"http.status_code": 500}),
+			Conditions: []string{`attributes["http.status_code"] == 500`},
+			MatchAll:   true,
+			Decision:   Sampled,
+		},
+		{
+			Desc:       "not matching all spans",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}, map[string]interface{}{"http.status_code": 500}, map[string]interface{}{"http.status_code": 200}),
+			Conditions: []string{`attributes["http.status_code"] == 500`},
+			MatchAll:   true,
+			Decision:   NotSampled,
+		},
+		{
+			Desc:       "matching all spans without any span",
+			Trace:      newConditionTrace(map[string]interface{}{"resource": "svc"}),
+			Conditions: []string{`attributes["http.status_code"] == 500`},
+			MatchAll:   true,
+			Decision:   NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewConditionFilter(zap.NewNop(), c.Conditions, c.MatchAll)
+			require.NoError(t, err)
+			decision, err := filter.Evaluate(pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}), c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestConditionFilterNoConditions(t *testing.T) {
+	_, err := NewConditionFilter(zap.NewNop(), nil, false)
+	assert.EqualError(t, err, "expected at least one condition to filter on")
+}
+
+func TestConditionFilterInvalidCondition(t *testing.T) {
+	_, err := NewConditionFilter(zap.NewNop(), []string{`attributes["http.status_code"] ==`}, false)
+	assert.Error(t, err)
+
+	_, err = NewConditionFilter(zap.NewNop(), []string{`UnknownFunction(attributes["key"]) == true`}, false)
+	assert.Error(t, err)
+}
+
+func newConditionTrace(resourceAttributes map[string]interface{}, spanAttributes ...map[string]interface{}) *TraceData {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	pcommon.NewMapFromRaw(resourceAttributes).CopyTo(rs.Resource().Attributes())
+	ils := rs.ScopeSpans().AppendEmpty()
+	for _, attributes := range spanAttributes {
+		span := ils.Spans().AppendEmpty()
+		span.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		span.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+		pcommon.NewMapFromRaw(attributes).CopyTo(span.Attributes())
+	}
+	return &TraceData{
+		ReceivedBatches: []ptrace.Traces{traces},
+	}
+}
@@ -16,6 +16,7 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
@@ -24,13 +25,16 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -60,12 +64,20 @@ type tailSamplingSpanProcessor struct {
 	decisionBatcher idbatcher.Batcher
 	deleteChan      chan pcommon.TraceID
 	numTracesOnMap  *atomic.Uint64
+	// decisionCache keeps the decisions of the traces already removed from memory, nil if disabled.
+	decisionCache *cache.DecisionCache
+	// storageID is the storage extension persisting decisionCache, nil if not persisted.
+	storageID     *config.ComponentID
+	componentID   config.ComponentID
+	storageClient storage.Client
 }
 
 const (
 	sourceFormat = "tail_sampling"
 )
 
+var errDecisionCacheStorageWithoutSize = errors.New("the decision cache storage requires a decision cache size greater than zero")
+
 // newTracesProcessor returns a processor.TracesProcessor that will perform tail sampling according to the given
 // configuration.
 func newTracesProcessor(logger *zap.Logger, nextConsumer consumer.Traces, cfg Config) (component.TracesProcessor, error) {
@@ -73,6 +85,16 @@ func newTracesProcessor(logger *zap.Logger, nextConsumer consumer.Traces, cfg Co
 		return nil, component.ErrNilNextConsumer
 	}
 
+	var decisionCache *cache.DecisionCache
+	if cfg.DecisionCache.Size > 0 {
+		var err error
+		if decisionCache, err = cache.New(cfg.DecisionCache.Size); err != nil {
+			return nil, err
+		}
+	} else if cfg.DecisionCache.StorageID != nil {
+		return nil, errDecisionCacheStorageWithoutSize
+	}
+
 	numDecisionBatches := uint64(cfg.DecisionWait.Seconds())
 	inBatcher, err := idbatcher.New(numDecisionBatches, cfg.ExpectedNewTracesPerSec, uint64(2*runtime.NumCPU()))
 	if err != nil {
@@ -108,6 +130,9 @@ func newTracesProcessor(logger *zap.Logger, nextConsumer consumer.Traces, cfg Co
 		policies:        policies,
 		tickerFrequency: time.Second,
 		numTracesOnMap:  atomic.NewUint64(0),
+		decisionCache:   decisionCache,
+		storageID:       cfg.DecisionCache.StorageID,
+		componentID:     cfg.ID(),
 	}
 
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
@@ -150,6 +175,9 @@ func getPolicyEvaluator(logger *zap.Logger, cfg *PolicyCfg) (sampling.PolicyEval
 	case TraceState:
 		tsfCfg := cfg.TraceStateCfg
 		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values), nil
+	case Condition:
+		cfCfg := cfg.ConditionCfg
+		return sampling.NewConditionFilter(logger, cfCfg.Conditions, cfCfg.MatchAllSpans)
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
@@ -176,6 +204,9 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace.DecisionTime = time.Now()
 
 		decision, policy := tsp.makeDecision(id, trace, &metrics)
+		if tsp.decisionCache != nil {
+			tsp.decisionCache.Put(id, decision)
+		}
 
 		// Sampled or not, remove the batches
 		trace.Lock()
@@ -203,6 +234,12 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		statPolicyEvaluationErrorCount.M(metrics.evaluateErrorCount),
 		statTracesOnMemoryGauge.M(int64(tsp.numTracesOnMap.Load())))
 
+	if tsp.storageClient != nil {
+		if err := tsp.decisionCache.Flush(tsp.ctx, tsp.storageClient); err != nil {
+			tsp.logger.Warn("Failed to persist the sampling decisions", zap.Error(err))
+		}
+	}
+
 	tsp.logger.Debug("Sampling policy evaluation completed",
 		zap.Int("batch.len", batchLen),
 		zap.Int64("sampled", metrics.decisionSampled),
@@ -325,6 +362,10 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	idToSpans := tsp.groupSpansByTraceKey(resourceSpans)
 	var newTraceIDs int64
 	for id, spans := range idToSpans {
+		if tsp.applyCachedDecision(id, resourceSpans, spans) {
+			continue
+		}
+
 		lenSpans := int64(len(spans))
 		lenPolicies := len(tsp.policies)
 		initialDecisions := make([]sampling.Decision, lenPolicies)
@@ -403,21 +444,83 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
 }
 
+// applyCachedDecision handles the spans of a trace that has already been removed from memory
+// using its cached decision. It returns false if there is no such decision, in which case the
+// spans need to be processed as usual.
+func (tsp *tailSamplingSpanProcessor) applyCachedDecision(id pcommon.TraceID, resourceSpans ptrace.ResourceSpans, spans []*ptrace.Span) bool {
+	if tsp.decisionCache == nil {
+		return false
+	}
+	if _, ok := tsp.idToTrace.Load(id); ok {
+		return false
+	}
+	decision, ok := tsp.decisionCache.Get(id)
+	if !ok {
+		return false
+	}
+
+	switch decision {
+	case sampling.Sampled:
+		traceTd := prepareTraceBatch(resourceSpans, spans)
+		if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, traceTd); err != nil {
+			tsp.logger.Warn("Error sending late arrived spans to destination", zap.Error(err))
+		}
+		_ = stats.RecordWithTags(tsp.ctx,
+			[]tag.Mutator{tag.Insert(tagSampledKey, "true")},
+			statLateSpanCachedDecisionCount.M(int64(len(spans))))
+	default:
+		_ = stats.RecordWithTags(tsp.ctx,
+			[]tag.Mutator{tag.Insert(tagSampledKey, "false")},
+			statLateSpanCachedDecisionCount.M(int64(len(spans))))
+	}
+	return true
+}
+
 func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.storageID != nil {
+		client, err := getStorageClient(ctx, host, *tsp.storageID, tsp.componentID)
+		if err != nil {
+			return err
+		}
+		tsp.storageClient = client
+		if err = tsp.decisionCache.Load(ctx, client); err != nil {
+			tsp.logger.Warn("Failed to load the persisted sampling decisions", zap.Error(err))
+		}
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
-	return nil
+	if tsp.storageClient == nil {
+		return nil
+	}
+	if err := tsp.decisionCache.Flush(ctx, tsp.storageClient); err != nil {
+		tsp.logger.Warn("Failed to persist the sampling decisions", zap.Error(err))
+	}
+	return tsp.storageClient.Close(ctx)
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID config.ComponentID, componentID config.ComponentID) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindProcessor, componentID, "")
 }
 
 func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletionTime time.Time) {
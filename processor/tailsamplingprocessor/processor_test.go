@@ -24,14 +24,18 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -570,6 +574,151 @@ func TestMultipleBatchesAreCombinedIntoOne(t *testing.T) {
 	}
 }
 
+func TestLateSpansUseCachedDecision(t *testing.T) {
+	for _, decision := range []sampling.Decision{sampling.Sampled, sampling.NotSampled} {
+		const maxSize = 100
+		const decisionWaitSeconds = 1
+		msp := new(consumertest.TracesSink)
+		mpe := &mockPolicyEvaluator{NextDecision: decision}
+		decisionCache, err := cache.New(maxSize)
+		require.NoError(t, err)
+		tsp := &tailSamplingSpanProcessor{
+			ctx:             context.Background(),
+			nextConsumer:    msp,
+			maxNumTraces:    maxSize,
+			logger:          zap.NewNop(),
+			decisionBatcher: newSyncIDBatcher(decisionWaitSeconds),
+			policies:        []*policy{{name: "mock-policy", evaluator: mpe, ctx: context.TODO()}},
+			deleteChan:      make(chan pcommon.TraceID, maxSize),
+			policyTicker:    &manualTTicker{},
+			tickerFrequency: 100 * time.Millisecond,
+			numTracesOnMap:  atomic.NewUint64(0),
+			decisionCache:   decisionCache,
+		}
+		require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+
+		traceIds, batches := generateIdsAndBatches(1)
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+		tsp.samplingPolicyOnTick()
+		tsp.samplingPolicyOnTick()
+		require.Equal(t, 1, mpe.EvaluationCount)
+
+		cached, ok := decisionCache.Get(traceIds[0])
+		require.True(t, ok, "decision should have been cached")
+		require.Equal(t, decision, cached)
+
+		// Once the trace is removed from memory, late spans are handled with the cached decision
+		// instead of being buffered as a new trace.
+		tsp.dropTrace(traceIds[0], time.Now())
+		spanCount := msp.SpanCount()
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+		require.EqualValues(t, 0, tsp.numTracesOnMap.Load(), "late span should not be buffered")
+		if decision == sampling.Sampled {
+			require.Equal(t, spanCount+1, msp.SpanCount(), "late span should have been sent")
+		} else {
+			require.Equal(t, spanCount, msp.SpanCount(), "late span should not have been sent")
+		}
+
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}
+}
+
+func TestDecisionCachePersistedOnTick(t *testing.T) {
+	const maxSize = 100
+	decisionCache, err := cache.New(maxSize)
+	require.NoError(t, err)
+	client := storagetest.NewInMemoryClient(component.KindProcessor, config.NewComponentID(typeStr), "")
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    consumertest.NewNop(),
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(1),
+		policies:        []*policy{{name: "mock-policy", evaluator: &mockPolicyEvaluator{NextDecision: sampling.Sampled}, ctx: context.TODO()}},
+		deleteChan:      make(chan pcommon.TraceID, maxSize),
+		policyTicker:    &manualTTicker{},
+		tickerFrequency: 100 * time.Millisecond,
+		numTracesOnMap:  atomic.NewUint64(0),
+		decisionCache:   decisionCache,
+		storageClient:   client,
+	}
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+
+	traceIds, batches := generateIdsAndBatches(1)
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	// The decision is persisted without waiting for the shutdown, so it survives a crash.
+	restored, err := cache.New(maxSize)
+	require.NoError(t, err)
+	require.NoError(t, restored.Load(context.Background(), client))
+	decision, ok := restored.Get(traceIds[0])
+	require.True(t, ok, "decision should have been persisted")
+	require.Equal(t, sampling.Sampled, decision)
+
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
+
+func TestDecisionCachePersistedAcrossRestarts(t *testing.T) {
+	storageID := storagetest.NewStorageID("decisions")
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("decisions", t.TempDir())
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs:              testPolicy,
+		DecisionCache:           DecisionCacheCfg{Size: 10, StorageID: &storageID},
+	}
+	traceIds, batches := generateIdsAndBatches(1)
+
+	sp, err := newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	tsp := sp.(*tailSamplingSpanProcessor)
+	require.NoError(t, tsp.Start(context.Background(), host))
+	tsp.decisionCache.Put(traceIds[0], sampling.Sampled)
+	require.NoError(t, tsp.Shutdown(context.Background()))
+
+	msp := new(consumertest.TracesSink)
+	sp, err = newTracesProcessor(zap.NewNop(), msp, cfg)
+	require.NoError(t, err)
+	tsp = sp.(*tailSamplingSpanProcessor)
+	require.NoError(t, tsp.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}()
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), batches[0]))
+	require.Equal(t, 1, msp.SpanCount(), "span of a trace sampled before the restart should have been sent")
+	require.EqualValues(t, 0, tsp.numTracesOnMap.Load())
+}
+
+func TestDecisionCacheInvalidStorage(t *testing.T) {
+	storageID := storagetest.NewStorageID("missing")
+	cfg := Config{
+		DecisionWait:            defaultTestDecisionWait,
+		NumTraces:               100,
+		ExpectedNewTracesPerSec: 64,
+		PolicyCfgs:              testPolicy,
+		DecisionCache:           DecisionCacheCfg{StorageID: &storageID},
+	}
+	_, err := newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.ErrorIs(t, err, errDecisionCacheStorageWithoutSize)
+
+	cfg.DecisionCache.Size = 10
+	sp, err := newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.Error(t, sp.Start(context.Background(), storagetest.NewStorageHost()))
+	sp.(*tailSamplingSpanProcessor).decisionBatcher.Stop()
+
+	nonStorageID := storagetest.NewNonStorageID("other")
+	cfg.DecisionCache.StorageID = &nonStorageID
+	sp, err = newTracesProcessor(zap.NewNop(), consumertest.NewNop(), cfg)
+	require.NoError(t, err)
+	require.Error(t, sp.Start(context.Background(), storagetest.NewStorageHost().WithNonStorageExtension("other")))
+	sp.(*tailSamplingSpanProcessor).decisionBatcher.Stop()
+}
+
 func collectSpanIds(trace *ptrace.Traces) []pcommon.SpanID {
 	spanIDs := make([]pcommon.SpanID, 0)
 
@@ -23,6 +23,15 @@ var registry = map[string]interface{}{
 	"TraceID":              tqlotel.TraceID,
 	"SpanID":               tqlotel.SpanID,
 	"IsMatch":              tqlcommon.IsMatch,
+	"Concat":               tqlcommon.Concat,
+	"Split":                tqlcommon.Split,
+	"Int":                  tqlcommon.Int,
+	"Double":               tqlcommon.Double,
+	"String":               tqlcommon.String,
+	"Bool":                 tqlcommon.Bool,
+	"SHA256":               tqlcommon.SHA256,
+	"FNV":                  tqlcommon.FNV,
+	"ParseJSON":            tqlotel.ParseJSON,
 	"keep_keys":            tqlotel.KeepKeys,
 	"set":                  tqlcommon.Set,
 	"truncate_all":         tqlotel.TruncateAll,
@@ -33,6 +42,7 @@ var registry = map[string]interface{}{
 	"replace_all_patterns": tqlotel.ReplaceAllPatterns,
 	"delete_key":           tqlotel.DeleteKey,
 	"delete_matching_keys": tqlotel.DeleteMatchingKeys,
+	"merge_maps":           tqlotel.MergeMaps,
 }
 
 func Functions() map[string]interface{} {
@@ -68,6 +68,20 @@ func TestProcess(t *testing.T) {
 				td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(1).SetSeverityText("ok")
 			},
 		},
+		{
+			query: `merge_maps(attributes, ParseJSON("{\"json_test\":\"pass\", \"http.method\":\"post\"}"), "insert") where body == "operationA"`,
+			want: func(td plog.Logs) {
+				td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().InsertString("json_test", "pass")
+			},
+		},
+		{
+			query: `set(attributes["test"], Split(attributes["http.path"], "/")) where body == "operationA"`,
+			want: func(td plog.Logs) {
+				slice := td.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().UpsertEmptySlice("test")
+				slice.AppendEmpty().SetStringVal("")
+				slice.AppendEmpty().SetStringVal("health")
+			},
+		},
 		{
 			query: `replace_pattern(attributes["http.method"], "get", "post")`,
 			want: func(td plog.Logs) {
@@ -165,6 +165,37 @@ func TestProcess(t *testing.T) {
 				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetKind(2)
 			},
 		},
+		{
+			query: `set(attributes["duration_ms"], (end_time_unix_nano - start_time_unix_nano) / 1000000)`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().InsertInt("duration_ms", 1000)
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).Attributes().InsertInt("duration_ms", 1000)
+			},
+		},
+		{
+			query: `set(attributes["test"], "pass") where dropped_attributes_count * 2 + 1 == 3`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().InsertString("test", "pass")
+			},
+		},
+		{
+			query: `set(attributes["http.path"], SHA256(attributes["http.path"])) where name == "operationA"`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().UpdateString("http.path", "0587c50e302cd55b995100e6e49c0789939b48cd57b63503b22b8ce34544370f")
+			},
+		},
+		{
+			query: `set(attributes["test"], Int("200")) where Bool(dropped_attributes_count) == true`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().InsertInt("test", 200)
+			},
+		},
+		{
+			query: `set(attributes["test"], Concat(" ", attributes["http.method"], attributes["http.path"])) where name == "operationA"`,
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().InsertString("test", "get /health")
+			},
+		},
 	}
 
 	for _, tt := range tests {
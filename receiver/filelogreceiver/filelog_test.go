@@ -129,6 +129,62 @@ func TestReadStaticFile(t *testing.T) {
 	require.NoError(t, rcvr.Shutdown(context.Background()))
 }
 
+func TestReadFileWithHeader(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "header.yaml"))
+	require.NoError(t, err)
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*FileLogConfig)
+	sub, err := cm.Sub(config.NewComponentID(typeStr).String())
+	require.NoError(t, err)
+	require.NoError(t, config.UnmarshalReceiver(sub, cfg))
+	cfg.Converter.MaxFlushCount = 1
+	cfg.Converter.FlushInterval = time.Millisecond
+
+	sink := new(consumertest.LogsSink)
+	rcvr, err := f.CreateLogsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, err, "failed to create receiver")
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcvr.Shutdown(context.Background()))
+	}()
+
+	require.Eventually(t, expectNLogs(sink, 2), 2*time.Second, 5*time.Millisecond,
+		"expected %d but got %d logs",
+		2, sink.LogRecordCount(),
+	)
+
+	attrs := make([]map[string]interface{}, 0, 2)
+	for _, logs := range sink.AllLogs() {
+		records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			attrs = append(attrs, records.At(i).Attributes().AsRaw())
+		}
+	}
+	const fields = "date time cs-method cs-uri-stem sc-status"
+	assert.ElementsMatch(t, []map[string]interface{}{
+		{
+			"log.file.name": "w3c.log",
+			"fields":        fields,
+			"date":          "2022-10-01",
+			"time":          "00:00:01",
+			"cs-method":     "GET",
+			"cs-uri-stem":   "/index.html",
+			"sc-status":     "200",
+		},
+		{
+			"log.file.name": "w3c.log",
+			"fields":        fields,
+			"date":          "2022-10-01",
+			"time":          "00:00:02",
+			"cs-method":     "POST",
+			"cs-uri-stem":   "/login",
+			"sc-status":     "302",
+		},
+	}, attrs)
+}
+
 func TestReadRotatingFiles(t *testing.T) {
 
 	tests := []rotationTest{
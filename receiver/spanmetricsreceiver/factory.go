@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/spanmetricsreceiver"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "spanmetrics"
+	// The stability level of the receiver.
+	stability = component.StabilityLevelInDevelopment
+)
+
+// factory creates the spanmetrics receivers and keeps track of them, so that the spanmetrics
+// processor finds the receiver it feeds through the factory returned by its component.Host.
+// Each collector has its own factory, so the receivers of different collectors don't mix.
+type factory struct {
+	component.ReceiverFactory
+
+	mu        sync.Mutex
+	receivers map[config.ComponentID]*metricsReceiver
+}
+
+// NewFactory creates a factory for the spanmetrics receiver.
+func NewFactory() component.ReceiverFactory {
+	f := &factory{receivers: make(map[config.ComponentID]*metricsReceiver)}
+	f.ReceiverFactory = component.NewReceiverFactory(
+		typeStr,
+		createDefaultConfig,
+		component.WithMetricsReceiver(f.createMetricsReceiver, stability),
+	)
+	return f
+}
+
+func createDefaultConfig() config.Receiver {
+	return &Config{
+		ReceiverSettings: config.NewReceiverSettings(config.NewComponentID(typeStr)),
+	}
+}
+
+func (f *factory) createMetricsReceiver(_ context.Context, _ component.ReceiverCreateSettings, cfg config.Receiver, nextConsumer consumer.Metrics) (component.MetricsReceiver, error) {
+	r := &metricsReceiver{id: cfg.ID(), nextConsumer: nextConsumer, factory: f}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// A receiver created for a new configuration replaces the previous one with the same ID.
+	f.receivers[r.id] = r
+	return r, nil
+}
+
+// MetricsConsumer returns the next consumer of the spanmetrics receiver with the given ID
+// created by this factory. The receiver is available from its creation until its shutdown,
+// so the spanmetrics processor finds it when started, even though receivers are started last.
+func (f *factory) MetricsConsumer(id config.ComponentID) (consumer.Metrics, error) {
+	if id.Type() != typeStr {
+		return nil, fmt.Errorf("%q isn't a %s receiver", id.String(), typeStr)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.receivers[id]
+	if !ok {
+		return nil, fmt.Errorf("the %s receiver %q isn't configured; please add it to a metrics pipeline", typeStr, id.String())
+	}
+	return r.nextConsumer, nil
+}
+
+func (f *factory) remove(r *metricsReceiver) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.receivers[r.id] == r {
+		delete(f.receivers, r.id)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/spanmetricsreceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+)
+
+// metricsReceiver makes the next consumer of its metrics pipelines available to the spanmetrics
+// processors, through the factory that created it.
+type metricsReceiver struct {
+	id           config.ComponentID
+	nextConsumer consumer.Metrics
+	factory      *factory
+}
+
+// Start implements the component.Component interface.
+func (r *metricsReceiver) Start(context.Context, component.Host) error {
+	return nil
+}
+
+// Shutdown implements the component.Component interface.
+func (r *metricsReceiver) Shutdown(context.Context) error {
+	r.factory.remove(r)
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestMetricsReceiverLifecycle(t *testing.T) {
+	// Prepare
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	cfg.SetIDName("lifecycle")
+	sink := new(consumertest.MetricsSink)
+
+	_, err := f.(*factory).MetricsConsumer(cfg.ID())
+	assert.Error(t, err, "Must not be available before creation")
+
+	// Test
+	rcvr, err := f.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	next, err := f.(*factory).MetricsConsumer(cfg.ID())
+	require.NoError(t, err, "Must be available once created, before being started")
+	assert.Equal(t, sink, next)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	next, err = f.(*factory).MetricsConsumer(cfg.ID())
+	require.NoError(t, err)
+	assert.Equal(t, sink, next)
+
+	// Verify
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	_, err = f.(*factory).MetricsConsumer(cfg.ID())
+	assert.Error(t, err, "Must not be available after shutdown")
+}
+
+func TestMetricsReceiverReplaced(t *testing.T) {
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig()
+	cfg.SetIDName("replaced")
+
+	previous, err := f.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	sink := new(consumertest.MetricsSink)
+	current, err := f.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, previous.Shutdown(context.Background()))
+	next, err := f.(*factory).MetricsConsumer(cfg.ID())
+	require.NoError(t, err, "Must not be removed by the shutdown of the receiver it replaced")
+	assert.Equal(t, sink, next)
+
+	require.NoError(t, current.Shutdown(context.Background()))
+	_, err = f.(*factory).MetricsConsumer(cfg.ID())
+	assert.Error(t, err)
+}
+
+func TestMetricsConsumerInvalidID(t *testing.T) {
+	_, err := NewFactory().(*factory).MetricsConsumer(config.NewComponentID("otlp"))
+	assert.Error(t, err)
+}
+
+func TestMetricsReceiverPerFactory(t *testing.T) {
+	// Each collector has its own factories, so the receivers of a collector aren't visible to the others.
+	first, second := NewFactory(), NewFactory()
+	cfg := first.CreateDefaultConfig()
+	sink := new(consumertest.MetricsSink)
+
+	rcvr, err := first.CreateMetricsReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, rcvr.Shutdown(context.Background())) }()
+
+	next, err := first.(*factory).MetricsConsumer(cfg.ID())
+	require.NoError(t, err)
+	assert.Equal(t, sink, next)
+	_, err = second.(*factory).MetricsConsumer(cfg.ID())
+	assert.Error(t, err)
+}